@@ -0,0 +1,82 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"errors"
+	"flag"
+	"strings"
+)
+
+// Credentials holds a user name and password, for instance for HTTP basic
+// authentication.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// String returns the credentials as "user:***", so that the password is not
+// leaked when the credentials are printed.
+func (c Credentials) String() string {
+	if c.Password == "" {
+		return c.Username
+	}
+	return c.Username + ":***"
+}
+
+// BasicAuth defines a credentials flag with specified name, default value,
+// and usage string. The value is provided via the command line as
+// "user:password". The return value is the address of a Credentials variable
+// that stores the value of the flag.
+func BasicAuth(name string, value Credentials, usage string, opts ...Option) *Credentials {
+	var c Credentials
+	BasicAuthVar(&c, name, value, usage, opts...)
+	return &c
+}
+
+// BasicAuthVar defines a credentials flag with specified name, default
+// value, and usage string. The argument p points to a Credentials variable
+// in which to store the value of the flag.
+func BasicAuthVar(p *Credentials, name string, value Credentials, usage string, opts ...Option) {
+	BasicAuthVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// BasicAuthVarFS is like BasicAuthVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func BasicAuthVarFS(fs *flag.FlagSet, p *Credentials, name string, value Credentials, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*BasicAuthValue)(p), name, usage, opts)
+}
+
+// BasicAuthValue holds credentials that can be provided via the command line
+// as "user:password". The password is masked when the value is printed, for
+// instance in usage messages.
+type BasicAuthValue Credentials
+
+// String implements flag.Value by returning the credentials with the
+// password masked.
+func (c *BasicAuthValue) String() string {
+	return Credentials(*c).String()
+}
+
+// Set implements flag.Value by parsing the given "user:password" value. The
+// value is not included in returned errors, as it may contain a password.
+func (c *BasicAuthValue) Set(value string) error {
+	username, password, ok := strings.Cut(value, ":")
+	if !ok {
+		return errors.New(`invalid credentials: expected "user:password"`)
+	}
+	if username == "" {
+		return errors.New("invalid credentials: empty user name")
+	}
+	*c = BasicAuthValue{
+		Username: username,
+		Password: password,
+	}
+	return nil
+}
+
+// Type implements pflag.Value by returning "basicAuth".
+func (c *BasicAuthValue) Type() string {
+	return "basicAuth"
+}
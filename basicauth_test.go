@@ -0,0 +1,146 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.BasicAuthValue)(nil)
+
+var basicAuthTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.Credentials
+	expectedValue       flagutils.Credentials
+	expectedStringValue string
+	expectedError       string
+}{{
+	about: "user and password",
+	name:  "auth",
+	value: "admin:s3cr3t",
+	expectedValue: flagutils.Credentials{
+		Username: "admin",
+		Password: "s3cr3t",
+	},
+	expectedStringValue: "admin:***",
+}, {
+	about: "password including colons",
+	name:  "colons",
+	value: "admin:bad:wolf",
+	expectedValue: flagutils.Credentials{
+		Username: "admin",
+		Password: "bad:wolf",
+	},
+	expectedStringValue: "admin:***",
+}, {
+	about: "empty password",
+	name:  "empty",
+	value: "admin:",
+	expectedValue: flagutils.Credentials{
+		Username: "admin",
+	},
+	expectedStringValue: "admin",
+}, {
+	about: "default value: with value",
+	name:  "def1",
+	value: "rose:tyler",
+	defaultValue: flagutils.Credentials{
+		Username: "admin",
+		Password: "admin",
+	},
+	expectedValue: flagutils.Credentials{
+		Username: "rose",
+		Password: "tyler",
+	},
+}, {
+	about: "default value: without value",
+	name:  "def2",
+	defaultValue: flagutils.Credentials{
+		Username: "admin",
+		Password: "admin",
+	},
+	expectedValue: flagutils.Credentials{
+		Username: "admin",
+		Password: "admin",
+	},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid credentials: expected "user:password"`,
+}, {
+	about:         "error: missing password",
+	name:          "err",
+	value:         "admin",
+	expectedError: `invalid credentials: expected "user:password"`,
+}, {
+	about:         "error: empty user name",
+	name:          "err",
+	value:         ":s3cr3t",
+	expectedError: "invalid credentials: empty user name",
+}}
+
+func TestBasicAuth(t *testing.T) {
+	for _, test := range basicAuthTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.BasicAuth(test.name, test.defaultValue, "basic auth usage")
+			if test.value != "" || test.defaultValue.Username == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestBasicAuthVar(t *testing.T) {
+	for _, test := range basicAuthTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.Credentials
+			flagutils.BasicAuthVar(&v, test.name, test.defaultValue, "basic auth usage")
+			if test.value != "" || test.defaultValue.Username == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestBasicAuthValueString(t *testing.T) {
+	for _, test := range basicAuthTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue.Username != "" || test.expectedError != "" {
+				return
+			}
+			var v flagutils.BasicAuthValue
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestBasicAuthDefaultValueMasked(t *testing.T) {
+	runIsolated(t, "default value masked", func(c *qt.C) {
+		flagutils.BasicAuth("auth", flagutils.Credentials{
+			Username: "admin",
+			Password: "s3cr3t",
+		}, "basic auth usage")
+		c.Assert(flag.Lookup("auth").DefValue, qt.Equals, "admin:***")
+	})
+}
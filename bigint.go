@@ -0,0 +1,69 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// BigInt defines an arbitrary precision integer flag with specified name,
+// default value, and usage string. The value can be provided via the command
+// line in decimal form, or in hexadecimal, octal or binary form using the
+// "0x", "0o" and "0b" prefixes. Leading zeros do not select the octal form,
+// so that "0100" is 100, and underscores are not allowed. The return value is the address of a big.Int
+// variable that stores the value of the flag.
+func BigInt(name string, value *big.Int, usage string, opts ...Option) *big.Int {
+	n := new(big.Int)
+	BigIntVar(n, name, value, usage, opts...)
+	return n
+}
+
+// BigIntVar defines an arbitrary precision integer flag with specified name,
+// default value, and usage string. See BigInt for details on the accepted
+// values. The argument p points to a big.Int variable in which to store the
+// value of the flag. A nil default value is treated as zero.
+func BigIntVar(p *big.Int, name string, value *big.Int, usage string, opts ...Option) {
+	BigIntVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// BigIntVarFS is like BigIntVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func BigIntVarFS(fs *flag.FlagSet, p *big.Int, name string, value *big.Int, usage string, opts ...Option) {
+	if value != nil {
+		p.Set(value)
+	}
+	defineVar(fs, (*BigIntValue)(p), name, usage, opts)
+}
+
+// BigIntValue holds an arbitrary precision integer that can be provided via
+// the command line.
+type BigIntValue big.Int
+
+// String implements flag.Value by returning the integer in decimal form.
+func (n *BigIntValue) String() string {
+	return (*big.Int)(n).String()
+}
+
+// Set implements flag.Value by parsing the given integer. The value is
+// parsed in base 10 unless it has a "0x", "0o" or "0b" prefix.
+func (n *BigIntValue) Set(value string) error {
+	base := 10
+	digits := strings.TrimLeft(value, "+-")
+	if len(digits) > 2 && digits[0] == '0' && strings.ContainsRune("xXoObB", rune(digits[1])) {
+		base = 0
+	}
+	v, ok := new(big.Int).SetString(value, base)
+	if !ok || strings.Contains(value, "_") {
+		return fmt.Errorf("invalid integer value %q", value)
+	}
+	(*big.Int)(n).Set(v)
+	return nil
+}
+
+// Type implements pflag.Value by returning "bigInt".
+func (n *BigIntValue) Type() string {
+	return "bigInt"
+}
@@ -0,0 +1,169 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"math/big"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.BigIntValue)(nil)
+
+var bigIntTests = []struct {
+	about         string
+	name          string
+	value         string
+	defaultValue  *big.Int
+	expectedValue string
+	expectedError string
+}{{
+	about:         "decimal",
+	name:          "decimal",
+	value:         "123456789012345678901234567890",
+	expectedValue: "123456789012345678901234567890",
+}, {
+	about:         "negative",
+	name:          "negative",
+	value:         "-42",
+	expectedValue: "-42",
+}, {
+	about:         "hexadecimal",
+	name:          "hex",
+	value:         "0xffffffffffffffffffff",
+	expectedValue: "1208925819614629174706175",
+}, {
+	about:         "binary",
+	name:          "binary",
+	value:         "0b1010",
+	expectedValue: "10",
+}, {
+	about:         "octal",
+	name:          "octal",
+	value:         "0o100",
+	expectedValue: "64",
+}, {
+	about:         "uppercase prefix",
+	name:          "upper",
+	value:         "-0XFF",
+	expectedValue: "-255",
+}, {
+	about:         "leading zero",
+	name:          "zero",
+	value:         "0100",
+	expectedValue: "100",
+}, {
+	about:         "zero",
+	name:          "zero",
+	value:         "0",
+	expectedValue: "0",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "1",
+	defaultValue:  big.NewInt(42),
+	expectedValue: "1",
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  big.NewInt(42),
+	expectedValue: "42",
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedValue: "0",
+	expectedError: `invalid integer value ""`,
+}, {
+	about:         "error: float",
+	name:          "err",
+	value:         "1.5",
+	expectedValue: "0",
+	expectedError: `invalid integer value "1.5"`,
+}, {
+	about:         "error: invalid hexadecimal",
+	name:          "err",
+	value:         "0xbadwolf",
+	expectedValue: "0",
+	expectedError: `invalid integer value "0xbadwolf"`,
+}, {
+	about:         "error: underscores",
+	name:          "err",
+	value:         "1_000",
+	expectedValue: "0",
+	expectedError: `invalid integer value "1_000"`,
+}, {
+	about:         "error: underscores with prefix",
+	name:          "err",
+	value:         "0x_ff",
+	expectedValue: "0",
+	expectedError: `invalid integer value "0x_ff"`,
+}, {
+	about:         "error: invalid binary",
+	name:          "err",
+	value:         "0b102",
+	expectedValue: "0",
+	expectedError: `invalid integer value "0b102"`,
+}, {
+	about:         "error: prefix without digits",
+	name:          "err",
+	value:         "0o",
+	expectedValue: "0",
+	expectedError: `invalid integer value "0o"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "forty-two",
+	defaultValue:  big.NewInt(42),
+	expectedValue: "42",
+	expectedError: `invalid integer value "forty-two"`,
+}}
+
+func TestBigInt(t *testing.T) {
+	for _, test := range bigIntTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.BigInt(test.name, test.defaultValue, "big int usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v.String(), qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestBigIntVar(t *testing.T) {
+	for _, test := range bigIntTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v big.Int
+			flagutils.BigIntVar(&v, test.name, test.defaultValue, "big int usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v.String(), qt.Equals, test.expectedValue)
+			c.Assert(flag.Lookup(test.name).Value.String(), qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestBigIntDefaultValueNotShared(t *testing.T) {
+	runIsolated(t, "default value not shared", func(c *qt.C) {
+		def := big.NewInt(42)
+		v := flagutils.BigInt("n", def, "big int usage")
+		c.Assert(flag.Set("n", "1"), qt.Equals, nil)
+		c.Assert(v.Int64(), qt.Equals, int64(1))
+		c.Assert(def.Int64(), qt.Equals, int64(42))
+	})
+}
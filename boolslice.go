@@ -0,0 +1,87 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BoolSlice defines a bool slice flag with specified name, default value, and
+// usage string. The return value is the address of a bool slice variable that
+// stores the value of the flag.
+func BoolSlice(name string, value []bool, usage string, opts ...Option) *[]bool {
+	var s []bool
+	BoolSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// BoolSliceVar defines a bool slice flag with specified name, default value,
+// and usage string. The argument p points to a bool slice variable in which to
+// store the value of the flag.
+func BoolSliceVar(p *[]bool, name string, value []bool, usage string, opts ...Option) {
+	BoolSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// BoolSliceVarFS is like BoolSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func BoolSliceVarFS(fs *flag.FlagSet, p *[]bool, name string, value []bool, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*BoolSliceValue)(p), name, usage, opts)
+}
+
+// BoolSliceValue holds a slice of booleans that can be provided via the
+// command line as a comma separated list of values. Each element can be
+// expressed as true/false, yes/no, on/off or 1/0.
+type BoolSliceValue []bool
+
+// String implements flag.Value by returning the slice as a string.
+func (s *BoolSliceValue) String() string {
+	values := make([]string, len(*s))
+	for i, b := range *s {
+		values[i] = strconv.FormatBool(b)
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *BoolSliceValue) Set(value string) error {
+	*s = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	bs := make([]bool, len(values))
+	for i, v := range values {
+		if bs[i], err = parseBool(v); err != nil {
+			return err
+		}
+	}
+	*s = bs
+	return nil
+}
+
+// Type implements pflag.Value by returning "boolSlice".
+func (s *BoolSliceValue) Type() string {
+	return "boolSlice"
+}
+
+// parseBool returns the boolean value represented by the given string.
+// In addition to the values accepted by strconv.ParseBool, the yes/no and
+// on/off forms are accepted, case insensitively.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean value %q", value)
+	}
+	return b, nil
+}
@@ -0,0 +1,110 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.BoolSliceValue)(nil)
+
+var boolSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []bool
+	expectedValue       []bool
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single value",
+	name:                "single",
+	value:               "true",
+	expectedValue:       []bool{true},
+	expectedStringValue: "true",
+}, {
+	about:               "multiple values",
+	name:                "multiple",
+	value:               "true,false,1,0,yes,no,on,off",
+	expectedValue:       []bool{true, false, true, false, true, false, true, false},
+	expectedStringValue: "true,false,true,false,true,false,true,false",
+}, {
+	about:               "weird formatting",
+	name:                "weird",
+	value:               "  YES , Off,t,  F ",
+	expectedValue:       []bool{true, false, true, false},
+	expectedStringValue: "true,false,true,false",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "on",
+	defaultValue:  []bool{false, false},
+	expectedValue: []bool{true},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []bool{true, false},
+	expectedValue: []bool{true, false},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: invalid boolean",
+	name:          "err",
+	value:         "on,maybe,off",
+	expectedError: `invalid boolean value "maybe"`,
+}}
+
+func TestBoolSlice(t *testing.T) {
+	for _, test := range boolSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.BoolSlice(test.name, test.defaultValue, "bool slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestBoolSliceVar(t *testing.T) {
+	for _, test := range boolSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []bool
+			flagutils.BoolSliceVar(&v, test.name, test.defaultValue, "bool slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestBoolSliceValueString(t *testing.T) {
+	for _, test := range boolSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.BoolSliceValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
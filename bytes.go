@@ -0,0 +1,70 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"encoding/base64"
+	"flag"
+	"fmt"
+)
+
+// Bytes defines a byte slice flag with specified name, default value, and
+// usage string. The value is provided via the command line as a base64
+// encoded string, using either the standard or the URL safe alphabet, with
+// or without padding. The return value is the address of a byte slice
+// variable that stores the decoded value of the flag.
+func Bytes(name string, value []byte, usage string, opts ...Option) *[]byte {
+	var b []byte
+	BytesVar(&b, name, value, usage, opts...)
+	return &b
+}
+
+// BytesVar defines a byte slice flag with specified name, default value, and
+// usage string. See Bytes for details on the accepted encodings. The argument
+// p points to a byte slice variable in which to store the decoded value of
+// the flag.
+func BytesVar(p *[]byte, name string, value []byte, usage string, opts ...Option) {
+	BytesVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// BytesVarFS is like BytesVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func BytesVarFS(fs *flag.FlagSet, p *[]byte, name string, value []byte, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*BytesValue)(p), name, usage, opts)
+}
+
+// BytesValue holds a byte slice that can be provided via the command line as
+// a base64 encoded string.
+type BytesValue []byte
+
+// base64Encodings holds the encodings tried, in order, when decoding a
+// BytesValue.
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.URLEncoding,
+	base64.RawStdEncoding,
+	base64.RawURLEncoding,
+}
+
+// String implements flag.Value by returning the bytes encoded using standard
+// base64 encoding.
+func (b *BytesValue) String() string {
+	return base64.StdEncoding.EncodeToString(*b)
+}
+
+// Set implements flag.Value by decoding the given base64 value.
+func (b *BytesValue) Set(value string) error {
+	for _, enc := range base64Encodings {
+		if decoded, err := enc.DecodeString(value); err == nil {
+			*b = decoded
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid base64 value %q", value)
+}
+
+// Type implements pflag.Value by returning "bytesBase64".
+func (b *BytesValue) Type() string {
+	return "bytesBase64"
+}
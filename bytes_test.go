@@ -0,0 +1,125 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.BytesValue)(nil)
+
+var bytesTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []byte
+	expectedValue       []byte
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "standard encoding",
+	name:                "std",
+	value:               "YmFkIHdvbGY/Pz8=",
+	expectedValue:       []byte("bad wolf???"),
+	expectedStringValue: "YmFkIHdvbGY/Pz8=",
+}, {
+	about:               "URL safe encoding",
+	name:                "url",
+	value:               "YmFkIHdvbGY_Pz8=",
+	expectedValue:       []byte("bad wolf???"),
+	expectedStringValue: "YmFkIHdvbGY/Pz8=",
+}, {
+	about:               "standard encoding without padding",
+	name:                "rawstd",
+	value:               "YmFkIHdvbGY/Pz8",
+	expectedValue:       []byte("bad wolf???"),
+	expectedStringValue: "YmFkIHdvbGY/Pz8=",
+}, {
+	about:               "URL safe encoding without padding",
+	name:                "rawurl",
+	value:               "YmFkIHdvbGY_Pz8",
+	expectedValue:       []byte("bad wolf???"),
+	expectedStringValue: "YmFkIHdvbGY/Pz8=",
+}, {
+	about:               "empty string",
+	name:                "empty",
+	expectedValue:       []byte{},
+	expectedStringValue: "",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "cm9zZQ==",
+	defaultValue:  []byte("wolf"),
+	expectedValue: []byte("rose"),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []byte("wolf"),
+	expectedValue: []byte("wolf"),
+}, {
+	about:         "error: invalid characters",
+	name:          "err",
+	value:         "bad wolf",
+	expectedError: `invalid base64 value "bad wolf"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "cm9zZQ=",
+	defaultValue:  []byte("wolf"),
+	expectedValue: []byte("wolf"),
+	expectedError: `invalid base64 value "cm9zZQ="`,
+}}
+
+func TestBytes(t *testing.T) {
+	for _, test := range bytesTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Bytes(test.name, test.defaultValue, "bytes usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestBytesVar(t *testing.T) {
+	for _, test := range bytesTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []byte
+			flagutils.BytesVar(&v, test.name, test.defaultValue, "bytes usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestBytesValueString(t *testing.T) {
+	for _, test := range bytesTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil || test.expectedError != "" {
+				return
+			}
+			var v flagutils.BytesValue
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import "flag"
+
+// ByteSize defines a byte size flag with specified name, default value, and
+// usage string. The return value is the address of an int64 variable that
+// stores the value of the flag, in bytes.
+func ByteSize(name string, value int64, usage string, opts ...Option) *int64 {
+	var n int64
+	ByteSizeVar(&n, name, value, usage, opts...)
+	return &n
+}
+
+// ByteSizeVar defines a byte size flag with specified name, default value,
+// and usage string. The argument p points to an int64 variable in which to
+// store the value of the flag, in bytes.
+func ByteSizeVar(p *int64, name string, value int64, usage string, opts ...Option) {
+	ByteSizeVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// ByteSizeVarFS is like ByteSizeVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func ByteSizeVarFS(fs *flag.FlagSet, p *int64, name string, value int64, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*ByteSizeValue)(p), name, usage, opts)
+}
+
+// ByteSizeValue holds a size in bytes that can be provided via the command
+// line in human readable form, for instance "512", "64KiB" or "1.5GB". Both
+// SI (kB, MB, GB...) and IEC (KiB, MiB, GiB...) suffixes are supported.
+type ByteSizeValue int64
+
+// String implements flag.Value by returning the size in human readable form.
+func (n *ByteSizeValue) String() string {
+	return formatByteSize(int64(*n))
+}
+
+// Set implements flag.Value by parsing the given human readable size.
+func (n *ByteSizeValue) Set(value string) error {
+	size, err := parseByteSize(value)
+	if err != nil {
+		return err
+	}
+	*n = ByteSizeValue(size)
+	return nil
+}
+
+// Type implements pflag.Value by returning "byteSize".
+func (n *ByteSizeValue) Type() string {
+	return "byteSize"
+}
@@ -0,0 +1,129 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.ByteSizeValue)(nil)
+
+var byteSizeTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        int64
+	expectedValue       int64
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "bytes",
+	name:                "bytes",
+	value:               "512",
+	expectedValue:       512,
+	expectedStringValue: "512B",
+}, {
+	about:               "IEC suffix",
+	name:                "iec",
+	value:               "64KiB",
+	expectedValue:       64 << 10,
+	expectedStringValue: "64KiB",
+}, {
+	about:               "SI suffix with fraction",
+	name:                "si",
+	value:               "1.5GB",
+	expectedValue:       1500000000,
+	expectedStringValue: "1500MB",
+}, {
+	about:               "suffix without trailing B",
+	name:                "short",
+	value:               "2Mi",
+	expectedValue:       2 << 20,
+	expectedStringValue: "2MiB",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "1KiB",
+	defaultValue:  4096,
+	expectedValue: 1024,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  4096,
+	expectedValue: 4096,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid byte size ""`,
+}, {
+	about:         "error: negative",
+	name:          "err",
+	value:         "-1KB",
+	expectedError: `invalid byte size "-1KB"`,
+}, {
+	about:         "error: unknown suffix",
+	name:          "err",
+	value:         "42XB",
+	expectedError: `invalid byte size "42XB"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "0.5B",
+	defaultValue:  4096,
+	expectedValue: 4096,
+	expectedError: `invalid byte size "0.5B"`,
+}}
+
+func TestByteSize(t *testing.T) {
+	for _, test := range byteSizeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.ByteSize(test.name, test.defaultValue, "byte size usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestByteSizeVar(t *testing.T) {
+	for _, test := range byteSizeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v int64
+			flagutils.ByteSizeVar(&v, test.name, test.defaultValue, "byte size usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestByteSizeValueString(t *testing.T) {
+	for _, test := range byteSizeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != 0 || test.expectedError != "" {
+				return
+			}
+			var v flagutils.ByteSizeValue
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
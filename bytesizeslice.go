@@ -0,0 +1,152 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ByteSizeSlice defines a byte size slice flag with specified name, default
+// value, and usage string. The return value is the address of an int64 slice
+// variable that stores the value of the flag, in bytes.
+func ByteSizeSlice(name string, value []int64, usage string, opts ...Option) *[]int64 {
+	var s []int64
+	ByteSizeSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// ByteSizeSliceVar defines a byte size slice flag with specified name,
+// default value, and usage string. The argument p points to an int64 slice
+// variable in which to store the value of the flag, in bytes.
+func ByteSizeSliceVar(p *[]int64, name string, value []int64, usage string, opts ...Option) {
+	ByteSizeSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// ByteSizeSliceVarFS is like ByteSizeSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func ByteSizeSliceVarFS(fs *flag.FlagSet, p *[]int64, name string, value []int64, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*ByteSizeSliceValue)(p), name, usage, opts)
+}
+
+// ByteSizeSliceValue holds a slice of sizes in bytes that can be provided via
+// the command line as a comma separated list of human readable sizes, for
+// instance "512,4KiB,1.5MB". Both SI (kB, MB, GB...) and IEC (KiB, MiB,
+// GiB...) suffixes are supported.
+type ByteSizeSliceValue []int64
+
+// String implements flag.Value by returning the slice as a string.
+func (s *ByteSizeSliceValue) String() string {
+	values := make([]string, len(*s))
+	for i, size := range *s {
+		values[i] = formatByteSize(size)
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *ByteSizeSliceValue) Set(value string) error {
+	*s = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	sizes := make([]int64, len(values))
+	for i, v := range values {
+		if sizes[i], err = parseByteSize(v); err != nil {
+			return err
+		}
+	}
+	*s = sizes
+	return nil
+}
+
+// Type implements pflag.Value by returning "byteSizeSlice".
+func (s *ByteSizeSliceValue) Type() string {
+	return "byteSizeSlice"
+}
+
+// byteSizeUnit associates a byte size suffix with its multiplier.
+type byteSizeUnit struct {
+	suffix     string
+	multiplier int64
+}
+
+// iecUnits and siUnits hold the supported byte size units, from the biggest
+// to the smallest. Suffixes are matched case insensitively.
+var (
+	iecUnits = []byteSizeUnit{
+		{"EiB", 1 << 60},
+		{"PiB", 1 << 50},
+		{"TiB", 1 << 40},
+		{"GiB", 1 << 30},
+		{"MiB", 1 << 20},
+		{"KiB", 1 << 10},
+	}
+	siUnits = []byteSizeUnit{
+		{"EB", 1e18},
+		{"PB", 1e15},
+		{"TB", 1e12},
+		{"GB", 1e9},
+		{"MB", 1e6},
+		{"kB", 1e3},
+	}
+)
+
+// parseByteSize parses the given human readable size, for instance "1.5GB"
+// or "64KiB", and returns the corresponding number of bytes. The trailing
+// "B" can be omitted from suffixes, so that "64Ki" and "1.5G" are also valid.
+func parseByteSize(value string) (int64, error) {
+	number, multiplier := splitByteSizeSuffix(strings.TrimSpace(value))
+	number = strings.TrimSpace(number)
+	if number == "" || strings.HasPrefix(number, "-") || strings.HasPrefix(number, "+") {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+	r, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(multiplier))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+	return r.Num().Int64(), nil
+}
+
+// splitByteSizeSuffix splits the given size into its numeric part and the
+// multiplier corresponding to its unit suffix.
+func splitByteSizeSuffix(value string) (number string, multiplier int64) {
+	lower := strings.ToLower(value)
+	for _, units := range [][]byteSizeUnit{iecUnits, siUnits} {
+		for _, u := range units {
+			suffix := strings.ToLower(u.suffix)
+			for _, suffix := range []string{suffix, strings.TrimSuffix(suffix, "b")} {
+				if strings.HasSuffix(lower, suffix) {
+					return value[:len(value)-len(suffix)], u.multiplier
+				}
+			}
+		}
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(value, "B"), "b"), 1
+}
+
+// formatByteSize returns a human readable representation of the given
+// number of bytes, using the biggest unit that represents it exactly.
+func formatByteSize(size int64) string {
+	unit := byteSizeUnit{suffix: "B", multiplier: 1}
+	if size != 0 {
+		for _, units := range [][]byteSizeUnit{iecUnits, siUnits} {
+			for _, u := range units {
+				if size%u.multiplier == 0 && u.multiplier > unit.multiplier {
+					unit = u
+				}
+			}
+		}
+	}
+	return strconv.FormatInt(size/unit.multiplier, 10) + unit.suffix
+}
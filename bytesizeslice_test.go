@@ -0,0 +1,137 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.ByteSizeSliceValue)(nil)
+
+var byteSizeSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []int64
+	expectedValue       []int64
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single size",
+	name:                "single",
+	value:               "4KiB",
+	expectedValue:       []int64{4096},
+	expectedStringValue: "4KiB",
+}, {
+	about:               "IEC suffixes",
+	name:                "iec",
+	value:               "1KiB,2MiB,3GiB,4TiB,5Pi,6Ei",
+	expectedValue:       []int64{1 << 10, 2 << 20, 3 << 30, 4 << 40, 5 << 50, 6 << 60},
+	expectedStringValue: "1KiB,2MiB,3GiB,4TiB,5PiB,6EiB",
+}, {
+	about:               "SI suffixes",
+	name:                "si",
+	value:               "1kB,2MB,3GB,4TB,5P,6E",
+	expectedValue:       []int64{1e3, 2e6, 3e9, 4e12, 5e15, 6e18},
+	expectedStringValue: "1kB,2MB,3GB,4TB,5PB,6EB",
+}, {
+	about:               "bytes",
+	name:                "bytes",
+	value:               "0,512,1500B",
+	expectedValue:       []int64{0, 512, 1500},
+	expectedStringValue: "0B,512B,1500B",
+}, {
+	about:               "decimals and weird formatting",
+	name:                "weird",
+	value:               " 1.5 GB, 0.5kib ,16mib",
+	expectedValue:       []int64{1.5e9, 512, 16 << 20},
+	expectedStringValue: "1500MB,512B,16MiB",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "1MiB",
+	defaultValue:  []int64{4096},
+	expectedValue: []int64{1 << 20},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []int64{4096},
+	expectedValue: []int64{4096},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: unknown suffix",
+	name:          "err",
+	value:         "4KiB,42XB",
+	expectedError: `invalid byte size "42XB"`,
+}, {
+	about:         "error: negative size",
+	name:          "err",
+	value:         "-1MiB",
+	expectedError: `invalid byte size "-1MiB"`,
+}, {
+	about:         "error: fractional bytes",
+	name:          "err",
+	value:         "1.5",
+	expectedError: `invalid byte size "1.5"`,
+}, {
+	about:         "error: overflow",
+	name:          "err",
+	value:         "8EiB",
+	expectedError: `invalid byte size "8EiB"`,
+}}
+
+func TestByteSizeSlice(t *testing.T) {
+	for _, test := range byteSizeSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.ByteSizeSlice(test.name, test.defaultValue, "byte size slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestByteSizeSliceVar(t *testing.T) {
+	for _, test := range byteSizeSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []int64
+			flagutils.ByteSizeSliceVar(&v, test.name, test.defaultValue, "byte size slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestByteSizeSliceValueString(t *testing.T) {
+	for _, test := range byteSizeSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.ByteSizeSliceValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net"
+)
+
+// CIDR defines a CIDR flag with specified name, default value, and usage
+// string. When normalize is true, the host bits of the provided address are
+// cleared, so that "10.1.2.3/8" is stored as "10.0.0.0/8". The return value
+// is the address of an IP network variable that stores the value of the flag.
+func CIDR(name string, value *net.IPNet, normalize bool, usage string, opts ...Option) **net.IPNet {
+	var n *net.IPNet
+	CIDRVar(&n, name, value, normalize, usage, opts...)
+	return &n
+}
+
+// CIDRVar defines a CIDR flag with specified name, default value, and usage
+// string. When normalize is true, the host bits of the provided address are
+// cleared. The argument p points to an IP network variable in which to store
+// the value of the flag.
+func CIDRVar(p **net.IPNet, name string, value *net.IPNet, normalize bool, usage string, opts ...Option) {
+	CIDRVarFS(flag.CommandLine, p, name, value, normalize, usage, opts...)
+}
+
+// CIDRVarFS is like CIDRVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func CIDRVarFS(fs *flag.FlagSet, p **net.IPNet, name string, value *net.IPNet, normalize bool, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewCIDRValue(p, normalize), name, usage, opts)
+}
+
+// NewCIDRValue returns a CIDRValue storing its value in p. When normalize is
+// true, the stored IP is the network address rather than the one provided.
+func NewCIDRValue(p **net.IPNet, normalize bool) *CIDRValue {
+	return &CIDRValue{
+		p:         p,
+		normalize: normalize,
+	}
+}
+
+// CIDRValue holds an IP network that can be provided via the command line in
+// CIDR notation, for instance "192.168.1.0/24" or "2001:db8::/32".
+type CIDRValue struct {
+	p         **net.IPNet
+	normalize bool
+}
+
+// String implements flag.Value by returning the IP network in CIDR notation.
+func (n *CIDRValue) String() string {
+	if n.p == nil || *n.p == nil {
+		return ""
+	}
+	return (*n.p).String()
+}
+
+// Set implements flag.Value by parsing the given CIDR notation block.
+func (n *CIDRValue) Set(value string) error {
+	ip, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR value %q", value)
+	}
+	if !n.normalize {
+		if ip4 := ip.To4(); ip4 != nil && len(network.IP) == net.IPv4len {
+			ip = ip4
+		}
+		network.IP = ip
+	}
+	*n.p = network
+	return nil
+}
+
+// Type implements pflag.Value by returning "cidr".
+func (n *CIDRValue) Type() string {
+	return "cidr"
+}
@@ -0,0 +1,142 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"net"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.CIDRValue)(nil)
+
+var cidrTests = []struct {
+	about               string
+	name                string
+	value               string
+	normalize           bool
+	defaultValue        *net.IPNet
+	expectedValue       *net.IPNet
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "IPv4 block",
+	name:                "v4",
+	value:               "192.168.1.0/24",
+	expectedValue:       mustParseCIDR("192.168.1.0/24"),
+	expectedStringValue: "192.168.1.0/24",
+}, {
+	about:               "IPv6 block",
+	name:                "v6",
+	value:               "2001:db8::/32",
+	expectedValue:       mustParseCIDR("2001:db8::/32"),
+	expectedStringValue: "2001:db8::/32",
+}, {
+	about: "host bits preserved",
+	name:  "preserved",
+	value: "10.1.2.3/8",
+	expectedValue: &net.IPNet{
+		IP:   net.IPv4(10, 1, 2, 3).To4(),
+		Mask: net.CIDRMask(8, 32),
+	},
+	expectedStringValue: "10.1.2.3/8",
+}, {
+	about:               "host bits normalized",
+	name:                "normalized",
+	value:               "10.1.2.3/8",
+	normalize:           true,
+	expectedValue:       mustParseCIDR("10.0.0.0/8"),
+	expectedStringValue: "10.0.0.0/8",
+}, {
+	about:               "IPv6 host bits normalized",
+	name:                "normalized6",
+	value:               "2001:db8::42/64",
+	normalize:           true,
+	expectedValue:       mustParseCIDR("2001:db8::/64"),
+	expectedStringValue: "2001:db8::/64",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "10.0.0.0/8",
+	defaultValue:  mustParseCIDR("0.0.0.0/0"),
+	expectedValue: mustParseCIDR("10.0.0.0/8"),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  mustParseCIDR("0.0.0.0/0"),
+	expectedValue: mustParseCIDR("0.0.0.0/0"),
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid CIDR value ""`,
+}, {
+	about:         "error: missing mask",
+	name:          "err",
+	value:         "192.168.1.1",
+	expectedError: `invalid CIDR value "192.168.1.1"`,
+}, {
+	about:         "error: invalid mask",
+	name:          "err",
+	value:         "10.0.0.0/42",
+	expectedError: `invalid CIDR value "10.0.0.0/42"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "10.0.0.0",
+	defaultValue:  mustParseCIDR("0.0.0.0/0"),
+	expectedValue: mustParseCIDR("0.0.0.0/0"),
+	expectedError: `invalid CIDR value "10.0.0.0"`,
+}}
+
+func TestCIDR(t *testing.T) {
+	for _, test := range cidrTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.CIDR(test.name, test.defaultValue, test.normalize, "CIDR usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestCIDRVar(t *testing.T) {
+	for _, test := range cidrTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v *net.IPNet
+			flagutils.CIDRVar(&v, test.name, test.defaultValue, test.normalize, "CIDR usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestCIDRValueString(t *testing.T) {
+	for _, test := range cidrTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v *net.IPNet
+			n := flagutils.NewCIDRValue(&v, test.normalize)
+			n.Set(test.value)
+			c.Assert(n.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
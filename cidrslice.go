@@ -0,0 +1,72 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// CIDRSlice defines a CIDR slice flag with specified name, default value, and
+// usage string. The return value is the address of a slice of IP networks
+// that stores the value of the flag.
+func CIDRSlice(name string, value []*net.IPNet, usage string, opts ...Option) *[]*net.IPNet {
+	var s []*net.IPNet
+	CIDRSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// CIDRSliceVar defines a CIDR slice flag with specified name, default value,
+// and usage string. The argument p points to a slice of IP networks in which
+// to store the value of the flag.
+func CIDRSliceVar(p *[]*net.IPNet, name string, value []*net.IPNet, usage string, opts ...Option) {
+	CIDRSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// CIDRSliceVarFS is like CIDRSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func CIDRSliceVarFS(fs *flag.FlagSet, p *[]*net.IPNet, name string, value []*net.IPNet, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*CIDRSliceValue)(p), name, usage, opts)
+}
+
+// CIDRSliceValue holds a slice of IP networks that can be provided via the
+// command line as a comma separated list of CIDR notation blocks, for
+// instance "10.0.0.0/8,2001:db8::/32".
+type CIDRSliceValue []*net.IPNet
+
+// String implements flag.Value by returning the slice as a string.
+func (s *CIDRSliceValue) String() string {
+	values := make([]string, len(*s))
+	for i, n := range *s {
+		values[i] = n.String()
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *CIDRSliceValue) Set(value string) error {
+	*s = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	ns := make([]*net.IPNet, len(values))
+	for i, v := range values {
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			return fmt.Errorf("invalid CIDR value %q", v)
+		}
+		ns[i] = n
+	}
+	*s = ns
+	return nil
+}
+
+// Type implements pflag.Value by returning "cidrSlice".
+func (s *CIDRSliceValue) Type() string {
+	return "cidrSlice"
+}
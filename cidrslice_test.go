@@ -0,0 +1,131 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"net"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.CIDRSliceValue)(nil)
+
+var cidrSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []*net.IPNet
+	expectedValue       []*net.IPNet
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single block",
+	name:                "single",
+	value:               "10.0.0.0/8",
+	expectedValue:       []*net.IPNet{mustParseCIDR("10.0.0.0/8")},
+	expectedStringValue: "10.0.0.0/8",
+}, {
+	about: "multiple blocks",
+	name:  "multiple",
+	value: "10.0.0.0/8,192.168.1.0/24,2001:db8::/32",
+	expectedValue: []*net.IPNet{
+		mustParseCIDR("10.0.0.0/8"),
+		mustParseCIDR("192.168.1.0/24"),
+		mustParseCIDR("2001:db8::/32"),
+	},
+	expectedStringValue: "10.0.0.0/8,192.168.1.0/24,2001:db8::/32",
+}, {
+	about: "host bits are masked",
+	name:  "masked",
+	value: " 10.1.2.3/8 , 192.168.1.42/24 ",
+	expectedValue: []*net.IPNet{
+		mustParseCIDR("10.0.0.0/8"),
+		mustParseCIDR("192.168.1.0/24"),
+	},
+	expectedStringValue: "10.0.0.0/8,192.168.1.0/24",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "10.0.0.0/8",
+	defaultValue:  []*net.IPNet{mustParseCIDR("0.0.0.0/0")},
+	expectedValue: []*net.IPNet{mustParseCIDR("10.0.0.0/8")},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []*net.IPNet{mustParseCIDR("0.0.0.0/0")},
+	expectedValue: []*net.IPNet{mustParseCIDR("0.0.0.0/0")},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: missing mask",
+	name:          "err",
+	value:         "10.0.0.0/8,192.168.1.1",
+	expectedError: `invalid CIDR value "192.168.1.1"`,
+}, {
+	about:         "error: invalid mask",
+	name:          "err",
+	value:         "10.0.0.0/42",
+	expectedError: `invalid CIDR value "10.0.0.0/42"`,
+}}
+
+func TestCIDRSlice(t *testing.T) {
+	for _, test := range cidrSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.CIDRSlice(test.name, test.defaultValue, "CIDR slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestCIDRSliceVar(t *testing.T) {
+	for _, test := range cidrSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []*net.IPNet
+			flagutils.CIDRSliceVar(&v, test.name, test.defaultValue, "CIDR slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestCIDRSliceValueString(t *testing.T) {
+	for _, test := range cidrSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.CIDRSliceValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
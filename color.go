@@ -0,0 +1,131 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Color defines a color flag with specified name, default value, and usage
+// string. The return value is the address of a color.NRGBA variable that
+// stores the value of the flag.
+func Color(name string, value color.NRGBA, usage string, opts ...Option) *color.NRGBA {
+	var c color.NRGBA
+	ColorVar(&c, name, value, usage, opts...)
+	return &c
+}
+
+// ColorVar defines a color flag with specified name, default value, and usage
+// string. The argument p points to a color.NRGBA variable in which to store
+// the value of the flag.
+func ColorVar(p *color.NRGBA, name string, value color.NRGBA, usage string, opts ...Option) {
+	ColorVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// ColorVarFS is like ColorVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func ColorVarFS(fs *flag.FlagSet, p *color.NRGBA, name string, value color.NRGBA, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*ColorValue)(p), name, usage, opts)
+}
+
+// ColorValue holds a color that can be provided via the command line in the
+// "#RRGGBB" or "#RGB" hexadecimal forms, optionally followed by the alpha
+// component ("#RRGGBBAA" or "#RGBA"), or as one of the basic CSS color names,
+// like "red" or "navy". Names are case insensitive. Colors without an alpha
+// component are fully opaque. Colors are stored as color.NRGBA values, which
+// are not alpha-premultiplied, so that the provided components are preserved
+// regardless of the alpha component.
+type ColorValue color.NRGBA
+
+// String implements flag.Value by returning the color in its hexadecimal
+// form. The alpha component is only included if the color is not fully
+// opaque.
+func (c *ColorValue) String() string {
+	s := fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	if c.A != 0xff {
+		s += fmt.Sprintf("%02x", c.A)
+	}
+	return s
+}
+
+// Set implements flag.Value by parsing the given color.
+func (c *ColorValue) Set(value string) error {
+	nrgba, err := parseColor(value)
+	if err != nil {
+		return err
+	}
+	*c = ColorValue(nrgba)
+	return nil
+}
+
+// Type implements pflag.Value by returning "color".
+func (c *ColorValue) Type() string {
+	return "color"
+}
+
+// parseColor parses the given hexadecimal color or color name.
+func parseColor(value string) (color.NRGBA, error) {
+	if nrgba, ok := colorNames[strings.ToLower(value)]; ok {
+		return nrgba, nil
+	}
+	hex, ok := strings.CutPrefix(value, "#")
+	if !ok {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", value)
+	}
+	switch len(hex) {
+	case 3, 4:
+		// Expand the short form, so that "#f80" is the same as "#ff8800".
+		var b strings.Builder
+		for _, r := range hex {
+			b.WriteRune(r)
+			b.WriteRune(r)
+		}
+		hex = b.String()
+	case 6, 8:
+	default:
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", value)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", value)
+	}
+	return color.NRGBA{
+		R: uint8(n >> 24),
+		G: uint8(n >> 16),
+		B: uint8(n >> 8),
+		A: uint8(n),
+	}, nil
+}
+
+// colorNames maps the basic CSS color names to their values.
+var colorNames = map[string]color.NRGBA{
+	"black":       {0x00, 0x00, 0x00, 0xff},
+	"silver":      {0xc0, 0xc0, 0xc0, 0xff},
+	"gray":        {0x80, 0x80, 0x80, 0xff},
+	"grey":        {0x80, 0x80, 0x80, 0xff},
+	"white":       {0xff, 0xff, 0xff, 0xff},
+	"maroon":      {0x80, 0x00, 0x00, 0xff},
+	"red":         {0xff, 0x00, 0x00, 0xff},
+	"purple":      {0x80, 0x00, 0x80, 0xff},
+	"fuchsia":     {0xff, 0x00, 0xff, 0xff},
+	"magenta":     {0xff, 0x00, 0xff, 0xff},
+	"green":       {0x00, 0x80, 0x00, 0xff},
+	"lime":        {0x00, 0xff, 0x00, 0xff},
+	"olive":       {0x80, 0x80, 0x00, 0xff},
+	"yellow":      {0xff, 0xff, 0x00, 0xff},
+	"navy":        {0x00, 0x00, 0x80, 0xff},
+	"blue":        {0x00, 0x00, 0xff, 0xff},
+	"teal":        {0x00, 0x80, 0x80, 0xff},
+	"aqua":        {0x00, 0xff, 0xff, 0xff},
+	"cyan":        {0x00, 0xff, 0xff, 0xff},
+	"orange":      {0xff, 0xa5, 0x00, 0xff},
+	"transparent": {0x00, 0x00, 0x00, 0x00},
+}
@@ -0,0 +1,153 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"image/color"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.ColorValue)(nil)
+
+var colorTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        color.NRGBA
+	expectedValue       color.NRGBA
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "long form",
+	name:                "long",
+	value:               "#1E90FF",
+	expectedValue:       color.NRGBA{R: 0x1e, G: 0x90, B: 0xff, A: 0xff},
+	expectedStringValue: "#1e90ff",
+}, {
+	about:               "short form",
+	name:                "short",
+	value:               "#f80",
+	expectedValue:       color.NRGBA{R: 0xff, G: 0x88, B: 0x00, A: 0xff},
+	expectedStringValue: "#ff8800",
+}, {
+	about:               "long form with alpha",
+	name:                "long-alpha",
+	value:               "#00000080",
+	expectedValue:       color.NRGBA{A: 0x80},
+	expectedStringValue: "#00000080",
+}, {
+	about:               "short form with alpha",
+	name:                "short-alpha",
+	value:               "#fff0",
+	expectedValue:       color.NRGBA{R: 0xff, G: 0xff, B: 0xff},
+	expectedStringValue: "#ffffff00",
+}, {
+	about:               "color name",
+	name:                "name",
+	value:               "Navy",
+	expectedValue:       color.NRGBA{B: 0x80, A: 0xff},
+	expectedStringValue: "#000080",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "red",
+	defaultValue:  color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	expectedValue: color.NRGBA{R: 0xff, A: 0xff},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	expectedValue: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+}, {
+	about:         "error: unknown name",
+	name:          "err",
+	value:         "bad-wolf",
+	expectedError: `invalid color "bad-wolf"`,
+}, {
+	about:         "error: missing hash",
+	name:          "err",
+	value:         "ff0000",
+	expectedError: `invalid color "ff0000"`,
+}, {
+	about:         "error: invalid length",
+	name:          "err",
+	value:         "#ff0000f",
+	expectedError: `invalid color "#ff0000f"`,
+}, {
+	about:         "error: invalid digits",
+	name:          "err",
+	value:         "#gg0000",
+	expectedError: `invalid color "#gg0000"`,
+}, {
+	about:         "error: sign",
+	name:          "err",
+	value:         "#+f0000",
+	expectedError: `invalid color "#\+f0000"`,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid color ""`,
+}}
+
+func TestColor(t *testing.T) {
+	for _, test := range colorTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Color(test.name, test.defaultValue, "color usage")
+			if test.value != "" || test.defaultValue == (color.NRGBA{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestColorVar(t *testing.T) {
+	for _, test := range colorTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v color.NRGBA
+			flagutils.ColorVar(&v, test.name, test.defaultValue, "color usage")
+			if test.value != "" || test.defaultValue == (color.NRGBA{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestColorValueString(t *testing.T) {
+	for _, test := range colorTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != (color.NRGBA{}) || test.expectedError != "" {
+				return
+			}
+			var v flagutils.ColorValue
+			c.Assert(v.Set(test.value), qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestColorTranslucent(t *testing.T) {
+	c := qt.New(t)
+	var v color.NRGBA
+	err := (*flagutils.ColorValue)(&v).Set("#ff000080")
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v, qt.Equals, color.NRGBA{R: 0xff, A: 0x80})
+	r, g, b, a := v.RGBA()
+	c.Assert([]uint32{r, g, b, a}, qt.DeepEquals, []uint32{0x8080, 0, 0, 0x8080})
+}
@@ -0,0 +1,89 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// LatLng holds a geographic point, with latitude and longitude expressed in
+// decimal degrees.
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// String returns the point as "latitude,longitude", as in "45.07,7.68".
+func (p LatLng) String() string {
+	return formatFloat(p.Lat) + "," + formatFloat(p.Lng)
+}
+
+// Coordinate defines a geographic coordinate flag with specified name,
+// default value, and usage string. The value is provided via the command
+// line as "latitude,longitude" in decimal degrees, as in "45.07,7.68". The
+// return value is the address of a LatLng variable that stores the value of
+// the flag.
+func Coordinate(name string, value LatLng, usage string, opts ...Option) *LatLng {
+	var p LatLng
+	CoordinateVar(&p, name, value, usage, opts...)
+	return &p
+}
+
+// CoordinateVar defines a geographic coordinate flag with specified name,
+// default value, and usage string. The argument p points to a LatLng
+// variable in which to store the value of the flag.
+func CoordinateVar(p *LatLng, name string, value LatLng, usage string, opts ...Option) {
+	CoordinateVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// CoordinateVarFS is like CoordinateVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func CoordinateVarFS(fs *flag.FlagSet, p *LatLng, name string, value LatLng, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*CoordinateValue)(p), name, usage, opts)
+}
+
+// CoordinateValue holds a geographic point that can be provided via the
+// command line. The latitude must be between -90 and 90, and the longitude
+// between -180 and 180.
+type CoordinateValue LatLng
+
+// String implements flag.Value by returning the point as
+// "latitude,longitude".
+func (p *CoordinateValue) String() string {
+	return LatLng(*p).String()
+}
+
+// Set implements flag.Value by parsing the given coordinate.
+func (p *CoordinateValue) Set(value string) error {
+	lat, lng, ok := strings.Cut(value, ",")
+	if !ok {
+		return fmt.Errorf("invalid coordinate %q: expected \"latitude,longitude\"", value)
+	}
+	latFloat, err := parseFloat(strings.TrimSpace(lat))
+	if err != nil {
+		return fmt.Errorf("invalid coordinate %q: invalid latitude %q", value, strings.TrimSpace(lat))
+	}
+	if latFloat < -90 || latFloat > 90 {
+		return fmt.Errorf("invalid coordinate %q: latitude must be between -90 and 90", value)
+	}
+	lngFloat, err := parseFloat(strings.TrimSpace(lng))
+	if err != nil {
+		return fmt.Errorf("invalid coordinate %q: invalid longitude %q", value, strings.TrimSpace(lng))
+	}
+	if lngFloat < -180 || lngFloat > 180 {
+		return fmt.Errorf("invalid coordinate %q: longitude must be between -180 and 180", value)
+	}
+	*p = CoordinateValue{
+		Lat: latFloat,
+		Lng: lngFloat,
+	}
+	return nil
+}
+
+// Type implements pflag.Value by returning "coordinate".
+func (p *CoordinateValue) Type() string {
+	return "coordinate"
+}
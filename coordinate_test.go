@@ -0,0 +1,139 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.CoordinateValue)(nil)
+
+var coordinateTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.LatLng
+	expectedValue       flagutils.LatLng
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "valid coordinate",
+	name:                "valid",
+	value:               "45.07,7.68",
+	expectedValue:       flagutils.LatLng{Lat: 45.07, Lng: 7.68},
+	expectedStringValue: "45.07,7.68",
+}, {
+	about:               "negative values and spaces",
+	name:                "negative",
+	value:               "-33.8688, -151.2093",
+	expectedValue:       flagutils.LatLng{Lat: -33.8688, Lng: -151.2093},
+	expectedStringValue: "-33.8688,-151.2093",
+}, {
+	about:               "bounds",
+	name:                "bounds",
+	value:               "90,-180",
+	expectedValue:       flagutils.LatLng{Lat: 90, Lng: -180},
+	expectedStringValue: "90,-180",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "51.5,-0.12",
+	defaultValue:  flagutils.LatLng{Lat: 45.07, Lng: 7.68},
+	expectedValue: flagutils.LatLng{Lat: 51.5, Lng: -0.12},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  flagutils.LatLng{Lat: 45.07, Lng: 7.68},
+	expectedValue: flagutils.LatLng{Lat: 45.07, Lng: 7.68},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid coordinate "": expected "latitude,longitude"`,
+}, {
+	about:         "error: missing longitude",
+	name:          "err",
+	value:         "45.07",
+	expectedError: `invalid coordinate "45.07": expected "latitude,longitude"`,
+}, {
+	about:         "error: invalid latitude",
+	name:          "err",
+	value:         "north,7.68",
+	expectedError: `invalid coordinate "north,7.68": invalid latitude "north"`,
+}, {
+	about:         "error: invalid longitude",
+	name:          "err",
+	value:         "45.07,7.68,1",
+	expectedError: `invalid coordinate "45.07,7.68,1": invalid longitude "7.68,1"`,
+}, {
+	about:         "error: latitude out of range",
+	name:          "err",
+	value:         "90.1,7.68",
+	expectedError: `invalid coordinate "90.1,7.68": latitude must be between -90 and 90`,
+}, {
+	about:         "error: longitude out of range",
+	name:          "err",
+	value:         "45.07,-181",
+	expectedError: `invalid coordinate "45.07,-181": longitude must be between -180 and 180`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "45.07,",
+	defaultValue:  flagutils.LatLng{Lat: 45.07, Lng: 7.68},
+	expectedValue: flagutils.LatLng{Lat: 45.07, Lng: 7.68},
+	expectedError: `invalid coordinate "45.07,": invalid longitude ""`,
+}}
+
+func TestCoordinate(t *testing.T) {
+	for _, test := range coordinateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Coordinate(test.name, test.defaultValue, "coordinate usage")
+			if test.value != "" || test.defaultValue == (flagutils.LatLng{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestCoordinateVar(t *testing.T) {
+	for _, test := range coordinateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.LatLng
+			flagutils.CoordinateVar(&v, test.name, test.defaultValue, "coordinate usage")
+			if test.value != "" || test.defaultValue == (flagutils.LatLng{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestCoordinateValueString(t *testing.T) {
+	for _, test := range coordinateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != (flagutils.LatLng{}) || test.expectedError != "" {
+				return
+			}
+			var v flagutils.CoordinateValue
+			c.Assert(v.String(), qt.Equals, "0,0")
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
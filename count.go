@@ -0,0 +1,71 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// Count defines a counter flag with specified name, default value, and usage
+// string. Each occurrence of the flag on the command line increments the
+// counter, so that "-v -v -v" results in 3. An explicit number can also be
+// provided, as in "-v=2". The return value is the address of an int variable
+// that stores the value of the flag.
+func Count(name string, value int, usage string, opts ...Option) *int {
+	var n int
+	CountVar(&n, name, value, usage, opts...)
+	return &n
+}
+
+// CountVar defines a counter flag with specified name, default value, and
+// usage string. See Count for details on how the counter is incremented. The
+// argument p points to an int variable in which to store the value of the
+// flag.
+func CountVar(p *int, name string, value int, usage string, opts ...Option) {
+	CountVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// CountVarFS is like CountVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func CountVarFS(fs *flag.FlagSet, p *int, name string, value int, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*CountValue)(p), name, usage, opts)
+}
+
+// CountValue holds a counter incremented each time the flag is provided via
+// the command line without an explicit value.
+type CountValue int
+
+// String implements flag.Value by returning the counter as a string.
+func (n *CountValue) String() string {
+	return strconv.Itoa(int(*n))
+}
+
+// Set implements flag.Value by incrementing the counter. The flag package
+// passes "true" when the flag is provided without a value. Otherwise the
+// given value must be a non-negative number and replaces the counter.
+func (n *CountValue) Set(value string) error {
+	if value == "true" {
+		*n++
+		return nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil || v < 0 {
+		return fmt.Errorf("invalid count value %q", value)
+	}
+	*n = CountValue(v)
+	return nil
+}
+
+// Type implements pflag.Value by returning "count".
+func (n *CountValue) Type() string {
+	return "count"
+}
+
+// IsBoolFlag reports that the flag does not require a value, so that it can
+// be provided as "-v" on the command line.
+func (n *CountValue) IsBoolFlag() bool {
+	return true
+}
@@ -0,0 +1,96 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.CountValue)(nil)
+
+var countTests = []struct {
+	about         string
+	args          []string
+	defaultValue  int
+	expectedValue int
+	expectedError string
+}{{
+	about:         "not provided",
+	expectedValue: 0,
+}, {
+	about:         "single occurrence",
+	args:          []string{"-v"},
+	expectedValue: 1,
+}, {
+	about:         "multiple occurrences",
+	args:          []string{"-v", "-v", "-v"},
+	expectedValue: 3,
+}, {
+	about:         "explicit value",
+	args:          []string{"-v=5"},
+	expectedValue: 5,
+}, {
+	about:         "explicit value then occurrences",
+	args:          []string{"-v=2", "-v", "-v"},
+	expectedValue: 4,
+}, {
+	about:         "default value: with occurrences",
+	args:          []string{"-v", "-v"},
+	defaultValue:  1,
+	expectedValue: 3,
+}, {
+	about:         "default value: without occurrences",
+	defaultValue:  1,
+	expectedValue: 1,
+}, {
+	about:         "error: invalid value",
+	args:          []string{"-v=many"},
+	expectedError: `invalid boolean value "many" for -v: invalid count value "many"`,
+}, {
+	about:         "error: negative value",
+	args:          []string{"-v=-1"},
+	expectedError: `invalid boolean value "-1" for -v: invalid count value "-1"`,
+}}
+
+func TestCount(t *testing.T) {
+	for _, test := range countTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			v := new(int)
+			*v = test.defaultValue
+			fs.Var((*flagutils.CountValue)(v), "v", "verbosity")
+			err := fs.Parse(test.args)
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				return
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestCountVar(t *testing.T) {
+	runIsolated(t, "count var", func(c *qt.C) {
+		var v int
+		flagutils.CountVar(&v, "verbose", 0, "verbosity")
+		c.Assert(flag.Set("verbose", "true"), qt.Equals, nil)
+		c.Assert(flag.Set("verbose", "true"), qt.Equals, nil)
+		c.Assert(v, qt.Equals, 2)
+		c.Assert(flag.Lookup("verbose").Value.String(), qt.Equals, "2")
+	})
+}
+
+func TestCountValueIsBoolFlag(t *testing.T) {
+	c := qt.New(t)
+	var v flagutils.CountValue
+	c.Assert(v.IsBoolFlag(), qt.Equals, true)
+}
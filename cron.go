@@ -0,0 +1,196 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron defines a cron expression flag with specified name, default value,
+// validation function and usage string. The expression is validated by the
+// given function when the flag is set, so that the flag can be used with any
+// cron library. If validate is nil, a built-in validator is used, accepting
+// standard 5 field expressions ("*/5 * * * *"), 6 field expressions with a
+// leading seconds field, and descriptors such as "@daily" or "@every 1h".
+// The return value is the address of a string variable that stores the
+// value of the flag.
+func Cron(name string, value string, validate func(string) error, usage string, opts ...Option) *string {
+	var s string
+	CronVar(&s, name, value, validate, usage, opts...)
+	return &s
+}
+
+// CronVar defines a cron expression flag with specified name, default value,
+// validation function and usage string. See Cron for details. The argument p
+// points to a string variable in which to store the value of the flag.
+func CronVar(p *string, name string, value string, validate func(string) error, usage string, opts ...Option) {
+	CronVarFS(flag.CommandLine, p, name, value, validate, usage, opts...)
+}
+
+// CronVarFS is like CronVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func CronVarFS(fs *flag.FlagSet, p *string, name string, value string, validate func(string) error, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewCronValue(p, validate), name, usage, opts)
+}
+
+// NewCronValue returns a CronValue storing its value in p and validating
+// expressions with the given function. If validate is nil, the built-in
+// validator is used.
+func NewCronValue(p *string, validate func(string) error) *CronValue {
+	if validate == nil {
+		validate = validateCron
+	}
+	return &CronValue{
+		p:        p,
+		validate: validate,
+	}
+}
+
+// CronValue holds a cron expression that can be provided via the command
+// line.
+type CronValue struct {
+	p        *string
+	validate func(string) error
+}
+
+// String implements flag.Value by returning the cron expression.
+func (c *CronValue) String() string {
+	if c.p == nil {
+		return ""
+	}
+	return *c.p
+}
+
+// Set implements flag.Value by validating and storing the given cron
+// expression.
+func (c *CronValue) Set(value string) error {
+	if err := c.validate(value); err != nil {
+		return fmt.Errorf("invalid cron expression %q: %v", value, err)
+	}
+	*c.p = value
+	return nil
+}
+
+// Type implements pflag.Value by returning "cron".
+func (c *CronValue) Type() string {
+	return "cron"
+}
+
+// cronField describes a field of a cron expression.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+	any   bool
+}
+
+var (
+	cronSeconds = cronField{name: "seconds", min: 0, max: 59}
+	cronFields  = []cronField{
+		{name: "minutes", min: 0, max: 59},
+		{name: "hours", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31, any: true},
+		{name: "month", min: 1, max: 12, names: []string{
+			"JAN", "FEB", "MAR", "APR", "MAY", "JUN",
+			"JUL", "AUG", "SEP", "OCT", "NOV", "DEC",
+		}},
+		{name: "day of week", min: 0, max: 7, names: []string{
+			"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT",
+		}, any: true},
+	}
+	cronDescriptors = []string{
+		"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly",
+	}
+)
+
+// validateCron validates the given cron expression.
+func validateCron(expr string) error {
+	if strings.HasPrefix(expr, "@") {
+		if d, ok := strings.CutPrefix(expr, "@every "); ok {
+			if dur, err := time.ParseDuration(d); err != nil || dur <= 0 {
+				return fmt.Errorf("invalid duration %q", d)
+			}
+			return nil
+		}
+		for _, desc := range cronDescriptors {
+			if expr == desc {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown descriptor %q", expr)
+	}
+	parts := strings.Fields(expr)
+	fields := cronFields
+	switch len(parts) {
+	case 5:
+	case 6:
+		fields = append([]cronField{cronSeconds}, cronFields...)
+	default:
+		return fmt.Errorf("expected 5 or 6 fields, got %d", len(parts))
+	}
+	for i, part := range parts {
+		if err := fields[i].validate(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks that the given value is valid for the field. The value is
+// a comma separated list of "*", single values or ranges, each one
+// optionally followed by a "/step" suffix.
+func (f cronField) validate(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if !f.validItem(item) {
+			return fmt.Errorf("invalid %s field %q", f.name, value)
+		}
+	}
+	return nil
+}
+
+// validItem reports whether the given list item is valid for the field.
+func (f cronField) validItem(item string) bool {
+	item, step, hasStep := strings.Cut(item, "/")
+	if hasStep {
+		if n, err := strconv.Atoi(step); err != nil || n < 1 {
+			return false
+		}
+	}
+	if item == "*" || item == "?" && f.any {
+		return true
+	}
+	lo, hi, isRange := strings.Cut(item, "-")
+	loValue, ok := f.parse(lo)
+	if !ok {
+		return false
+	}
+	if !isRange {
+		return true
+	}
+	hiValue, ok := f.parse(hi)
+	return ok && loValue <= hiValue
+}
+
+// parse parses the given field value, either a number or a name, and
+// reports whether it is in range.
+func (f cronField) parse(value string) (int, bool) {
+	for i, name := range f.names {
+		if strings.EqualFold(value, name) {
+			if f.min == 1 {
+				return i + 1, true
+			}
+			return i, true
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, false
+	}
+	return n, true
+}
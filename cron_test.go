@@ -0,0 +1,173 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.CronValue)(nil)
+
+var cronTests = []struct {
+	about         string
+	name          string
+	value         string
+	defaultValue  string
+	expectedValue string
+	expectedError string
+}{{
+	about:         "every minute",
+	name:          "minute",
+	value:         "* * * * *",
+	expectedValue: "* * * * *",
+}, {
+	about:         "steps, ranges and lists",
+	name:          "complex",
+	value:         "*/15 9-17 1,15 * MON-FRI",
+	expectedValue: "*/15 9-17 1,15 * MON-FRI",
+}, {
+	about:         "seconds field",
+	name:          "seconds",
+	value:         "30 0 12 ? jan-jun 0",
+	expectedValue: "30 0 12 ? jan-jun 0",
+}, {
+	about:         "range with step",
+	name:          "rangestep",
+	value:         "0 0-23/2 * * 7",
+	expectedValue: "0 0-23/2 * * 7",
+}, {
+	about:         "descriptor",
+	name:          "descriptor",
+	value:         "@daily",
+	expectedValue: "@daily",
+}, {
+	about:         "every",
+	name:          "every",
+	value:         "@every 1h30m",
+	expectedValue: "@every 1h30m",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "@hourly",
+	defaultValue:  "@daily",
+	expectedValue: "@hourly",
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  "@daily",
+	expectedValue: "@daily",
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid cron expression "": expected 5 or 6 fields, got 0`,
+}, {
+	about:         "error: too many fields",
+	name:          "err",
+	value:         "* * * * * * *",
+	expectedError: `invalid cron expression "\* \* \* \* \* \* \*": expected 5 or 6 fields, got 7`,
+}, {
+	about:         "error: minute out of range",
+	name:          "err",
+	value:         "60 * * * *",
+	expectedError: `invalid cron expression "60 \* \* \* \*": invalid minutes field "60"`,
+}, {
+	about:         "error: day of month out of range",
+	name:          "err",
+	value:         "0 0 0 * *",
+	expectedError: `invalid cron expression "0 0 0 \* \*": invalid day of month field "0"`,
+}, {
+	about:         "error: invalid range",
+	name:          "err",
+	value:         "0 17-9 * * *",
+	expectedError: `invalid cron expression "0 17-9 \* \* \*": invalid hours field "17-9"`,
+}, {
+	about:         "error: invalid step",
+	name:          "err",
+	value:         "*/0 * * * *",
+	expectedError: `invalid cron expression "\*/0 \* \* \* \*": invalid minutes field "\*/0"`,
+}, {
+	about:         "error: question mark in month",
+	name:          "err",
+	value:         "0 0 1 ? *",
+	expectedError: `invalid cron expression "0 0 1 \? \*": invalid month field "\?"`,
+}, {
+	about:         "error: unknown name",
+	name:          "err",
+	value:         "0 0 * * FUN",
+	expectedError: `invalid cron expression "0 0 \* \* FUN": invalid day of week field "FUN"`,
+}, {
+	about:         "error: unknown descriptor",
+	name:          "err",
+	value:         "@fortnightly",
+	expectedError: `invalid cron expression "@fortnightly": unknown descriptor "@fortnightly"`,
+}, {
+	about:         "error: invalid every duration",
+	name:          "err",
+	value:         "@every 1d",
+	expectedError: `invalid cron expression "@every 1d": invalid duration "1d"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "* * *",
+	defaultValue:  "@daily",
+	expectedValue: "@daily",
+	expectedError: `invalid cron expression "\* \* \*": expected 5 or 6 fields, got 3`,
+}}
+
+func TestCron(t *testing.T) {
+	for _, test := range cronTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Cron(test.name, test.defaultValue, nil, "cron usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestCronVar(t *testing.T) {
+	for _, test := range cronTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v string
+			flagutils.CronVar(&v, test.name, test.defaultValue, nil, "cron usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestCronCustomValidator(t *testing.T) {
+	runIsolated(t, "custom validator", func(c *qt.C) {
+		validate := func(expr string) error {
+			if expr != "@reboot" {
+				return errors.New("only @reboot is supported")
+			}
+			return nil
+		}
+		v := flagutils.Cron("schedule", "", validate, "cron usage")
+		err := flag.Set("schedule", "* * * * *")
+		c.Assert(err, qt.ErrorMatches, `invalid cron expression "\* \* \* \* \*": only @reboot is supported`)
+		err = flag.Set("schedule", "@reboot")
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(*v, qt.Equals, "@reboot")
+	})
+}
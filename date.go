@@ -0,0 +1,64 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// dateLayout is the layout used to parse and format dates.
+const dateLayout = "2006-01-02"
+
+// Date defines a date flag with specified name, default value, and usage
+// string. The value is provided as "YYYY-MM-DD", for instance "2024-06-01",
+// and stored as midnight UTC of that day. The return value is the address of
+// a time variable that stores the value of the flag.
+func Date(name string, value time.Time, usage string, opts ...Option) *time.Time {
+	var t time.Time
+	DateVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// DateVar defines a date flag with specified name, default value, and usage
+// string. The argument p points to a time variable in which to store the
+// value of the flag, as midnight UTC of the provided day.
+func DateVar(p *time.Time, name string, value time.Time, usage string, opts ...Option) {
+	DateVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// DateVarFS is like DateVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func DateVarFS(fs *flag.FlagSet, p *time.Time, name string, value time.Time, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*DateValue)(p), name, usage, opts)
+}
+
+// DateValue holds a date without a time of day, that can be provided via the
+// command line as "YYYY-MM-DD".
+type DateValue time.Time
+
+// String implements flag.Value by returning the date as "YYYY-MM-DD".
+func (d *DateValue) String() string {
+	t := time.Time(*d)
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+// Set implements flag.Value by parsing the given date.
+func (d *DateValue) Set(value string) error {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return fmt.Errorf("invalid date %q: expected layout %q", value, dateLayout)
+	}
+	*d = DateValue(t)
+	return nil
+}
+
+// Type implements pflag.Value by returning "date".
+func (d *DateValue) Type() string {
+	return "date"
+}
@@ -0,0 +1,117 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.DateValue)(nil)
+
+var dateTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        time.Time
+	expectedValue       time.Time
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "date",
+	name:                "date",
+	value:               "2024-06-01",
+	expectedValue:       time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	expectedStringValue: "2024-06-01",
+}, {
+	about:               "leap day",
+	name:                "leap",
+	value:               "2024-02-29",
+	expectedValue:       time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+	expectedStringValue: "2024-02-29",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "2024-06-01",
+	defaultValue:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	expectedValue: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	expectedValue: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid date "": expected layout "2006-01-02"`,
+}, {
+	about:         "error: full timestamp",
+	name:          "err",
+	value:         "2024-06-01T10:00:00Z",
+	expectedError: `invalid date "2024-06-01T10:00:00Z": expected layout "2006-01-02"`,
+}, {
+	about:         "error: invalid day",
+	name:          "err",
+	value:         "2023-02-29",
+	expectedError: `invalid date "2023-02-29": expected layout "2006-01-02"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "01/06/2024",
+	defaultValue:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	expectedValue: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	expectedError: `invalid date "01/06/2024": expected layout "2006-01-02"`,
+}}
+
+func TestDate(t *testing.T) {
+	for _, test := range dateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Date(test.name, test.defaultValue, "date usage")
+			if test.value != "" || test.defaultValue.IsZero() {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestDateVar(t *testing.T) {
+	for _, test := range dateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v time.Time
+			flagutils.DateVar(&v, test.name, test.defaultValue, "date usage")
+			if test.value != "" || test.defaultValue.IsZero() {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestDateValueString(t *testing.T) {
+	for _, test := range dateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if !test.defaultValue.IsZero() {
+				return
+			}
+			var v flagutils.DateValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimal defines an arbitrary precision decimal flag with specified name,
+// default value, scale and usage string. The value is provided via the
+// command line in decimal notation, as in "12.50", and is stored exactly,
+// without floating point rounding. If scale is not negative, values with
+// more than scale decimal places are rejected. The return value is the
+// address of a big.Rat variable that stores the value of the flag.
+func Decimal(name string, value *big.Rat, scale int, usage string, opts ...Option) *big.Rat {
+	r := new(big.Rat)
+	DecimalVar(r, name, value, scale, usage, opts...)
+	return r
+}
+
+// DecimalVar defines an arbitrary precision decimal flag with specified
+// name, default value, scale and usage string. See Decimal for details. The
+// argument p points to a big.Rat variable in which to store the value of the
+// flag. A nil default value is treated as zero.
+func DecimalVar(p *big.Rat, name string, value *big.Rat, scale int, usage string, opts ...Option) {
+	DecimalVarFS(flag.CommandLine, p, name, value, scale, usage, opts...)
+}
+
+// DecimalVarFS is like DecimalVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func DecimalVarFS(fs *flag.FlagSet, p *big.Rat, name string, value *big.Rat, scale int, usage string, opts ...Option) {
+	if value != nil {
+		p.Set(value)
+	}
+	defineVar(fs, NewDecimalValue(p, scale), name, usage, opts)
+}
+
+// NewDecimalValue returns a DecimalValue storing its value in p. If scale is
+// not negative, values with more than scale decimal places are rejected.
+func NewDecimalValue(p *big.Rat, scale int) *DecimalValue {
+	return &DecimalValue{
+		p:     p,
+		scale: scale,
+	}
+}
+
+// DecimalValue holds an arbitrary precision decimal number that can be
+// provided via the command line.
+type DecimalValue struct {
+	p     *big.Rat
+	scale int
+}
+
+// String implements flag.Value by returning the number in decimal notation.
+// If a scale is set, exactly scale decimal places are included.
+func (d *DecimalValue) String() string {
+	if d.p == nil {
+		return ""
+	}
+	if d.scale >= 0 {
+		return d.p.FloatString(d.scale)
+	}
+	return d.p.FloatString(decimalPlaces(d.p))
+}
+
+// Set implements flag.Value by parsing the given decimal number.
+func (d *DecimalValue) Set(value string) error {
+	digits := value
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(digits, ".")
+	if !isDigits(integer) || hasFraction && !isDigits(fraction) {
+		return fmt.Errorf("invalid decimal value %q", value)
+	}
+	if d.scale >= 0 && len(fraction) > d.scale {
+		return fmt.Errorf("invalid decimal value %q: more than %d decimal places", value, d.scale)
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return fmt.Errorf("invalid decimal value %q", value)
+	}
+	d.p.Set(r)
+	return nil
+}
+
+// Type implements pflag.Value by returning "decimal".
+func (d *DecimalValue) Type() string {
+	return "decimal"
+}
+
+// decimalPlaces returns the number of decimal places required to represent
+// the given rational number exactly, or 20 if it cannot be represented by a
+// finite decimal number.
+func decimalPlaces(r *big.Rat) int {
+	denom := new(big.Int).Set(r.Denom())
+	ten := big.NewInt(10)
+	for n := 0; n < 20; n++ {
+		if denom.Cmp(big.NewInt(1)) == 0 {
+			return n
+		}
+		denom.Div(denom, new(big.Int).GCD(nil, nil, denom, ten))
+	}
+	return 20
+}
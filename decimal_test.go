@@ -0,0 +1,166 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"math/big"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.DecimalValue)(nil)
+
+var decimalTests = []struct {
+	about               string
+	name                string
+	value               string
+	scale               int
+	defaultValue        *big.Rat
+	expectedValue       *big.Rat
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "decimal",
+	name:                "decimal",
+	value:               "0.1",
+	scale:               -1,
+	expectedValue:       big.NewRat(1, 10),
+	expectedStringValue: "0.1",
+}, {
+	about:               "many decimal places",
+	name:                "places",
+	value:               "-123.000456",
+	scale:               -1,
+	expectedValue:       big.NewRat(-123000456, 1000000),
+	expectedStringValue: "-123.000456",
+}, {
+	about:               "integer",
+	name:                "integer",
+	value:               "+42",
+	scale:               -1,
+	expectedValue:       big.NewRat(42, 1),
+	expectedStringValue: "42",
+}, {
+	about:               "scale",
+	name:                "scale",
+	value:               "12.5",
+	scale:               2,
+	expectedValue:       big.NewRat(25, 2),
+	expectedStringValue: "12.50",
+}, {
+	about:               "zero scale",
+	name:                "zero",
+	value:               "100",
+	scale:               0,
+	expectedValue:       big.NewRat(100, 1),
+	expectedStringValue: "100",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "9.99",
+	scale:         2,
+	defaultValue:  big.NewRat(1, 1),
+	expectedValue: big.NewRat(999, 100),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	scale:         2,
+	defaultValue:  big.NewRat(1, 1),
+	expectedValue: big.NewRat(1, 1),
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	scale:         -1,
+	expectedValue: new(big.Rat),
+	expectedError: `invalid decimal value ""`,
+}, {
+	about:         "error: too many decimal places",
+	name:          "err",
+	value:         "12.345",
+	scale:         2,
+	expectedValue: new(big.Rat),
+	expectedError: `invalid decimal value "12.345": more than 2 decimal places`,
+}, {
+	about:         "error: fraction",
+	name:          "err",
+	value:         "1/3",
+	scale:         -1,
+	expectedValue: new(big.Rat),
+	expectedError: `invalid decimal value "1/3"`,
+}, {
+	about:         "error: exponent",
+	name:          "err",
+	value:         "1e3",
+	scale:         -1,
+	expectedValue: new(big.Rat),
+	expectedError: `invalid decimal value "1e3"`,
+}, {
+	about:         "error: missing fraction digits",
+	name:          "err",
+	value:         "1.",
+	scale:         -1,
+	expectedValue: new(big.Rat),
+	expectedError: `invalid decimal value "1."`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "--1",
+	scale:         2,
+	defaultValue:  big.NewRat(1, 1),
+	expectedValue: big.NewRat(1, 1),
+	expectedError: `invalid decimal value "--1"`,
+}}
+
+func TestDecimal(t *testing.T) {
+	for _, test := range decimalTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Decimal(test.name, test.defaultValue, test.scale, "decimal usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v.Cmp(test.expectedValue), qt.Equals, 0, qt.Commentf("got %s", v))
+		})
+	}
+}
+
+func TestDecimalVar(t *testing.T) {
+	for _, test := range decimalTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v big.Rat
+			flagutils.DecimalVar(&v, test.name, test.defaultValue, test.scale, "decimal usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v.Cmp(test.expectedValue), qt.Equals, 0, qt.Commentf("got %s", &v))
+		})
+	}
+}
+
+func TestDecimalValueString(t *testing.T) {
+	for _, test := range decimalTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil || test.expectedError != "" {
+				return
+			}
+			var v big.Rat
+			d := flagutils.NewDecimalValue(&v, test.scale)
+			err := d.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(d.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
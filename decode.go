@@ -0,0 +1,444 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeOption configures how string maps are decoded by StringMap.Decode.
+// Decode options are also accepted where a JSONOption is expected, to
+// configure how JSON flags are decoded.
+type DecodeOption func(*decodeOptions)
+
+// applyJSONOption implements JSONOption.
+func (f DecodeOption) applyJSONOption(o *jsonOptions) {
+	f(&o.decode)
+}
+
+// decodeOptions holds the configuration used when decoding string maps.
+type decodeOptions struct {
+	disallowUnknownFields bool
+}
+
+// DisallowUnknownFields returns an option making StringMap.Decode return an
+// error when the map includes keys not matching any field of the
+// destination struct. When provided to JSONVar, it makes JSON flags reject
+// object keys not matching any exported field of the destination struct.
+func DisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// Decode stores the contents of the string map in the value pointed to by
+// target, which is usually a pointer to a struct.
+//
+// Map keys are matched against struct fields using the name specified in the
+// "mapstructure" field tag, or the field name itself, matched case
+// insensitively. Fields tagged with "-" are ignored, and embedded structs
+// tagged with the ",squash" option have their fields decoded from the same
+// map. Strings are decoded into time.Duration fields using
+// time.ParseDuration, and into fields implementing encoding.TextUnmarshaler
+// using their UnmarshalText method. Arrays and objects are decoded element by
+// element, including into slice and map types implementing
+// encoding.TextUnmarshaler like StringSlice. Numbers are decoded into integer
+// fields only if they have no fractional part and fit the field type.
+//
+// Errors about specific values are reported as *PathError values including
+// the dot separated path of the offending value.
+func (s *StringMap) Decode(target interface{}, opts ...DecodeOption) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("cannot decode into %T: a non-nil pointer is required", target)
+	}
+	d := &decoder{}
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+	return d.decode("", map[string]interface{}(*s), v.Elem())
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// decoder decodes string map values into Go values.
+type decoder struct {
+	opts decodeOptions
+}
+
+// decode stores the given value, found at the given path, in out.
+func (d *decoder) decode(path string, in interface{}, out reflect.Value) error {
+	if in == nil {
+		out.Set(reflect.Zero(out.Type()))
+		return nil
+	}
+	if out.Kind() == reflect.Ptr {
+		if out.IsNil() {
+			out.Set(reflect.New(out.Type().Elem()))
+		}
+		return d.decode(path, in, out.Elem())
+	}
+	if out.Type() == durationType {
+		return d.decodeDuration(path, in, out)
+	}
+	if reflect.PtrTo(out.Type()).Implements(textUnmarshalerType) && !isCollection(in, out) {
+		return d.decodeText(path, in, out)
+	}
+	switch out.Kind() {
+	case reflect.Interface:
+		v := reflect.ValueOf(in)
+		if !v.Type().AssignableTo(out.Type()) {
+			return d.mismatch(path, in, out)
+		}
+		out.Set(v)
+		return nil
+	case reflect.String:
+		v, ok := in.(string)
+		if !ok {
+			return d.mismatch(path, in, out)
+		}
+		out.SetString(v)
+		return nil
+	case reflect.Bool:
+		v, ok := in.(bool)
+		if !ok {
+			return d.mismatch(path, in, out)
+		}
+		out.SetBool(v)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return d.decodeInt(path, in, out)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return d.decodeUint(path, in, out)
+	case reflect.Float32, reflect.Float64:
+		return d.decodeFloat(path, in, out)
+	case reflect.Slice:
+		return d.decodeSlice(path, in, out)
+	case reflect.Map:
+		return d.decodeMap(path, in, out)
+	case reflect.Struct:
+		return d.decodeStruct(path, in, out)
+	}
+	return &PathError{
+		Path: path,
+		Err:  fmt.Errorf("cannot decode into unsupported type %s", out.Type()),
+	}
+}
+
+// decodeDuration decodes a duration string into out.
+func (d *decoder) decodeDuration(path string, in interface{}, out reflect.Value) error {
+	v, ok := in.(string)
+	if !ok {
+		return d.mismatch(path, in, out)
+	}
+	dur, err := time.ParseDuration(v)
+	if err != nil {
+		return &PathError{
+			Path: path,
+			Err:  err,
+		}
+	}
+	out.SetInt(int64(dur))
+	return nil
+}
+
+// decodeText decodes a string into out, which implements
+// encoding.TextUnmarshaler.
+func (d *decoder) decodeText(path string, in interface{}, out reflect.Value) error {
+	v, ok := in.(string)
+	if !ok {
+		return d.mismatch(path, in, out)
+	}
+	if err := out.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v)); err != nil {
+		return &PathError{
+			Path: path,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// isCollection reports whether the given value is a JSON array or object to
+// be decoded into out, which is a slice or a map. In this case, values such
+// as a StringSlice are decoded element by element rather than as text.
+func isCollection(in interface{}, out reflect.Value) bool {
+	switch in.(type) {
+	case []interface{}:
+		return out.Kind() == reflect.Slice
+	case map[string]interface{}, StringMap:
+		return out.Kind() == reflect.Map
+	}
+	return false
+}
+
+// decodeInt decodes an integer number into out.
+func (d *decoder) decodeInt(path string, in interface{}, out reflect.Value) error {
+	var n int64
+	switch v := in.(type) {
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= -math.MinInt64 {
+			return d.mismatch(path, in, out)
+		}
+		n = int64(v)
+	case json.Number:
+		var err error
+		if n, err = strconv.ParseInt(v.String(), 10, 64); err != nil {
+			return d.mismatch(path, in, out)
+		}
+	case int:
+		n = int64(v)
+	case int64:
+		n = v
+	default:
+		return d.mismatch(path, in, out)
+	}
+	if out.OverflowInt(n) {
+		return d.overflow(path, in, out)
+	}
+	out.SetInt(n)
+	return nil
+}
+
+// decodeUint decodes a non-negative integer number into out.
+func (d *decoder) decodeUint(path string, in interface{}, out reflect.Value) error {
+	var n uint64
+	switch v := in.(type) {
+	case float64:
+		if v != math.Trunc(v) || v < 0 || v >= math.MaxUint64 {
+			return d.mismatch(path, in, out)
+		}
+		n = uint64(v)
+	case json.Number:
+		var err error
+		if n, err = strconv.ParseUint(v.String(), 10, 64); err != nil {
+			return d.mismatch(path, in, out)
+		}
+	case int:
+		if v < 0 {
+			return d.mismatch(path, in, out)
+		}
+		n = uint64(v)
+	case int64:
+		if v < 0 {
+			return d.mismatch(path, in, out)
+		}
+		n = uint64(v)
+	default:
+		return d.mismatch(path, in, out)
+	}
+	if out.OverflowUint(n) {
+		return d.overflow(path, in, out)
+	}
+	out.SetUint(n)
+	return nil
+}
+
+// decodeFloat decodes a number into out.
+func (d *decoder) decodeFloat(path string, in interface{}, out reflect.Value) error {
+	var f float64
+	switch v := in.(type) {
+	case float64:
+		f = v
+	case json.Number:
+		var err error
+		if f, err = v.Float64(); err != nil {
+			return d.mismatch(path, in, out)
+		}
+	case int:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	default:
+		return d.mismatch(path, in, out)
+	}
+	if out.OverflowFloat(f) {
+		return d.overflow(path, in, out)
+	}
+	out.SetFloat(f)
+	return nil
+}
+
+// decodeSlice decodes a JSON array into out. The path of each element is
+// the path of the slice followed by the element index.
+func (d *decoder) decodeSlice(path string, in interface{}, out reflect.Value) error {
+	v, ok := in.([]interface{})
+	if !ok {
+		return d.mismatch(path, in, out)
+	}
+	s := reflect.MakeSlice(out.Type(), len(v), len(v))
+	for i, elem := range v {
+		if err := d.decode(joinPath(path, strconv.Itoa(i)), elem, s.Index(i)); err != nil {
+			return err
+		}
+	}
+	out.Set(s)
+	return nil
+}
+
+// decodeMap decodes a JSON object into out, which must be a map with string
+// keys.
+func (d *decoder) decodeMap(path string, in interface{}, out reflect.Value) error {
+	m, ok := asMap(in)
+	if !ok || out.Type().Key().Kind() != reflect.String {
+		return d.mismatch(path, in, out)
+	}
+	result := reflect.MakeMapWithSize(out.Type(), len(m))
+	for _, key := range sortedKeys(m) {
+		elem := reflect.New(out.Type().Elem()).Elem()
+		if err := d.decode(joinPath(path, key), m[key], elem); err != nil {
+			return err
+		}
+		result.SetMapIndex(reflect.ValueOf(key).Convert(out.Type().Key()), elem)
+	}
+	out.Set(result)
+	return nil
+}
+
+// decodeStruct decodes a JSON object into out, which is a struct.
+func (d *decoder) decodeStruct(path string, in interface{}, out reflect.Value) error {
+	m, ok := asMap(in)
+	if !ok {
+		return d.mismatch(path, in, out)
+	}
+	fields := structFields(out.Type())
+	for _, key := range sortedKeys(m) {
+		f, ok := fields.lookup(key)
+		if !ok {
+			if d.opts.disallowUnknownFields {
+				return &PathError{
+					Path: joinPath(path, key),
+					Err:  errors.New("unknown field"),
+				}
+			}
+			continue
+		}
+		if err := d.decode(joinPath(path, key), m[key], out.FieldByIndex(f.index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mismatch returns an error reporting that the given value cannot be decoded
+// into out.
+func (d *decoder) mismatch(path string, in interface{}, out reflect.Value) error {
+	return &PathError{
+		Path: path,
+		Err:  fmt.Errorf("cannot decode %s into %s", describeValue(in), out.Type()),
+	}
+}
+
+// overflow returns an error reporting that the given number overflows out.
+func (d *decoder) overflow(path string, in interface{}, out reflect.Value) error {
+	return &PathError{
+		Path: path,
+		Err:  fmt.Errorf("number %v overflows %s", in, out.Type()),
+	}
+}
+
+// field describes a struct field that can be decoded from a map entry.
+type field struct {
+	name  string
+	index []int
+}
+
+// fieldList holds the decodable fields of a struct.
+type fieldList []field
+
+// lookup returns the field with the given name. Exact matches are preferred
+// over case insensitive ones.
+func (l fieldList) lookup(name string) (field, bool) {
+	for _, f := range l {
+		if f.name == name {
+			return f, true
+		}
+	}
+	for _, f := range l {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// structFields returns the decodable fields of the given struct type,
+// including the fields of squashed embedded structs.
+func structFields(t reflect.Type) fieldList {
+	var fields fieldList
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts, _ := strings.Cut(sf.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && hasTagOption(opts, "squash") {
+			for _, f := range structFields(sf.Type) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{
+			name:  name,
+			index: []int{i},
+		})
+	}
+	return fields
+}
+
+// hasTagOption reports whether the given comma separated tag options include
+// the given option.
+func hasTagOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// asMap returns the given value as a map, if it is one.
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case StringMap:
+		return m, true
+	}
+	return nil, false
+}
+
+// describeValue returns a short description of the kind of the given decoded
+// JSON value, for use in error messages.
+func describeValue(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, json.Number, int, int64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}, StringMap:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,226 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+type DecodeBase struct {
+	Name string `mapstructure:"name"`
+}
+
+type decodeServer struct {
+	Addr    net.IP `mapstructure:"addr"`
+	Port    uint16 `mapstructure:"port"`
+	Timeout time.Duration
+}
+
+type decodeConfig struct {
+	DecodeBase `mapstructure:",squash"`
+	Debug      bool                   `mapstructure:"debug"`
+	Retries    int                    `mapstructure:"retries"`
+	Ratio      float64                `mapstructure:"ratio"`
+	Tags       []string               `mapstructure:"tags"`
+	Server     *decodeServer          `mapstructure:"server"`
+	Labels     map[string]string      `mapstructure:"labels"`
+	Extra      interface{}            `mapstructure:"extra"`
+	Raw        map[string]interface{} `mapstructure:"raw"`
+	Ignored    string                 `mapstructure:"-"`
+}
+
+var decodeTests = []struct {
+	about         string
+	value         string
+	opts          []flagutils.DecodeOption
+	expectedValue decodeConfig
+	expectedError string
+}{{
+	about: "all fields",
+	value: `{
+		"name": "gisf",
+		"debug": true,
+		"retries": 3,
+		"ratio": 0.5,
+		"tags": ["a", "b"],
+		"server": {"addr": "1.2.3.4", "port": 8080, "timeout": "1m"},
+		"labels": {"env": "prod"},
+		"extra": [1, "two"],
+		"raw": {"nested": {"key": null}}
+	}`,
+	expectedValue: decodeConfig{
+		DecodeBase: DecodeBase{
+			Name: "gisf",
+		},
+		Debug:   true,
+		Retries: 3,
+		Ratio:   0.5,
+		Tags:    []string{"a", "b"},
+		Server: &decodeServer{
+			Addr:    net.ParseIP("1.2.3.4"),
+			Port:    8080,
+			Timeout: time.Minute,
+		},
+		Labels: map[string]string{
+			"env": "prod",
+		},
+		Extra: []interface{}{1.0, "two"},
+		Raw: map[string]interface{}{
+			"nested": map[string]interface{}{
+				"key": nil,
+			},
+		},
+	},
+}, {
+	about: "pairs",
+	value: "server.port=443,retries=2,Debug=true",
+	expectedValue: decodeConfig{
+		Debug:   true,
+		Retries: 2,
+		Server: &decodeServer{
+			Port: 443,
+		},
+	},
+}, {
+	about: "unknown fields ignored",
+	value: `{"name": "gisf", "Ignored": "value", "unknown": 42}`,
+	expectedValue: decodeConfig{
+		DecodeBase: DecodeBase{
+			Name: "gisf",
+		},
+	},
+}, {
+	about: "null values",
+	value: `{"tags": null, "server": null}`,
+}, {
+	about:         "error: unknown field",
+	value:         `{"name": "gisf", "unknown": 42}`,
+	opts:          []flagutils.DecodeOption{flagutils.DisallowUnknownFields()},
+	expectedError: `invalid value at "unknown": unknown field`,
+}, {
+	about:         "error: unknown nested field",
+	value:         `{"server": {"port": 80, "host": "localhost"}}`,
+	opts:          []flagutils.DecodeOption{flagutils.DisallowUnknownFields()},
+	expectedError: `invalid value at "server.host": unknown field`,
+}, {
+	about:         "error: ignored field",
+	value:         `{"Ignored": "value"}`,
+	opts:          []flagutils.DecodeOption{flagutils.DisallowUnknownFields()},
+	expectedError: `invalid value at "Ignored": unknown field`,
+}, {
+	about:         "error: type mismatch",
+	value:         `{"retries": "many"}`,
+	expectedError: `invalid value at "retries": cannot decode string into int`,
+}, {
+	about:         "error: fractional number",
+	value:         `{"retries": 1.5}`,
+	expectedError: `invalid value at "retries": cannot decode number into int`,
+}, {
+	about:         "error: negative unsigned number",
+	value:         `{"server": {"port": -1}}`,
+	expectedError: `invalid value at "server.port": cannot decode number into uint16`,
+}, {
+	about:         "error: overflow",
+	value:         `{"server": {"port": 70000}}`,
+	expectedError: `invalid value at "server.port": number 70000 overflows uint16`,
+}, {
+	about:         "error: invalid slice element",
+	value:         `{"tags": ["a", 2]}`,
+	expectedError: `invalid value at "tags.1": cannot decode number into string`,
+}, {
+	about:         "error: invalid map value",
+	value:         `{"labels": {"env": true}}`,
+	expectedError: `invalid value at "labels.env": cannot decode boolean into string`,
+}, {
+	about:         "error: object into string",
+	value:         `{"name": {"first": "gisf"}}`,
+	expectedError: `invalid value at "name": cannot decode object into string`,
+}, {
+	about:         "error: invalid duration",
+	value:         `{"server": {"timeout": "forever"}}`,
+	expectedError: `invalid value at "server.timeout": time: invalid duration "forever"`,
+}, {
+	about:         "error: invalid text",
+	value:         `{"server": {"addr": "bad-wolf"}}`,
+	expectedError: `invalid value at "server.addr": invalid IP address: bad-wolf`,
+}}
+
+func TestStringMapDecode(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range decodeTests {
+		c.Run(test.about, func(c *qt.C) {
+			var m flagutils.StringMap
+			err := m.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			var conf decodeConfig
+			err = m.Decode(&conf, test.opts...)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				var pathErr *flagutils.PathError
+				c.Assert(errors.As(err, &pathErr), qt.Equals, true)
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(conf, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestStringMapDecodeJSONNumber(t *testing.T) {
+	c := qt.New(t)
+	m := flagutils.StringMap{
+		"retries": json.Number("3"),
+		"ratio":   json.Number("0.25"),
+	}
+	var conf decodeConfig
+	err := m.Decode(&conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf.Retries, qt.Equals, 3)
+	c.Assert(conf.Ratio, qt.Equals, 0.25)
+}
+
+func TestStringMapDecodeIntoMap(t *testing.T) {
+	c := qt.New(t)
+	m := flagutils.StringMap{
+		"a": 1.0,
+		"b": 2.0,
+	}
+	var target map[string]int
+	err := m.Decode(&target)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(target, qt.DeepEquals, map[string]int{"a": 1, "b": 2})
+}
+
+func TestStringMapDecodeInvalidTarget(t *testing.T) {
+	c := qt.New(t)
+	m := flagutils.StringMap{}
+	var conf decodeConfig
+	err := m.Decode(conf)
+	c.Assert(err, qt.ErrorMatches, `cannot decode into flagutils_test.decodeConfig: a non-nil pointer is required`)
+	err = m.Decode((*decodeConfig)(nil))
+	c.Assert(err, qt.ErrorMatches, `cannot decode into \*flagutils_test.decodeConfig: a non-nil pointer is required`)
+}
+
+func TestStringMapDecodeStringSlice(t *testing.T) {
+	c := qt.New(t)
+	m := flagutils.StringMap{
+		"tags":  []interface{}{"a", "b,c"},
+		"names": "d,e",
+	}
+	var conf struct {
+		Tags  flagutils.StringSlice
+		Names flagutils.StringSlice
+	}
+	err := m.Decode(&conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf.Tags, qt.DeepEquals, flagutils.StringSlice{"a", "b,c"})
+	c.Assert(conf.Names, qt.DeepEquals, flagutils.StringSlice{"d", "e"})
+}
@@ -0,0 +1,160 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Duration defines a duration flag with specified name, default value, and
+// usage string. In addition to the units accepted by time.ParseDuration, the
+// flag value can include days ("d") and weeks ("w"), for instance "30d" or
+// "1w2d12h". The return value is the address of a duration variable that
+// stores the value of the flag.
+func Duration(name string, value time.Duration, usage string, opts ...Option) *time.Duration {
+	var d time.Duration
+	DurationVar(&d, name, value, usage, opts...)
+	return &d
+}
+
+// DurationVar defines a duration flag with specified name, default value,
+// and usage string. See Duration for details on the accepted units. The
+// argument p points to a duration variable in which to store the value of
+// the flag.
+func DurationVar(p *time.Duration, name string, value time.Duration, usage string, opts ...Option) {
+	DurationVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// DurationVarFS is like DurationVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func DurationVarFS(fs *flag.FlagSet, p *time.Duration, name string, value time.Duration, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*DurationValue)(p), name, usage, opts)
+}
+
+// DurationValue holds a duration that can be provided via the command line
+// using the time.ParseDuration syntax extended with days ("d", 24 hours) and
+// weeks ("w", 7 days).
+type DurationValue time.Duration
+
+// String implements flag.Value by returning the duration as a string, using
+// weeks and days where possible.
+func (d *DurationValue) String() string {
+	return formatDuration(time.Duration(*d))
+}
+
+// Set implements flag.Value by parsing the given duration.
+func (d *DurationValue) Set(value string) error {
+	v, err := parseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = DurationValue(v)
+	return nil
+}
+
+// Type implements pflag.Value by returning "duration".
+func (d *DurationValue) Type() string {
+	return "duration"
+}
+
+// parseDuration parses the given duration, also accepting the "d" and "w"
+// units. Each number and unit pair is parsed by time.ParseDuration, with days
+// and weeks being converted to hours.
+func parseDuration(value string) (time.Duration, error) {
+	s := value
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	var total time.Duration
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool {
+			return r != '.' && (r < '0' || r > '9')
+		})
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		number := s[:i]
+		s = s[i:]
+		j := strings.IndexFunc(s, func(r rune) bool {
+			return r == '.' || (r >= '0' && r <= '9')
+		})
+		if j == -1 {
+			j = len(s)
+		}
+		unit := s[:j]
+		s = s[j:]
+		multiplier := time.Duration(1)
+		switch unit {
+		case "d":
+			unit, multiplier = "h", 24
+		case "w":
+			unit, multiplier = "h", 7*24
+		}
+		d, err := time.ParseDuration(number + unit)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		if d > (math.MaxInt64-total)/multiplier {
+			return 0, fmt.Errorf("invalid duration %q: out of range", value)
+		}
+		total += d * multiplier
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// formatDuration returns the given duration as a string, for instance
+// "2w3d12h". Durations shorter than a day are formatted like
+// time.Duration.String, without trailing zero units.
+func formatDuration(d time.Duration) string {
+	const (
+		day  = 24 * time.Hour
+		week = 7 * day
+	)
+	if d == 0 {
+		return "0s"
+	}
+	if d == math.MinInt64 {
+		// The absolute value cannot be represented.
+		return d.String()
+	}
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	if w := d / week; w > 0 {
+		fmt.Fprintf(&b, "%dw", w)
+		d -= w * week
+	}
+	if n := d / day; n > 0 {
+		fmt.Fprintf(&b, "%dd", n)
+		d -= n * day
+	}
+	if d > 0 {
+		s := d.String()
+		if strings.HasSuffix(s, "m0s") {
+			s = s[:len(s)-2]
+		}
+		if strings.HasSuffix(s, "h0m") {
+			s = s[:len(s)-2]
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
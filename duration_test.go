@@ -0,0 +1,164 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.DurationValue)(nil)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+var durationTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        time.Duration
+	expectedValue       time.Duration
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "standard units",
+	name:                "standard",
+	value:               "1h30m",
+	expectedValue:       90 * time.Minute,
+	expectedStringValue: "1h30m",
+}, {
+	about:               "sub-second units",
+	name:                "subsecond",
+	value:               "1.5s",
+	expectedValue:       1500 * time.Millisecond,
+	expectedStringValue: "1.5s",
+}, {
+	about:               "days",
+	name:                "days",
+	value:               "30d",
+	expectedValue:       30 * day,
+	expectedStringValue: "4w2d",
+}, {
+	about:               "weeks",
+	name:                "weeks",
+	value:               "2w",
+	expectedValue:       2 * week,
+	expectedStringValue: "2w",
+}, {
+	about:               "fractional days",
+	name:                "fractional",
+	value:               "1.5d",
+	expectedValue:       36 * time.Hour,
+	expectedStringValue: "1d12h",
+}, {
+	about:               "mixed units",
+	name:                "mixed",
+	value:               "1w2d3h4m5s",
+	expectedValue:       week + 2*day + 3*time.Hour + 4*time.Minute + 5*time.Second,
+	expectedStringValue: "1w2d3h4m5s",
+}, {
+	about:               "negative",
+	name:                "negative",
+	value:               "-1d",
+	expectedValue:       -day,
+	expectedStringValue: "-1d",
+}, {
+	about:               "zero",
+	name:                "zero",
+	value:               "0",
+	expectedValue:       0,
+	expectedStringValue: "0s",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "7d",
+	defaultValue:  time.Hour,
+	expectedValue: week,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  time.Hour,
+	expectedValue: time.Hour,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid duration ""`,
+}, {
+	about:         "error: missing unit",
+	name:          "err",
+	value:         "30",
+	expectedError: `invalid duration "30"`,
+}, {
+	about:         "error: unknown unit",
+	name:          "err",
+	value:         "3y",
+	expectedError: `invalid duration "3y"`,
+}, {
+	about:         "error: out of range",
+	name:          "err",
+	value:         "100000w",
+	expectedError: `invalid duration "100000w": out of range`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "d",
+	defaultValue:  time.Hour,
+	expectedValue: time.Hour,
+	expectedError: `invalid duration "d"`,
+}}
+
+func TestDuration(t *testing.T) {
+	for _, test := range durationTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Duration(test.name, test.defaultValue, "duration usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestDurationVar(t *testing.T) {
+	for _, test := range durationTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v time.Duration
+			flagutils.DurationVar(&v, test.name, test.defaultValue, "duration usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestDurationValueString(t *testing.T) {
+	for _, test := range durationTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != 0 || test.expectedError != "" {
+				return
+			}
+			var v flagutils.DurationValue
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net/mail"
+)
+
+// Email defines an email address flag with specified name, default value,
+// and usage string. The value is parsed as an RFC 5322 address, and only its
+// address part is stored, so that "Bad Wolf <wolf@example.com>" results in
+// "wolf@example.com". The return value is the address of a string variable
+// that stores the value of the flag.
+func Email(name string, value string, usage string, opts ...Option) *string {
+	var s string
+	EmailVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// EmailVar defines an email address flag with specified name, default value,
+// and usage string. The argument p points to a string variable in which to
+// store the address part of the flag value.
+func EmailVar(p *string, name string, value string, usage string, opts ...Option) {
+	EmailVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// EmailVarFS is like EmailVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func EmailVarFS(fs *flag.FlagSet, p *string, name string, value string, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*EmailValue)(p), name, usage, opts)
+}
+
+// EmailValue holds an email address that can be provided via the command
+// line in any of the forms accepted by mail.ParseAddress.
+type EmailValue string
+
+// String implements flag.Value by returning the email address.
+func (e *EmailValue) String() string {
+	return string(*e)
+}
+
+// Set implements flag.Value by parsing the given email address.
+func (e *EmailValue) Set(value string) error {
+	addr, err := mail.ParseAddress(value)
+	if err != nil {
+		return fmt.Errorf("invalid email address %q: %v", value, err)
+	}
+	*e = EmailValue(addr.Address)
+	return nil
+}
+
+// Type implements pflag.Value by returning "email".
+func (e *EmailValue) Type() string {
+	return "email"
+}
@@ -0,0 +1,100 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.EmailValue)(nil)
+
+var emailTests = []struct {
+	about         string
+	name          string
+	value         string
+	defaultValue  string
+	expectedValue string
+	expectedError string
+}{{
+	about:         "address",
+	name:          "address",
+	value:         "wolf@example.com",
+	expectedValue: "wolf@example.com",
+}, {
+	about:         "address with name",
+	name:          "named",
+	value:         "Bad Wolf <wolf@example.com>",
+	expectedValue: "wolf@example.com",
+}, {
+	about:         "address in angle brackets",
+	name:          "brackets",
+	value:         "<rose@example.com>",
+	expectedValue: "rose@example.com",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "wolf@example.com",
+	defaultValue:  "admin@example.com",
+	expectedValue: "wolf@example.com",
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  "admin@example.com",
+	expectedValue: "admin@example.com",
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid email address "": mail: no address`,
+}, {
+	about:         "error: missing domain",
+	name:          "err",
+	value:         "wolf",
+	expectedError: `invalid email address "wolf": mail: missing '@' or angle-addr`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "wolf@",
+	defaultValue:  "admin@example.com",
+	expectedValue: "admin@example.com",
+	expectedError: `invalid email address "wolf@": mail: .*`,
+}}
+
+func TestEmail(t *testing.T) {
+	for _, test := range emailTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Email(test.name, test.defaultValue, "email usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestEmailVar(t *testing.T) {
+	for _, test := range emailTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v string
+			flagutils.EmailVar(&v, test.name, test.defaultValue, "email usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
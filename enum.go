@@ -0,0 +1,84 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Enum defines a string flag with specified name, default value, allowed
+// values and usage string. The value must be one of the allowed values,
+// which are also listed in the usage string. The return value is the address
+// of a string variable that stores the value of the flag. Enum panics if the
+// default value is not empty and is not one of the allowed values.
+func Enum(name string, value string, allowed []string, usage string, opts ...Option) *string {
+	var s string
+	EnumVar(&s, name, value, allowed, usage, opts...)
+	return &s
+}
+
+// EnumVar defines a string flag with specified name, default value, allowed
+// values and usage string. The value must be one of the allowed values,
+// which are also listed in the usage string. The argument p points to a
+// string variable in which to store the value of the flag. EnumVar panics if
+// the default value is not empty and is not one of the allowed values.
+func EnumVar(p *string, name string, value string, allowed []string, usage string, opts ...Option) {
+	EnumVarFS(flag.CommandLine, p, name, value, allowed, usage, opts...)
+}
+
+// EnumVarFS is like EnumVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func EnumVarFS(fs *flag.FlagSet, p *string, name string, value string, allowed []string, usage string, opts ...Option) {
+	if value != "" {
+		if err := checkChoice(value, allowed); err != nil {
+			panic(fmt.Sprintf("flagutils: invalid default value of flag -%s: %v", name, err))
+		}
+	}
+	*p = value
+	defineVar(fs, NewEnumValue(p, allowed), name, usageWithChoices(usage, allowed), opts)
+}
+
+// NewEnumValue returns an EnumValue storing its value in p and only
+// accepting the given allowed values.
+func NewEnumValue(p *string, allowed []string) *EnumValue {
+	return &EnumValue{
+		p:       p,
+		allowed: allowed,
+	}
+}
+
+// EnumValue holds a string that can be provided via the command line, and
+// that must be included in a predefined set of allowed values.
+type EnumValue struct {
+	p       *string
+	allowed []string
+}
+
+// String implements flag.Value by returning the string value.
+func (s *EnumValue) String() string {
+	if s.p == nil {
+		return ""
+	}
+	return *s.p
+}
+
+// Set implements flag.Value by checking the value is allowed and storing it.
+func (s *EnumValue) Set(value string) error {
+	if err := checkChoice(value, s.allowed); err != nil {
+		return err
+	}
+	*s.p = value
+	return nil
+}
+
+// Type implements pflag.Value by returning "enum".
+func (s *EnumValue) Type() string {
+	return "enum"
+}
+
+// usageWithChoices returns the given usage string extended with the list of
+// allowed values.
+func usageWithChoices(usage string, allowed []string) string {
+	return usage + " (allowed values: " + formatChoices(allowed) + ")"
+}
@@ -0,0 +1,108 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.EnumValue)(nil)
+
+var enumAllowed = []string{"debug", "info", "error"}
+
+var enumTests = []struct {
+	about         string
+	name          string
+	value         string
+	defaultValue  string
+	expectedValue string
+	expectedError string
+}{{
+	about:         "allowed value",
+	name:          "allowed",
+	value:         "info",
+	expectedValue: "info",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "error",
+	defaultValue:  "debug",
+	expectedValue: "error",
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  "debug",
+	expectedValue: "debug",
+}, {
+	about:         "error: value not allowed",
+	name:          "err",
+	value:         "warning",
+	expectedError: `invalid value "warning": allowed values are "debug", "info", "error"`,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid value "": allowed values are "debug", "info", "error"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "INFO",
+	defaultValue:  "debug",
+	expectedValue: "debug",
+	expectedError: `invalid value "INFO": allowed values are "debug", "info", "error"`,
+}}
+
+func TestEnum(t *testing.T) {
+	for _, test := range enumTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Enum(test.name, test.defaultValue, enumAllowed, "enum usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestEnumVar(t *testing.T) {
+	for _, test := range enumTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v string
+			flagutils.EnumVar(&v, test.name, test.defaultValue, enumAllowed, "enum usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestEnumUsage(t *testing.T) {
+	runIsolated(t, "usage", func(c *qt.C) {
+		flagutils.Enum("level", "info", enumAllowed, "the log level")
+		c.Assert(flag.Lookup("level").Usage, qt.Equals, `the log level (allowed values: "debug", "info", "error")`)
+	})
+}
+
+func TestEnumInvalidDefault(t *testing.T) {
+	c := qt.New(t)
+	fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+	c.Assert(func() {
+		var v string
+		flagutils.EnumVarFS(fs, &v, "level", "warning", enumAllowed, "enum usage")
+	}, qt.PanicMatches, `flagutils: invalid default value of flag -level: invalid value "warning": allowed values are "debug", "info", "error"`)
+}
@@ -0,0 +1,109 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// EnumSlice defines a string slice flag with specified name, allowed values,
+// default value and usage string. Each element of the slice must be one of
+// the allowed values. The return value is the address of a string slice
+// variable that stores the value of the flag. EnumSlice panics if the default
+// value includes elements which are not allowed.
+func EnumSlice(name string, allowed []string, value []string, usage string, opts ...Option) *[]string {
+	var s []string
+	EnumSliceVar(&s, name, allowed, value, usage, opts...)
+	return &s
+}
+
+// EnumSliceVar defines a string slice flag with specified name, allowed
+// values, default value and usage string. Each element of the slice must be
+// one of the allowed values. The argument p points to a string slice variable
+// in which to store the value of the flag. EnumSliceVar panics if the default
+// value includes elements which are not allowed.
+func EnumSliceVar(p *[]string, name string, allowed []string, value []string, usage string, opts ...Option) {
+	EnumSliceVarFS(flag.CommandLine, p, name, allowed, value, usage, opts...)
+}
+
+// EnumSliceVarFS is like EnumSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func EnumSliceVarFS(fs *flag.FlagSet, p *[]string, name string, allowed []string, value []string, usage string, opts ...Option) {
+	for _, v := range value {
+		if err := checkChoice(v, allowed); err != nil {
+			panic(fmt.Sprintf("flagutils: invalid default value of flag -%s: %v", name, err))
+		}
+	}
+	*p = value
+	defineVar(fs, NewEnumSliceValue(p, allowed), name, usage, opts)
+}
+
+// NewEnumSliceValue returns an EnumSliceValue storing its value in p and
+// only accepting the given allowed values.
+func NewEnumSliceValue(p *[]string, allowed []string) *EnumSliceValue {
+	return &EnumSliceValue{
+		p:       p,
+		allowed: allowed,
+	}
+}
+
+// EnumSliceValue holds a slice of strings that can be provided via the
+// command line as a comma separated list of values, each one included in a
+// predefined set of allowed values.
+type EnumSliceValue struct {
+	p       *[]string
+	allowed []string
+}
+
+// String implements flag.Value by returning the slice as a string.
+func (s *EnumSliceValue) String() string {
+	if s.p == nil {
+		return ""
+	}
+	return strings.Join(*s.p, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *EnumSliceValue) Set(value string) error {
+	*s.p = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := checkChoice(v, s.allowed); err != nil {
+			return err
+		}
+	}
+	*s.p = values
+	return nil
+}
+
+// Type implements pflag.Value by returning "enumSlice".
+func (s *EnumSliceValue) Type() string {
+	return "enumSlice"
+}
+
+// checkChoice returns an error if the given value is not one of the allowed
+// choices.
+func checkChoice(value string, allowed []string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q: allowed values are %s", value, formatChoices(allowed))
+}
+
+// formatChoices returns the given choices as a comma separated list of
+// quoted strings.
+func formatChoices(choices []string) string {
+	quoted := make([]string, len(choices))
+	for i, c := range choices {
+		quoted[i] = fmt.Sprintf("%q", c)
+	}
+	return strings.Join(quoted, ", ")
+}
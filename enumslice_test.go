@@ -0,0 +1,120 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.EnumSliceValue)(nil)
+
+var enumSliceAllowed = []string{"gisf", "profile", "status"}
+
+var enumSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []string
+	expectedValue       []string
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single value",
+	name:                "single",
+	value:               "gisf",
+	expectedValue:       []string{"gisf"},
+	expectedStringValue: "gisf",
+}, {
+	about:               "multiple values",
+	name:                "multiple",
+	value:               " status,gisf , profile",
+	expectedValue:       []string{"status", "gisf", "profile"},
+	expectedStringValue: "status,gisf,profile",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "profile",
+	defaultValue:  []string{"gisf"},
+	expectedValue: []string{"profile"},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []string{"gisf", "status"},
+	expectedValue: []string{"gisf", "status"},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: value not allowed",
+	name:          "err",
+	value:         "gisf,bad-wolf",
+	expectedError: `invalid value "bad-wolf": allowed values are "gisf", "profile", "status"`,
+}, {
+	about:         "error: case sensitive",
+	name:          "err",
+	value:         "GISF",
+	expectedError: `invalid value "GISF": allowed values are "gisf", "profile", "status"`,
+}}
+
+func TestEnumSlice(t *testing.T) {
+	for _, test := range enumSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.EnumSlice(test.name, enumSliceAllowed, test.defaultValue, "enum slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestEnumSliceVar(t *testing.T) {
+	for _, test := range enumSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []string
+			flagutils.EnumSliceVar(&v, test.name, enumSliceAllowed, test.defaultValue, "enum slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestEnumSliceValueString(t *testing.T) {
+	for _, test := range enumSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v []string
+			s := flagutils.NewEnumSliceValue(&v, enumSliceAllowed)
+			s.Set(test.value)
+			c.Assert(s.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestEnumSliceInvalidDefault(t *testing.T) {
+	runIsolated(t, "invalid default", func(c *qt.C) {
+		c.Assert(func() {
+			flagutils.EnumSlice("levels", enumSliceAllowed, []string{"gisf", "verbose"}, "enum slice usage")
+		}, qt.PanicMatches, `flagutils: invalid default value of flag -levels: invalid value "verbose": allowed values are .*`)
+	})
+}
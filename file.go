@@ -0,0 +1,115 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// FileCheck specifies the checks performed on the path provided to a File
+// flag.
+type FileCheck int
+
+const (
+	// FileExists requires the path to exist and to be a regular file.
+	FileExists FileCheck = iota
+	// FileReadable requires the path to be an existing regular file that
+	// can be opened for reading.
+	FileReadable
+	// FileNotExists requires the path not to exist, for instance for output
+	// files which must not be overwritten.
+	FileNotExists
+)
+
+// File defines a file path flag with specified name, default value, check
+// and usage string. The path is checked when the flag is set, so that
+// missing input files are reported as flag errors. The default value is not
+// checked. The return value is the address of a string variable that stores
+// the value of the flag.
+func File(name string, value string, check FileCheck, usage string, opts ...Option) *string {
+	var s string
+	FileVar(&s, name, value, check, usage, opts...)
+	return &s
+}
+
+// FileVar defines a file path flag with specified name, default value, check
+// and usage string. The argument p points to a string variable in which to
+// store the value of the flag.
+func FileVar(p *string, name string, value string, check FileCheck, usage string, opts ...Option) {
+	FileVarFS(flag.CommandLine, p, name, value, check, usage, opts...)
+}
+
+// FileVarFS is like FileVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func FileVarFS(fs *flag.FlagSet, p *string, name string, value string, check FileCheck, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewFileValue(p, check), name, usage, opts)
+}
+
+// NewFileValue returns a FileValue storing its value in p and performing the
+// given check on the provided paths.
+func NewFileValue(p *string, check FileCheck) *FileValue {
+	return &FileValue{
+		p:     p,
+		check: check,
+	}
+}
+
+// FileValue holds a file path that can be provided via the command line.
+type FileValue struct {
+	p     *string
+	check FileCheck
+}
+
+// String implements flag.Value by returning the file path.
+func (f *FileValue) String() string {
+	if f.p == nil {
+		return ""
+	}
+	return *f.p
+}
+
+// Set implements flag.Value by checking and storing the given file path.
+func (f *FileValue) Set(value string) error {
+	if value == "" {
+		return errors.New("invalid file: empty path")
+	}
+	info, err := os.Stat(value)
+	if f.check == FileNotExists {
+		if err == nil {
+			return fmt.Errorf("invalid file %q: file already exists", value)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("invalid file %q: %v", value, err)
+		}
+		*f.p = value
+		return nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("invalid file %q: no such file", value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid file %q: %v", value, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("invalid file %q: not a regular file", value)
+	}
+	if f.check == FileReadable {
+		file, err := os.Open(value)
+		if err != nil {
+			return fmt.Errorf("invalid file %q: %v", value, err)
+		}
+		file.Close()
+	}
+	*f.p = value
+	return nil
+}
+
+// Type implements pflag.Value by returning "file".
+func (f *FileValue) Type() string {
+	return "file"
+}
@@ -0,0 +1,137 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.FileValue)(nil)
+
+var fileTests = []struct {
+	about         string
+	name          string
+	value         string
+	check         flagutils.FileCheck
+	expectedValue string
+	expectedError string
+}{{
+	about:         "existing file",
+	name:          "exists",
+	value:         "input.txt",
+	expectedValue: "input.txt",
+}, {
+	about:         "readable file",
+	name:          "readable",
+	value:         "input.txt",
+	check:         flagutils.FileReadable,
+	expectedValue: "input.txt",
+}, {
+	about:         "file not existing",
+	name:          "notexists",
+	value:         "output.txt",
+	check:         flagutils.FileNotExists,
+	expectedValue: "output.txt",
+}, {
+	about:         "error: empty path",
+	name:          "err",
+	expectedError: "invalid file: empty path",
+}, {
+	about:         "error: no such file",
+	name:          "err",
+	value:         "output.txt",
+	expectedError: `invalid file ".*output.txt": no such file`,
+}, {
+	about:         "error: no such readable file",
+	name:          "err",
+	value:         "output.txt",
+	check:         flagutils.FileReadable,
+	expectedError: `invalid file ".*output.txt": no such file`,
+}, {
+	about:         "error: directory",
+	name:          "err",
+	value:         "dir",
+	expectedError: `invalid file ".*dir": not a regular file`,
+}, {
+	about:         "error: file already exists",
+	name:          "err",
+	value:         "input.txt",
+	check:         flagutils.FileNotExists,
+	expectedError: `invalid file ".*input.txt": file already exists`,
+}}
+
+func TestFile(t *testing.T) {
+	for _, test := range fileTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			dir := makeFileTree(c)
+			v := flagutils.File(test.name, "default.txt", test.check, "file usage")
+			path := test.value
+			if path != "" {
+				path = filepath.Join(dir, path)
+			}
+			err := flag.Set(test.name, path)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				c.Assert(*v, qt.Equals, "default.txt")
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(*v, qt.Equals, filepath.Join(dir, test.expectedValue))
+		})
+	}
+}
+
+func TestFileVar(t *testing.T) {
+	for _, test := range fileTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			dir := makeFileTree(c)
+			var v string
+			flagutils.FileVar(&v, test.name, "", test.check, "file usage")
+			path := test.value
+			if path != "" {
+				path = filepath.Join(dir, path)
+			}
+			err := flag.Set(test.name, path)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				c.Assert(v, qt.Equals, "")
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v, qt.Equals, filepath.Join(dir, test.expectedValue))
+		})
+	}
+}
+
+func TestFileNotReadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("file permissions are not enforced for root")
+	}
+	c := qt.New(t)
+	dir := makeFileTree(c)
+	path := filepath.Join(dir, "input.txt")
+	c.Assert(os.Chmod(path, 0), qt.Equals, nil)
+	var v string
+	f := flagutils.NewFileValue(&v, flagutils.FileReadable)
+	err := f.Set(path)
+	c.Assert(err, qt.ErrorMatches, `invalid file ".*input.txt": open .*: permission denied`)
+	c.Assert(v, qt.Equals, "")
+}
+
+// makeFileTree creates a temporary directory including an "input.txt" file
+// and a "dir" directory, and returns its path.
+func makeFileTree(c *qt.C) string {
+	dir := c.TB.(*testing.T).TempDir()
+	err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte("bad wolf"), 0600)
+	c.Assert(err, qt.Equals, nil)
+	err = os.Mkdir(filepath.Join(dir, "dir"), 0700)
+	c.Assert(err, qt.Equals, nil)
+	return dir
+}
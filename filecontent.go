@@ -0,0 +1,88 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// FileContent defines a file content flag with specified name, default
+// value, maximum size and usage string. The flag is provided via the command
+// line as a file path, and the file contents are read when the flag is set.
+// If maxSize is positive, files bigger than maxSize bytes are rejected. The
+// return value is the address of a byte slice variable that stores the
+// contents of the file.
+func FileContent(name string, value []byte, maxSize int64, usage string, opts ...Option) *[]byte {
+	var b []byte
+	FileContentVar(&b, name, value, maxSize, usage, opts...)
+	return &b
+}
+
+// FileContentVar defines a file content flag with specified name, default
+// value, maximum size and usage string. See FileContent for details. The
+// argument p points to a byte slice variable in which to store the contents
+// of the file.
+func FileContentVar(p *[]byte, name string, value []byte, maxSize int64, usage string, opts ...Option) {
+	FileContentVarFS(flag.CommandLine, p, name, value, maxSize, usage, opts...)
+}
+
+// FileContentVarFS is like FileContentVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func FileContentVarFS(fs *flag.FlagSet, p *[]byte, name string, value []byte, maxSize int64, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewFileContentValue(p, maxSize), name, usage, opts)
+}
+
+// NewFileContentValue returns a FileContentValue storing the file contents
+// in p. If maxSize is positive, files bigger than maxSize bytes are rejected.
+func NewFileContentValue(p *[]byte, maxSize int64) *FileContentValue {
+	return &FileContentValue{
+		p:       p,
+		maxSize: maxSize,
+	}
+}
+
+// FileContentValue holds the contents of a file whose path is provided via
+// the command line.
+type FileContentValue struct {
+	p       *[]byte
+	maxSize int64
+	path    string
+}
+
+// String implements flag.Value by returning the path of the file, so that
+// its contents are not included in usage and log messages.
+func (f *FileContentValue) String() string {
+	return f.path
+}
+
+// Set implements flag.Value by reading the file at the given path.
+func (f *FileContentValue) Set(value string) error {
+	file, err := os.Open(value)
+	if err != nil {
+		return fmt.Errorf("cannot read file %q: %v", value, err)
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if f.maxSize > 0 {
+		r = io.LimitReader(file, f.maxSize+1)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("cannot read file %q: %v", value, err)
+	}
+	if f.maxSize > 0 && int64(len(b)) > f.maxSize {
+		return fmt.Errorf("cannot read file %q: file is bigger than %s", value, formatByteSize(f.maxSize))
+	}
+	*f.p = b
+	f.path = value
+	return nil
+}
+
+// Type implements pflag.Value by returning "fileContent".
+func (f *FileContentValue) Type() string {
+	return "fileContent"
+}
@@ -0,0 +1,129 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.FileContentValue)(nil)
+
+var fileContentTests = []struct {
+	about         string
+	name          string
+	value         string
+	maxSize       int64
+	defaultValue  []byte
+	expectedValue []byte
+	expectedError string
+}{{
+	about:         "file",
+	name:          "file",
+	value:         "input.txt",
+	expectedValue: []byte("bad wolf"),
+}, {
+	about:         "file within the size limit",
+	name:          "limit",
+	value:         "input.txt",
+	maxSize:       8,
+	expectedValue: []byte("bad wolf"),
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "input.txt",
+	defaultValue:  []byte("rose"),
+	expectedValue: []byte("bad wolf"),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []byte("rose"),
+	expectedValue: []byte("rose"),
+}, {
+	about:         "error: empty path",
+	name:          "err",
+	expectedError: `cannot read file "": open : no such file or directory`,
+}, {
+	about:         "error: no such file",
+	name:          "err",
+	value:         "no-such.txt",
+	expectedError: `cannot read file ".*no-such.txt": open .*: no such file or directory`,
+}, {
+	about:         "error: file too big",
+	name:          "err",
+	value:         "input.txt",
+	maxSize:       4,
+	defaultValue:  []byte("rose"),
+	expectedValue: []byte("rose"),
+	expectedError: `cannot read file ".*input.txt": file is bigger than 4B`,
+}, {
+	about:         "error: directory",
+	name:          "err",
+	value:         "dir",
+	expectedError: `cannot read file ".*dir": read .*: is a directory`,
+}}
+
+func TestFileContent(t *testing.T) {
+	for _, test := range fileContentTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			dir := makeFileTree(c)
+			v := flagutils.FileContent(test.name, test.defaultValue, test.maxSize, "file content usage")
+			if test.value != "" || test.defaultValue == nil {
+				path := test.value
+				if path != "" {
+					path = filepath.Join(dir, path)
+				}
+				err := flag.Set(test.name, path)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+					c.Assert(flag.Lookup(test.name).Value.String(), qt.Equals, path)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestFileContentVar(t *testing.T) {
+	for _, test := range fileContentTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			dir := makeFileTree(c)
+			var v []byte
+			flagutils.FileContentVar(&v, test.name, test.defaultValue, test.maxSize, "file content usage")
+			if test.value != "" || test.defaultValue == nil {
+				path := test.value
+				if path != "" {
+					path = filepath.Join(dir, path)
+				}
+				err := flag.Set(test.name, path)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestFileContentValueString(t *testing.T) {
+	c := qt.New(t)
+	var v []byte
+	f := flagutils.NewFileContentValue(&v, 0)
+	c.Assert(f.String(), qt.Equals, "")
+	path := filepath.Join(c.TB.(*testing.T).TempDir(), "secret.pem")
+	err := os.WriteFile(path, []byte("secret"), 0600)
+	c.Assert(err, qt.Equals, nil)
+	err = f.Set(path)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(f.String(), qt.Equals, path)
+}
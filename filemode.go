@@ -0,0 +1,87 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FileMode defines a file mode flag with specified name, default value, and
+// usage string. The value is provided via the command line as an octal
+// number, as in "0644", "0o755" or "600". Only permission bits and the
+// setuid, setgid and sticky bits are accepted. The return value is the
+// address of an os.FileMode variable that stores the value of the flag.
+func FileMode(name string, value os.FileMode, usage string, opts ...Option) *os.FileMode {
+	var m os.FileMode
+	FileModeVar(&m, name, value, usage, opts...)
+	return &m
+}
+
+// FileModeVar defines a file mode flag with specified name, default value,
+// and usage string. See FileMode for details on the accepted values. The
+// argument p points to an os.FileMode variable in which to store the value
+// of the flag.
+func FileModeVar(p *os.FileMode, name string, value os.FileMode, usage string, opts ...Option) {
+	FileModeVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// FileModeVarFS is like FileModeVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func FileModeVarFS(fs *flag.FlagSet, p *os.FileMode, name string, value os.FileMode, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*FileModeValue)(p), name, usage, opts)
+}
+
+// FileModeValue holds a file mode that can be provided via the command line
+// in octal notation.
+type FileModeValue os.FileMode
+
+// String implements flag.Value by returning the mode in octal notation, as
+// in "0644".
+func (m *FileModeValue) String() string {
+	mode := os.FileMode(*m)
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 0o1000
+	}
+	return fmt.Sprintf("%04o", bits)
+}
+
+// Set implements flag.Value by parsing the given octal file mode.
+func (m *FileModeValue) Set(value string) error {
+	digits := strings.TrimPrefix(strings.TrimPrefix(value, "0o"), "0O")
+	bits, err := strconv.ParseUint(digits, 8, 32)
+	if err != nil {
+		return fmt.Errorf("invalid file mode %q: must be an octal number", value)
+	}
+	if bits > 0o7777 {
+		return fmt.Errorf("invalid file mode %q: only permission bits are allowed", value)
+	}
+	mode := os.FileMode(bits).Perm()
+	if bits&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	*m = FileModeValue(mode)
+	return nil
+}
+
+// Type implements pflag.Value by returning "fileMode".
+func (m *FileModeValue) Type() string {
+	return "fileMode"
+}
@@ -0,0 +1,135 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.FileModeValue)(nil)
+
+var fileModeTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        os.FileMode
+	expectedValue       os.FileMode
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "leading zero",
+	name:                "zero",
+	value:               "0644",
+	expectedValue:       0o644,
+	expectedStringValue: "0644",
+}, {
+	about:               "0o prefix",
+	name:                "prefix",
+	value:               "0o755",
+	expectedValue:       0o755,
+	expectedStringValue: "0755",
+}, {
+	about:               "no prefix",
+	name:                "noprefix",
+	value:               "600",
+	expectedValue:       0o600,
+	expectedStringValue: "0600",
+}, {
+	about:               "special bits",
+	name:                "special",
+	value:               "7755",
+	expectedValue:       os.ModeSetuid | os.ModeSetgid | os.ModeSticky | 0o755,
+	expectedStringValue: "7755",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "0600",
+	defaultValue:  0o644,
+	expectedValue: 0o600,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  0o644,
+	expectedValue: 0o644,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid file mode "": must be an octal number`,
+}, {
+	about:         "error: not octal",
+	name:          "err",
+	value:         "0849",
+	expectedError: `invalid file mode "0849": must be an octal number`,
+}, {
+	about:         "error: symbolic mode",
+	name:          "err",
+	value:         "u+rwx",
+	expectedError: `invalid file mode "u\+rwx": must be an octal number`,
+}, {
+	about:         "error: too many bits",
+	name:          "err",
+	value:         "10644",
+	expectedError: `invalid file mode "10644": only permission bits are allowed`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "-644",
+	defaultValue:  0o644,
+	expectedValue: 0o644,
+	expectedError: `invalid file mode "-644": must be an octal number`,
+}}
+
+func TestFileMode(t *testing.T) {
+	for _, test := range fileModeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.FileMode(test.name, test.defaultValue, "file mode usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestFileModeVar(t *testing.T) {
+	for _, test := range fileModeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v os.FileMode
+			flagutils.FileModeVar(&v, test.name, test.defaultValue, "file mode usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestFileModeValueString(t *testing.T) {
+	for _, test := range fileModeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != 0 || test.expectedError != "" {
+				return
+			}
+			var v flagutils.FileModeValue
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
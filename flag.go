@@ -3,27 +3,41 @@
 package flagutils
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 )
 
-// Slice defines a string slice flag with specified name, default value, and
-// usage string. The return value is the address of a StringSlice variable that
-// stores the value of the flag.
-func Slice(name string, value []string, usage string) *StringSlice {
+// Slice defines a string slice flag with specified name, default value, usage
+// string and options. The return value is the address of a StringSlice
+// variable that stores the value of the flag.
+func Slice(name string, value []string, usage string, opts ...SliceOption) *StringSlice {
 	var s StringSlice
-	SliceVar(&s, name, value, usage)
+	SliceVar(&s, name, value, usage, opts...)
 	return &s
 }
 
-// SliceVar defines a string slice flag with specified name, default value, and
-// usage string. The argument p points to a StringSlice variable in which to
-// store the value of the flag.
-func SliceVar(p *StringSlice, name string, value []string, usage string) {
+// SliceVar defines a string slice flag with specified name, default value,
+// usage string and options. The argument p points to a StringSlice variable
+// in which to store the value of the flag.
+func SliceVar(p *StringSlice, name string, value []string, usage string, opts ...SliceOption) {
+	SliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// SliceVarFS is like SliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set. When no options are
+// provided, the flag value registered in the flag set is p itself.
+func SliceVarFS(fs *flag.FlagSet, p *StringSlice, name string, value []string, usage string, opts ...SliceOption) {
 	*p = value
-	flag.Var(p, name, usage)
+	if len(opts) == 0 {
+		fs.Var(p, name, usage)
+		return
+	}
+	v := newSliceValue(p, opts)
+	v.opts.flag.define(fs, v, name, usage)
 }
 
 // StringSlice holds a slice of strings that can be provided via the command
@@ -39,31 +53,119 @@ func (s *StringSlice) String() string {
 // separated value.
 func (s *StringSlice) Set(value string) error {
 	*s = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	*s = values
+	return nil
+}
+
+// Type implements pflag.Value by returning "stringSlice".
+func (s *StringSlice) Type() string {
+	return "stringSlice"
+}
+
+// MarshalText implements encoding.TextMarshaler by returning the slice as a
+// comma separated list of values.
+func (s StringSlice) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by populating the slice
+// from the given comma separated value. An empty value results in a nil
+// slice.
+func (s *StringSlice) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = nil
+		return nil
+	}
+	return s.Set(string(text))
+}
+
+// MarshalJSON implements json.Marshaler by encoding the slice as a JSON
+// array.
+func (s StringSlice) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler by decoding either a JSON array
+// of strings or a JSON string holding a comma separated list of values.
+func (s *StringSlice) UnmarshalJSON(data []byte) error {
+	values, err := unmarshalJSONList(data)
+	if err != nil {
+		return err
+	}
+	*s = values
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler by encoding the slice as a YAML
+// sequence.
+func (s StringSlice) MarshalYAML() (interface{}, error) {
+	return []string(s), nil
+}
+
+// unmarshalJSONList decodes the given JSON array of strings, or JSON string
+// holding a comma separated list of values. A JSON null results in a nil
+// slice.
+func unmarshalJSONList(data []byte) ([]string, error) {
+	var values []string
+	if err := json.Unmarshal(data, &values); err == nil {
+		return values, nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal JSON: expected array of strings or string, got %s", data)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	return splitList(value)
+}
+
+// splitList splits the given comma separated value into its elements,
+// trimming leading and trailing spaces. An error is returned if any of the
+// elements is empty.
+func splitList(value string) ([]string, error) {
+	var values []string
 	for _, v := range strings.Split(value, ",") {
 		v = strings.TrimSpace(v)
 		if v == "" {
-			return fmt.Errorf("cannot include empty strings in the list")
+			return nil, fmt.Errorf("cannot include empty strings in the list")
 		}
-		*s = append(*s, v)
+		values = append(values, v)
 	}
-	return nil
+	return values, nil
 }
 
 // Map defines a flag containing a map of strings with specified name, default
-// value, and usage string. The return value is the address of a StringMap
-// variable that stores the value of the flag.
-func Map(name string, value map[string]interface{}, usage string) *StringMap {
+// value, usage string and options. The return value is the address of a
+// StringMap variable that stores the value of the flag.
+func Map(name string, value map[string]interface{}, usage string, opts ...MapOption) *StringMap {
 	var s StringMap
-	MapVar(&s, name, value, usage)
+	MapVar(&s, name, value, usage, opts...)
 	return &s
 }
 
 // MapVar defines a flag containing a map of strings with specified name,
-// default value, and usage string. The argument p points to a StringMap
-// variable in which to store the value of the flag.
-func MapVar(p *StringMap, name string, value map[string]interface{}, usage string) {
+// default value, usage string and options. The argument p points to a
+// StringMap variable in which to store the value of the flag.
+func MapVar(p *StringMap, name string, value map[string]interface{}, usage string, opts ...MapOption) {
+	MapVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// MapVarFS is like MapVar, but defines the flag in the given flag set
+// rather than in the default command line flag set. When no options are
+// provided, the flag value registered in the flag set is p itself.
+func MapVarFS(fs *flag.FlagSet, p *StringMap, name string, value map[string]interface{}, usage string, opts ...MapOption) {
 	*p = value
-	flag.Var(p, name, usage)
+	if len(opts) == 0 {
+		fs.Var(p, name, usage)
+		return
+	}
+	v := newMapValue(p, opts)
+	v.opts.flag.define(fs, v, name, usage)
 }
 
 // StringMap holds a map strings to empty interfaces that can be provided via
@@ -72,7 +174,7 @@ type StringMap map[string]interface{}
 
 // String implements flag.Value by returning the map as a string.
 func (s *StringMap) String() string {
-	b, err := json.Marshal(*s)
+	b, err := json.Marshal(map[string]interface{}(*s))
 	if err != nil {
 		// This should never happen.
 		panic(err)
@@ -81,15 +183,196 @@ func (s *StringMap) String() string {
 }
 
 // Set implements flag.Value by unmarshaling the JSON encoded value into the
-// string map. The JSON enclosing braces can be omitted.
+// string map. The JSON enclosing braces can be omitted. Alternatively, the
+// value can be provided as a comma separated list of "key=value" pairs, in
+// which keys are dot separated paths into nested maps, and values are JSON
+// decoded if possible or used as strings otherwise. For instance,
+// "flags.profile=true,url=https://1.2.3.4" is equivalent to
+// `{"flags": {"profile": true}, "url": "https://1.2.3.4"}`.
+// If the value starts with "@", the rest of the value is used as the path of
+// a file whose contents are parsed as described above.
 func (s *StringMap) Set(value string) error {
 	*s = nil
+	m, err := parseMap(value, &mapOptions{})
+	if err != nil {
+		return err
+	}
+	*s = m
+	return nil
+}
+
+// Type implements pflag.Value by returning "stringMap".
+func (s *StringMap) Type() string {
+	return "stringMap"
+}
+
+// MarshalText implements encoding.TextMarshaler by returning the map as a
+// JSON encoded string. A nil map results in an empty value.
+func (s StringMap) MarshalText() ([]byte, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by populating the map
+// from the given value, in any of the formats accepted by StringMap.Set. An
+// empty value results in a nil map.
+func (s *StringMap) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*s = nil
+		return nil
+	}
+	return s.Set(string(text))
+}
+
+// MarshalJSON implements json.Marshaler by encoding the map as a JSON object.
+func (s StringMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler by decoding either a JSON object
+// or a JSON string holding a value in any of the formats accepted by
+// StringMap.Set.
+func (s *StringMap) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		return s.UnmarshalText([]byte(value))
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("cannot unmarshal JSON: expected object or string, got %s", data)
+	}
+	*s = m
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler by encoding the map as a YAML
+// mapping.
+func (s StringMap) MarshalYAML() (interface{}, error) {
+	return map[string]interface{}(s), nil
+}
+
+// parseMap parses the given string map value according to the given
+// options. See StringMap.Set for details on the accepted formats.
+func parseMap(value string, opts *mapOptions) (StringMap, error) {
 	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "@") {
+		b, err := os.ReadFile(value[1:])
+		if err != nil {
+			return nil, fmt.Errorf("cannot read file: %v", err)
+		}
+		value = strings.TrimSpace(string(b))
+	}
+	if isPairs(value) {
+		return parsePairs(value, opts)
+	}
 	if !strings.HasPrefix(value, "{") {
 		value = "{" + value + "}"
 	}
-	if err := json.Unmarshal([]byte(value), s); err != nil {
-		return fmt.Errorf("cannot unmarshal JSON: %v", err)
+	var m map[string]interface{}
+	if err := opts.unmarshal([]byte(value), &m); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal JSON: %v", err)
+	}
+	if opts.noDuplicates {
+		if err := checkDuplicateKeys([]byte(value)); err != nil {
+			return nil, err
+		}
+	}
+	return StringMap(m), nil
+}
+
+// isPairs reports whether the given string map value is provided as a list
+// of "key=value" pairs rather than as JSON.
+func isPairs(value string) bool {
+	return !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, `"`) && strings.Contains(value, "=")
+}
+
+// parsePairs returns a map built from the given comma separated list of
+// "key=value" pairs, in which keys are dot separated paths.
+func parsePairs(value string, opts *mapOptions) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	seen := make(map[string]bool)
+	for _, pair := range splitPairs(value) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			return nil, fmt.Errorf("cannot include empty pairs in the list")
+		}
+		kv, err := parseKeyValue(pair)
+		if err != nil {
+			return nil, err
+		}
+		if opts.noDuplicates && seen[kv.Key] {
+			return nil, fmt.Errorf("duplicate key %q", kv.Key)
+		}
+		seen[kv.Key] = true
+		if err := setPath(m, kv.Key, decodeJSONOrString(kv.Value, opts)); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// splitPairs splits the given value on commas which are not part of JSON
+// strings, arrays or objects.
+func splitPairs(value string) []string {
+	var parts []string
+	var depth, start int
+	var quoted, escaped bool
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// decodeJSONOrString returns the JSON decoded value, or the value itself if
+// it is not valid JSON.
+func decodeJSONOrString(value string, opts *mapOptions) interface{} {
+	var v interface{}
+	if err := opts.unmarshal([]byte(value), &v); err != nil {
+		return value
+	}
+	return v
+}
+
+// setPath stores v in m at the given dot separated path, creating nested
+// maps as required.
+func setPath(m map[string]interface{}, path string, v interface{}) error {
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("invalid path %q: empty key", path)
+		}
+	}
+	for i, key := range keys[:len(keys)-1] {
+		switch next := m[key].(type) {
+		case map[string]interface{}:
+			m = next
+		case StringMap:
+			m = next
+		case nil:
+			nested := make(map[string]interface{})
+			m[key] = nested
+			m = nested
+		default:
+			return fmt.Errorf("cannot set %q: %q is not a map", path, strings.Join(keys[:i+1], "."))
+		}
 	}
+	m[keys[len(keys)-1]] = v
 	return nil
 }
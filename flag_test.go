@@ -3,10 +3,16 @@
 package flagutils_test
 
 import (
+	"encoding"
+	"encoding/json"
 	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"gopkg.in/yaml.v3"
 
 	"github.com/frankban/flagutils"
 )
@@ -15,6 +21,13 @@ import (
 var _ flag.Value = (*flagutils.StringSlice)(nil)
 var _ flag.Value = (*flagutils.StringMap)(nil)
 
+// These assignments are used to ensure that encoding.TextMarshaler and
+// encoding.TextUnmarshaler are implemented.
+var _ encoding.TextMarshaler = flagutils.StringSlice(nil)
+var _ encoding.TextUnmarshaler = (*flagutils.StringSlice)(nil)
+var _ encoding.TextMarshaler = flagutils.StringMap(nil)
+var _ encoding.TextUnmarshaler = (*flagutils.StringMap)(nil)
+
 var sliceTests = []struct {
 	about               string
 	name                string
@@ -67,6 +80,7 @@ func TestSlice(t *testing.T) {
 	for _, test := range sliceTests {
 		runIsolated(t, test.about, func(c *qt.C) {
 			v := flagutils.Slice(test.name, test.defaultValue, "slice usage")
+			c.Assert(flag.Lookup(test.name).Value.(*flagutils.StringSlice), qt.Equals, v)
 			if test.value != "" || test.defaultValue == nil {
 				err := flag.Set(test.name, test.value)
 				if test.expectedError == "" {
@@ -85,6 +99,7 @@ func TestSliceVar(t *testing.T) {
 		runIsolated(t, test.about, func(c *qt.C) {
 			var v flagutils.StringSlice
 			flagutils.SliceVar(&v, test.name, test.defaultValue, "slice usage")
+			c.Assert(flag.Lookup(test.name).Value.(*flagutils.StringSlice), qt.Equals, &v)
 			if test.value != "" || test.defaultValue == nil {
 				err := flag.Set(test.name, test.value)
 				if test.expectedError == "" {
@@ -129,6 +144,103 @@ func TestStringSliceString(t *testing.T) {
 	}
 }
 
+func TestStringSliceTextVar(t *testing.T) {
+	c := qt.New(t)
+	fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+	var v flagutils.StringSlice
+	fs.TextVar(&v, "slice", flagutils.StringSlice{"a", "b"}, "slice usage")
+	c.Assert(v, qt.DeepEquals, flagutils.StringSlice{"a", "b"})
+	c.Assert(fs.Lookup("slice").DefValue, qt.Equals, "a,b")
+	err := fs.Parse([]string{"-slice", "c, d"})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v, qt.DeepEquals, flagutils.StringSlice{"c", "d"})
+}
+
+func TestStringSliceText(t *testing.T) {
+	c := qt.New(t)
+	var v flagutils.StringSlice
+	err := v.UnmarshalText([]byte("a,b"))
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v, qt.DeepEquals, flagutils.StringSlice{"a", "b"})
+	text, err := v.MarshalText()
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(text), qt.Equals, "a,b")
+
+	err = v.UnmarshalText(nil)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v, qt.IsNil)
+
+	err = v.UnmarshalText([]byte("a,,b"))
+	c.Assert(err, qt.ErrorMatches, "cannot include empty strings in the list")
+}
+
+var stringSliceJSONTests = []struct {
+	about         string
+	data          string
+	expectedValue flagutils.StringSlice
+	expectedError string
+}{{
+	about:         "array",
+	data:          `{"tags": ["a", "b,c"]}`,
+	expectedValue: flagutils.StringSlice{"a", "b,c"},
+}, {
+	about:         "comma separated string",
+	data:          `{"tags": "a, b"}`,
+	expectedValue: flagutils.StringSlice{"a", "b"},
+}, {
+	about: "empty string",
+	data:  `{"tags": ""}`,
+}, {
+	about: "null",
+	data:  `{"tags": null}`,
+}, {
+	about:         "error: invalid string",
+	data:          `{"tags": "a,,b"}`,
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: invalid type",
+	data:          `{"tags": 42}`,
+	expectedError: "cannot unmarshal JSON: expected array of strings or string, got 42",
+}}
+
+func TestStringSliceJSON(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range stringSliceJSONTests {
+		c.Run(test.about, func(c *qt.C) {
+			var conf struct {
+				Tags flagutils.StringSlice `json:"tags"`
+			}
+			err := json.Unmarshal([]byte(test.data), &conf)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(conf.Tags, qt.DeepEquals, test.expectedValue)
+		})
+	}
+
+	b, err := json.Marshal(map[string]flagutils.StringSlice{"tags": {"a", "b"}})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(b), qt.Equals, `{"tags":["a","b"]}`)
+}
+
+func TestStringSliceYAML(t *testing.T) {
+	c := qt.New(t)
+	var conf struct {
+		Tags  flagutils.StringSlice `yaml:"tags"`
+		Names flagutils.StringSlice `yaml:"names"`
+	}
+	err := yaml.Unmarshal([]byte("tags: [a, b]\nnames: c,d\n"), &conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf.Tags, qt.DeepEquals, flagutils.StringSlice{"a", "b"})
+	c.Assert(conf.Names, qt.DeepEquals, flagutils.StringSlice{"c", "d"})
+
+	b, err := yaml.Marshal(conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(b), qt.Equals, "tags:\n    - a\n    - b\nnames:\n    - c\n    - d\n")
+}
+
 var mapTests = []struct {
 	about               string
 	name                string
@@ -241,6 +353,72 @@ var mapTests = []struct {
 	name:                "empty",
 	expectedValue:       flagutils.StringMap{},
 	expectedStringValue: "{}",
+}, {
+	about: "pairs: single pair",
+	name:  "single",
+	value: "gisf=true",
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+	},
+	expectedStringValue: `{"gisf":true}`,
+}, {
+	about: "pairs: dotted paths",
+	name:  "paths",
+	value: "flags.profile=true, flags.status=true ,url=https://1.2.3.4,answer=42",
+	expectedValue: flagutils.StringMap{
+		"answer": float64(42),
+		"flags": map[string]interface{}{
+			"profile": true,
+			"status":  true,
+		},
+		"url": "https://1.2.3.4",
+	},
+	expectedStringValue: `{"answer":42,"flags":{"profile":true,"status":true},"url":"https://1.2.3.4"}`,
+}, {
+	about: "pairs: JSON values",
+	name:  "json",
+	value: `list=[1, 2],obj={"a": "b,c"},str="x,y",empty=`,
+	expectedValue: flagutils.StringMap{
+		"list":  []interface{}{float64(1), float64(2)},
+		"obj":   map[string]interface{}{"a": "b,c"},
+		"str":   "x,y",
+		"empty": "",
+	},
+	expectedStringValue: `{"empty":"","list":[1,2],"obj":{"a":"b,c"},"str":"x,y"}`,
+}, {
+	about: "pairs: override",
+	name:  "override",
+	value: "flags.profile=true,flags.profile=false",
+	expectedValue: flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"profile": false,
+		},
+	},
+	expectedStringValue: `{"flags":{"profile":false}}`,
+}, {
+	about:               "error: pairs: empty pair",
+	name:                "err",
+	value:               "gisf=true,,url=https://1.2.3.4",
+	expectedStringValue: "null",
+	expectedError:       "cannot include empty pairs in the list",
+}, {
+	about:               "error: pairs: missing value",
+	name:                "err",
+	value:               "gisf=true,profile",
+	expectedStringValue: "null",
+	expectedError:       `invalid key=value pair "profile": missing "="`,
+}, {
+	about:               "error: pairs: empty path key",
+	name:                "err",
+	value:               "flags..profile=true",
+	expectedStringValue: "null",
+	expectedError:       `invalid path "flags..profile": empty key`,
+}, {
+	about:               "error: pairs: not a map",
+	name:                "err",
+	value:               "flags=true,flags.profile=true",
+	expectedStringValue: "null",
+	expectedError:       `cannot set "flags.profile": "flags" is not a map`,
 }, {
 	about:               "error: not a map",
 	name:                "err",
@@ -259,6 +437,7 @@ func TestMap(t *testing.T) {
 	for _, test := range mapTests {
 		runIsolated(t, test.about, func(c *qt.C) {
 			v := flagutils.Map(test.name, test.defaultValue, "map usage")
+			c.Assert(flag.Lookup(test.name).Value.(*flagutils.StringMap), qt.Equals, v)
 			if test.value != "" || test.defaultValue == nil {
 				err := flag.Set(test.name, test.value)
 				if test.expectedError == "" {
@@ -277,6 +456,7 @@ func TestMapVar(t *testing.T) {
 		runIsolated(t, test.about, func(c *qt.C) {
 			var v flagutils.StringMap
 			flagutils.MapVar(&v, test.name, test.defaultValue, "map usage")
+			c.Assert(flag.Lookup(test.name).Value.(*flagutils.StringMap), qt.Equals, &v)
 			if test.value != "" || test.defaultValue == nil {
 				err := flag.Set(test.name, test.value)
 				if test.expectedError == "" {
@@ -321,6 +501,222 @@ func TestStringMapString(t *testing.T) {
 	}
 }
 
+var mapFileTests = []struct {
+	about         string
+	contents      string
+	expectedValue flagutils.StringMap
+	expectedError string
+}{{
+	about: "JSON object",
+	contents: `{
+	"gisf": true,
+	"flags": {"profile": true}
+}
+`,
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+	},
+}, {
+	about:    "no braces",
+	contents: `"gisf": true`,
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+	},
+}, {
+	about:    "pairs",
+	contents: "flags.profile=true,url=https://1.2.3.4\n",
+	expectedValue: flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+		"url": "https://1.2.3.4",
+	},
+}, {
+	about:         "error: invalid JSON",
+	contents:      "{!}",
+	expectedError: "cannot unmarshal JSON: invalid character .*",
+}}
+
+func TestStringMapTextVar(t *testing.T) {
+	c := qt.New(t)
+	fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+	var v flagutils.StringMap
+	fs.TextVar(&v, "map", flagutils.StringMap{"answer": 42.0}, "map usage")
+	c.Assert(v, qt.DeepEquals, flagutils.StringMap{"answer": 42.0})
+	c.Assert(fs.Lookup("map").DefValue, qt.Equals, `{"answer":42}`)
+	err := fs.Parse([]string{"-map", "flags.profile=true"})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v, qt.DeepEquals, flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+	})
+}
+
+func TestStringMapText(t *testing.T) {
+	c := qt.New(t)
+	var v flagutils.StringMap
+	err := v.UnmarshalText([]byte(`"gisf": true`))
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v, qt.DeepEquals, flagutils.StringMap{"gisf": true})
+	text, err := v.MarshalText()
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(text), qt.Equals, `{"gisf":true}`)
+
+	err = v.UnmarshalText(nil)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v, qt.IsNil)
+	text, err = v.MarshalText()
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(text, qt.HasLen, 0)
+}
+
+var stringMapJSONTests = []struct {
+	about         string
+	data          string
+	expectedValue flagutils.StringMap
+	expectedError string
+}{{
+	about: "object",
+	data:  `{"conf": {"answer": 42, "flags": {"profile": true}}}`,
+	expectedValue: flagutils.StringMap{
+		"answer": 42.0,
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+	},
+}, {
+	about:         "pairs",
+	data:          `{"conf": "answer=42,url=https://1.2.3.4"}`,
+	expectedValue: flagutils.StringMap{"answer": 42.0, "url": "https://1.2.3.4"},
+}, {
+	about: "null",
+	data:  `{"conf": null}`,
+}, {
+	about:         "error: invalid type",
+	data:          `{"conf": [1, 2]}`,
+	expectedError: `cannot unmarshal JSON: expected object or string, got \[1, 2\]`,
+}}
+
+func TestStringMapJSON(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range stringMapJSONTests {
+		c.Run(test.about, func(c *qt.C) {
+			var conf struct {
+				Conf flagutils.StringMap `json:"conf"`
+			}
+			err := json.Unmarshal([]byte(test.data), &conf)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(conf.Conf, qt.DeepEquals, test.expectedValue)
+		})
+	}
+
+	b, err := json.Marshal(map[string]flagutils.StringMap{"conf": {"answer": 42}})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(b), qt.Equals, `{"conf":{"answer":42}}`)
+}
+
+func TestStringMapYAML(t *testing.T) {
+	c := qt.New(t)
+	var conf struct {
+		Conf  flagutils.StringMap `yaml:"conf"`
+		Flags flagutils.StringMap `yaml:"flags"`
+	}
+	err := yaml.Unmarshal([]byte("conf:\n  answer: 42\nflags: profile=true\n"), &conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf.Conf, qt.DeepEquals, flagutils.StringMap{"answer": 42})
+	c.Assert(conf.Flags, qt.DeepEquals, flagutils.StringMap{"profile": true})
+
+	b, err := yaml.Marshal(conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(b), qt.Equals, "conf:\n    answer: 42\nflags:\n    profile: true\n")
+}
+
+func TestStringMapSetFromFile(t *testing.T) {
+	for _, test := range mapFileTests {
+		qt.New(t).Run(test.about, func(c *qt.C) {
+			path := filepath.Join(t.TempDir(), "conf.json")
+			err := os.WriteFile(path, []byte(test.contents), 0600)
+			c.Assert(err, qt.Equals, nil)
+			var v flagutils.StringMap
+			err = v.Set(" @" + path)
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestStringMapSetFromFileNotFound(t *testing.T) {
+	c := qt.New(t)
+	var v flagutils.StringMap
+	err := v.Set("@/no/such/file.json")
+	c.Assert(err, qt.ErrorMatches, "cannot read file: open /no/such/file.json: no such file or directory")
+	c.Assert(v, qt.IsNil)
+}
+
+func TestVarFS(t *testing.T) {
+	runIsolated(t, "flag set", func(c *qt.C) {
+		fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+		var slice flagutils.StringSlice
+		flagutils.SliceVarFS(fs, &slice, "slice", []string{"default"}, "slice usage", flagutils.Accumulate())
+		var m flagutils.StringMap
+		flagutils.MapVarFS(fs, &m, "map", nil, "map usage")
+		var level string
+		flagutils.EnumVarFS(fs, &level, "level", "info", []string{"debug", "info"}, "enum usage")
+		var conf struct {
+			Debug bool
+		}
+		flagutils.JSONVarFS(fs, &conf, "json", "json usage")
+		opt := flagutils.OptionalOfFS(fs, "opt", 0, strconv.Atoi, "optional usage")
+		var weights map[string]int
+		flagutils.MapOfVarFS(fs, &weights, "weights", func(s string) (string, error) { return s, nil }, strconv.Atoi, nil, "map of usage")
+
+		err := fs.Parse([]string{
+			"-slice", "a,b", "-slice", "c",
+			"-map", "flags.profile=true",
+			"-level", "debug",
+			"-json", `{"Debug": true}`,
+			"-opt", "0",
+			"-weights", "a=1,b=2",
+			"arg",
+		})
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(fs.Args(), qt.DeepEquals, []string{"arg"})
+		c.Assert(slice, qt.DeepEquals, flagutils.StringSlice{"a", "b", "c"})
+		c.Assert(m, qt.DeepEquals, flagutils.StringMap{
+			"flags": map[string]interface{}{
+				"profile": true,
+			},
+		})
+		c.Assert(level, qt.Equals, "debug")
+		c.Assert(conf.Debug, qt.Equals, true)
+		c.Assert(opt.IsSet(), qt.Equals, true)
+		c.Assert(weights, qt.DeepEquals, map[string]int{"a": 1, "b": 2})
+
+		// The global command line flag set is not affected.
+		c.Assert(flag.Lookup("slice"), qt.IsNil)
+		c.Assert(flag.Lookup("map"), qt.IsNil)
+		c.Assert(flag.Lookup("level"), qt.IsNil)
+		c.Assert(flag.Lookup("json"), qt.IsNil)
+		c.Assert(flag.Lookup("opt"), qt.IsNil)
+		c.Assert(flag.Lookup("weights"), qt.IsNil)
+
+		err = fs.Parse([]string{"-level", "bad"})
+		c.Assert(err, qt.ErrorMatches, `invalid value "bad" for flag -level: invalid value "bad": allowed values are "debug", "info"`)
+	})
+}
+
 // runIsolated runs the given test function without clobbering global flags.
 func runIsolated(t *testing.T, name string, f func(c *qt.C)) {
 	restore := resetForTesting()
@@ -0,0 +1,1024 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"crypto/tls"
+	"encoding"
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"image/color"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NewFlagSet returns a new, empty flag set with the specified name and error
+// handling property.
+func NewFlagSet(name string, errorHandling flag.ErrorHandling) *FlagSet {
+	fs := flag.NewFlagSet(name, errorHandling)
+	// Use the default usage message printed by FlagSet, which omits hidden
+	// flags.
+	fs.Usage = nil
+	return &FlagSet{
+		FlagSet: fs,
+	}
+}
+
+// FlagSet wraps a flag.FlagSet, exposing all the flag helpers defined in
+// this package as methods, so that larger programs and subcommands can
+// define their flags without touching the global command line flag set.
+// All the methods of the embedded flag.FlagSet are available, except that
+// Duration and DurationVar define flags accepting the extended syntax
+// described in the Duration function. Generic helpers, like SliceOf or MapOf,
+// cannot be exposed as methods: use their FS variants with the embedded flag
+// set instead, for instance SliceOfVarFS(fs.FlagSet, ...).
+type FlagSet struct {
+	*flag.FlagSet
+}
+
+// Parse parses the command line flags from os.Args[1:], as done by
+// flag.Parse, also applying the Env and Required options and extending the
+// error reporting as described in FlagSet.Parse. It must be called after
+// all flags are defined and before flags are accessed by the program. Unless
+// flag.Usage has been changed, it is replaced by a function printing the
+// default usage message without hidden flags.
+func Parse() {
+	if reflect.ValueOf(flag.Usage).Pointer() == defaultUsage {
+		flag.Usage = commandLineUsage
+	}
+	// Ignore errors: flag.CommandLine is set for ExitOnError.
+	(&FlagSet{FlagSet: flag.CommandLine}).Parse(os.Args[1:])
+}
+
+// defaultUsage holds the address of the default flag.Usage function.
+var defaultUsage = reflect.ValueOf(flag.Usage).Pointer()
+
+// commandLineUsage prints the default usage message of the command line flag
+// set, as done by the default flag.Usage function, omitting hidden flags.
+func commandLineUsage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+	PrintDefaults()
+}
+
+// PrintDefaults prints, to standard error unless configured otherwise, the
+// default values of all defined command line flags, except hidden ones, as
+// done by flag.PrintDefaults. Use it in flag.Usage to omit hidden flags from
+// the usage message.
+func PrintDefaults() {
+	printDefaults(flag.CommandLine)
+}
+
+// Parse parses flag definitions from the argument list, which should not
+// include the command name, as done by flag.FlagSet.Parse. Flags not
+// provided in the argument list are then set from the environment variables
+// specified with the Env option, and an error is returned if flags defined
+// with the Required option are still not set. When a flag is not defined,
+// the returned error suggests the closest defined flag, if any, so that
+// typos are easy to spot. Errors are reported, followed by the usage
+// message, and then handled according to the flag set error handling
+// property.
+func (fs *FlagSet) Parse(arguments []string) error {
+	name, errorHandling := fs.Name(), fs.FlagSet.ErrorHandling()
+	usage, output := fs.Usage, fs.Output()
+	// Prevent the embedded flag set from reporting and handling errors: they
+	// are reported below, after being extended.
+	fs.Init(name, flag.ContinueOnError)
+	fs.Usage = func() {}
+	fs.SetOutput(io.Discard)
+	err := fs.FlagSet.Parse(arguments)
+	fs.Init(name, errorHandling)
+	fs.Usage = usage
+	fs.SetOutput(output)
+	if err == nil {
+		err = applyOptions(fs.FlagSet)
+	}
+	if err == nil {
+		return nil
+	}
+	if err != flag.ErrHelp {
+		err = fs.extendError(err)
+		fmt.Fprintln(output, err)
+	}
+	fs.usage()
+	switch errorHandling {
+	case flag.ExitOnError:
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	case flag.PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// PrintDefaults prints the default values of all the flags defined in the
+// flag set, except hidden ones, as done by flag.FlagSet.PrintDefaults.
+func (fs *FlagSet) PrintDefaults() {
+	printDefaults(fs.FlagSet)
+}
+
+// undefinedFlagPrefix is the prefix of the errors returned by
+// flag.FlagSet.Parse when a flag is not defined.
+const undefinedFlagPrefix = "flag provided but not defined: -"
+
+// extendError returns the given parsing error extended with a suggestion
+// when it is about a flag which is not defined.
+func (fs *FlagSet) extendError(err error) error {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, undefinedFlagPrefix) {
+		return err
+	}
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		names = append(names, f.Name)
+	})
+	if suggestion := suggest(msg[len(undefinedFlagPrefix):], names); suggestion != "" {
+		return fmt.Errorf("%s (did you mean -%s?)", msg, suggestion)
+	}
+	return err
+}
+
+// usage calls the usage function of the flag set, or prints the default
+// usage message if none is defined.
+func (fs *FlagSet) usage() {
+	if fs.Usage != nil {
+		fs.Usage()
+		return
+	}
+	if fs.Name() == "" {
+		fmt.Fprintf(fs.Output(), "Usage:\n")
+	} else {
+		fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+	}
+	fs.PrintDefaults()
+}
+
+// BasicAuth is like the package level BasicAuth function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) BasicAuth(name string, value Credentials, usage string, opts ...Option) *Credentials {
+	var c Credentials
+	fs.BasicAuthVar(&c, name, value, usage, opts...)
+	return &c
+}
+
+// BasicAuthVar is like the package level BasicAuthVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) BasicAuthVar(p *Credentials, name string, value Credentials, usage string, opts ...Option) {
+	BasicAuthVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// BigInt is like the package level BigInt function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) BigInt(name string, value *big.Int, usage string, opts ...Option) *big.Int {
+	n := new(big.Int)
+	fs.BigIntVar(n, name, value, usage, opts...)
+	return n
+}
+
+// BigIntVar is like the package level BigIntVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) BigIntVar(p *big.Int, name string, value *big.Int, usage string, opts ...Option) {
+	BigIntVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// BoolSlice is like the package level BoolSlice function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) BoolSlice(name string, value []bool, usage string, opts ...Option) *[]bool {
+	var s []bool
+	fs.BoolSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// BoolSliceVar is like the package level BoolSliceVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) BoolSliceVar(p *[]bool, name string, value []bool, usage string, opts ...Option) {
+	BoolSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Bytes is like the package level Bytes function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Bytes(name string, value []byte, usage string, opts ...Option) *[]byte {
+	var b []byte
+	fs.BytesVar(&b, name, value, usage, opts...)
+	return &b
+}
+
+// BytesVar is like the package level BytesVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) BytesVar(p *[]byte, name string, value []byte, usage string, opts ...Option) {
+	BytesVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// ByteSize is like the package level ByteSize function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) ByteSize(name string, value int64, usage string, opts ...Option) *int64 {
+	var n int64
+	fs.ByteSizeVar(&n, name, value, usage, opts...)
+	return &n
+}
+
+// ByteSizeVar is like the package level ByteSizeVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) ByteSizeVar(p *int64, name string, value int64, usage string, opts ...Option) {
+	ByteSizeVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// ByteSizeSlice is like the package level ByteSizeSlice function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) ByteSizeSlice(name string, value []int64, usage string, opts ...Option) *[]int64 {
+	var s []int64
+	fs.ByteSizeSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// ByteSizeSliceVar is like the package level ByteSizeSliceVar function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) ByteSizeSliceVar(p *[]int64, name string, value []int64, usage string, opts ...Option) {
+	ByteSizeSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// CIDR is like the package level CIDR function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) CIDR(name string, value *net.IPNet, normalize bool, usage string, opts ...Option) **net.IPNet {
+	var n *net.IPNet
+	fs.CIDRVar(&n, name, value, normalize, usage, opts...)
+	return &n
+}
+
+// CIDRVar is like the package level CIDRVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) CIDRVar(p **net.IPNet, name string, value *net.IPNet, normalize bool, usage string, opts ...Option) {
+	CIDRVarFS(fs.FlagSet, p, name, value, normalize, usage, opts...)
+}
+
+// CIDRSlice is like the package level CIDRSlice function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) CIDRSlice(name string, value []*net.IPNet, usage string, opts ...Option) *[]*net.IPNet {
+	var s []*net.IPNet
+	fs.CIDRSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// CIDRSliceVar is like the package level CIDRSliceVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) CIDRSliceVar(p *[]*net.IPNet, name string, value []*net.IPNet, usage string, opts ...Option) {
+	CIDRSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Color is like the package level Color function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Color(name string, value color.NRGBA, usage string, opts ...Option) *color.NRGBA {
+	var c color.NRGBA
+	fs.ColorVar(&c, name, value, usage, opts...)
+	return &c
+}
+
+// ColorVar is like the package level ColorVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) ColorVar(p *color.NRGBA, name string, value color.NRGBA, usage string, opts ...Option) {
+	ColorVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Coordinate is like the package level Coordinate function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) Coordinate(name string, value LatLng, usage string, opts ...Option) *LatLng {
+	var p LatLng
+	fs.CoordinateVar(&p, name, value, usage, opts...)
+	return &p
+}
+
+// CoordinateVar is like the package level CoordinateVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) CoordinateVar(p *LatLng, name string, value LatLng, usage string, opts ...Option) {
+	CoordinateVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Count is like the package level Count function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Count(name string, value int, usage string, opts ...Option) *int {
+	var n int
+	fs.CountVar(&n, name, value, usage, opts...)
+	return &n
+}
+
+// CountVar is like the package level CountVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) CountVar(p *int, name string, value int, usage string, opts ...Option) {
+	CountVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Cron is like the package level Cron function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Cron(name string, value string, validate func(string) error, usage string, opts ...Option) *string {
+	var s string
+	fs.CronVar(&s, name, value, validate, usage, opts...)
+	return &s
+}
+
+// CronVar is like the package level CronVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) CronVar(p *string, name string, value string, validate func(string) error, usage string, opts ...Option) {
+	CronVarFS(fs.FlagSet, p, name, value, validate, usage, opts...)
+}
+
+// Date is like the package level Date function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Date(name string, value time.Time, usage string, opts ...Option) *time.Time {
+	var t time.Time
+	fs.DateVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// DateVar is like the package level DateVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) DateVar(p *time.Time, name string, value time.Time, usage string, opts ...Option) {
+	DateVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Decimal is like the package level Decimal function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) Decimal(name string, value *big.Rat, scale int, usage string, opts ...Option) *big.Rat {
+	r := new(big.Rat)
+	fs.DecimalVar(r, name, value, scale, usage, opts...)
+	return r
+}
+
+// DecimalVar is like the package level DecimalVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) DecimalVar(p *big.Rat, name string, value *big.Rat, scale int, usage string, opts ...Option) {
+	DecimalVarFS(fs.FlagSet, p, name, value, scale, usage, opts...)
+}
+
+// DSN is like the package level DSN function, but defines the flag in the flag
+// set.
+func (fs *FlagSet) DSN(name string, value string, usage string, opts ...Option) *string {
+	var s string
+	fs.DSNVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// DSNVar is like the package level DSNVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) DSNVar(p *string, name string, value string, usage string, opts ...Option) {
+	DSNVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Duration is like the package level Duration function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) Duration(name string, value time.Duration, usage string, opts ...Option) *time.Duration {
+	var d time.Duration
+	fs.DurationVar(&d, name, value, usage, opts...)
+	return &d
+}
+
+// DurationVar is like the package level DurationVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) DurationVar(p *time.Duration, name string, value time.Duration, usage string, opts ...Option) {
+	DurationVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Email is like the package level Email function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Email(name string, value string, usage string, opts ...Option) *string {
+	var s string
+	fs.EmailVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// EmailVar is like the package level EmailVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) EmailVar(p *string, name string, value string, usage string, opts ...Option) {
+	EmailVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Enum is like the package level Enum function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Enum(name string, value string, allowed []string, usage string, opts ...Option) *string {
+	var s string
+	fs.EnumVar(&s, name, value, allowed, usage, opts...)
+	return &s
+}
+
+// EnumVar is like the package level EnumVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) EnumVar(p *string, name string, value string, allowed []string, usage string, opts ...Option) {
+	EnumVarFS(fs.FlagSet, p, name, value, allowed, usage, opts...)
+}
+
+// EnumSlice is like the package level EnumSlice function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) EnumSlice(name string, allowed []string, value []string, usage string, opts ...Option) *[]string {
+	var s []string
+	fs.EnumSliceVar(&s, name, allowed, value, usage, opts...)
+	return &s
+}
+
+// EnumSliceVar is like the package level EnumSliceVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) EnumSliceVar(p *[]string, name string, allowed []string, value []string, usage string, opts ...Option) {
+	EnumSliceVarFS(fs.FlagSet, p, name, allowed, value, usage, opts...)
+}
+
+// File is like the package level File function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) File(name string, value string, check FileCheck, usage string, opts ...Option) *string {
+	var s string
+	fs.FileVar(&s, name, value, check, usage, opts...)
+	return &s
+}
+
+// FileVar is like the package level FileVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) FileVar(p *string, name string, value string, check FileCheck, usage string, opts ...Option) {
+	FileVarFS(fs.FlagSet, p, name, value, check, usage, opts...)
+}
+
+// FileContent is like the package level FileContent function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) FileContent(name string, value []byte, maxSize int64, usage string, opts ...Option) *[]byte {
+	var b []byte
+	fs.FileContentVar(&b, name, value, maxSize, usage, opts...)
+	return &b
+}
+
+// FileContentVar is like the package level FileContentVar function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) FileContentVar(p *[]byte, name string, value []byte, maxSize int64, usage string, opts ...Option) {
+	FileContentVarFS(fs.FlagSet, p, name, value, maxSize, usage, opts...)
+}
+
+// FileMode is like the package level FileMode function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) FileMode(name string, value os.FileMode, usage string, opts ...Option) *os.FileMode {
+	var m os.FileMode
+	fs.FileModeVar(&m, name, value, usage, opts...)
+	return &m
+}
+
+// FileModeVar is like the package level FileModeVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) FileModeVar(p *os.FileMode, name string, value os.FileMode, usage string, opts ...Option) {
+	FileModeVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Slice is like the package level Slice function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Slice(name string, value []string, usage string, opts ...SliceOption) *StringSlice {
+	var s StringSlice
+	fs.SliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// SliceVar is like the package level SliceVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) SliceVar(p *StringSlice, name string, value []string, usage string, opts ...SliceOption) {
+	SliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Map is like the package level Map function, but defines the flag in the flag
+// set.
+func (fs *FlagSet) Map(name string, value map[string]interface{}, usage string, opts ...MapOption) *StringMap {
+	var s StringMap
+	fs.MapVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// MapVar is like the package level MapVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) MapVar(p *StringMap, name string, value map[string]interface{}, usage string, opts ...MapOption) {
+	MapVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// FloatRange is like the package level FloatRange function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) FloatRange(name string, value Interval[float64], usage string, opts ...Option) *Interval[float64] {
+	var r Interval[float64]
+	fs.FloatRangeVar(&r, name, value, usage, opts...)
+	return &r
+}
+
+// FloatRangeVar is like the package level FloatRangeVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) FloatRangeVar(p *Interval[float64], name string, value Interval[float64], usage string, opts ...Option) {
+	FloatRangeVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Glob is like the package level Glob function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Glob(name string, value string, usage string, opts ...GlobOption) *GlobPattern {
+	var g GlobPattern
+	fs.GlobVar(&g, name, value, usage, opts...)
+	return &g
+}
+
+// GlobVar is like the package level GlobVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) GlobVar(p *GlobPattern, name string, value string, usage string, opts ...GlobOption) {
+	GlobVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// GlobSlice is like the package level GlobSlice function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) GlobSlice(name string, value []string, usage string, opts ...Option) *[]string {
+	var s []string
+	fs.GlobSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// GlobSliceVar is like the package level GlobSliceVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) GlobSliceVar(p *[]string, name string, value []string, usage string, opts ...Option) {
+	GlobSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Header is like the package level Header function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) Header(name string, value http.Header, usage string, opts ...Option) *http.Header {
+	var h http.Header
+	fs.HeaderVar(&h, name, value, usage, opts...)
+	return &h
+}
+
+// HeaderVar is like the package level HeaderVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) HeaderVar(p *http.Header, name string, value http.Header, usage string, opts ...Option) {
+	HeaderVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// HexBytes is like the package level HexBytes function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) HexBytes(name string, value []byte, length int, usage string, opts ...Option) *[]byte {
+	var b []byte
+	fs.HexBytesVar(&b, name, value, length, usage, opts...)
+	return &b
+}
+
+// HexBytesVar is like the package level HexBytesVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) HexBytesVar(p *[]byte, name string, value []byte, length int, usage string, opts ...Option) {
+	HexBytesVarFS(fs.FlagSet, p, name, value, length, usage, opts...)
+}
+
+// HostPortSlice is like the package level HostPortSlice function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) HostPortSlice(name string, value []HostPort, usage string, opts ...Option) *[]HostPort {
+	var s []HostPort
+	fs.HostPortSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// HostPortSliceVar is like the package level HostPortSliceVar function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) HostPortSliceVar(p *[]HostPort, name string, value []HostPort, usage string, opts ...Option) {
+	HostPortSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// IntRange is like the package level IntRange function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) IntRange(name string, value Interval[int], usage string, opts ...Option) *Interval[int] {
+	var r Interval[int]
+	fs.IntRangeVar(&r, name, value, usage, opts...)
+	return &r
+}
+
+// IntRangeVar is like the package level IntRangeVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) IntRangeVar(p *Interval[int], name string, value Interval[int], usage string, opts ...Option) {
+	IntRangeVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// IP is like the package level IP function, but defines the flag in the flag
+// set.
+func (fs *FlagSet) IP(name string, value net.IP, version IPVersion, usage string, opts ...Option) *net.IP {
+	var ip net.IP
+	fs.IPVar(&ip, name, value, version, usage, opts...)
+	return &ip
+}
+
+// IPVar is like the package level IPVar function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) IPVar(p *net.IP, name string, value net.IP, version IPVersion, usage string, opts ...Option) {
+	IPVarFS(fs.FlagSet, p, name, value, version, usage, opts...)
+}
+
+// JSONVar is like the package level JSONVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) JSONVar(p interface{}, name string, usage string, opts ...JSONOption) {
+	JSONVarFS(fs.FlagSet, p, name, usage, opts...)
+}
+
+// KeyPair is like the package level KeyPair function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) KeyPair(name string, usage string, opts ...Option) *tls.Certificate {
+	var cert tls.Certificate
+	fs.KeyPairVar(&cert, name, usage, opts...)
+	return &cert
+}
+
+// KeyPairVar is like the package level KeyPairVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) KeyPairVar(p *tls.Certificate, name string, usage string, opts ...Option) {
+	KeyPairVarFS(fs.FlagSet, p, name, usage, opts...)
+}
+
+// KeyValueSlice is like the package level KeyValueSlice function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) KeyValueSlice(name string, value []KeyValue, usage string, opts ...Option) *[]KeyValue {
+	var s []KeyValue
+	fs.KeyValueSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// KeyValueSliceVar is like the package level KeyValueSliceVar function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) KeyValueSliceVar(p *[]KeyValue, name string, value []KeyValue, usage string, opts ...Option) {
+	KeyValueSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Location is like the package level Location function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) Location(name string, value *time.Location, usage string, opts ...Option) **time.Location {
+	var l *time.Location
+	fs.LocationVar(&l, name, value, usage, opts...)
+	return &l
+}
+
+// LocationVar is like the package level LocationVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) LocationVar(p **time.Location, name string, value *time.Location, usage string, opts ...Option) {
+	LocationVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// LogLevel is like the package level LogLevel function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) LogLevel(name string, value slog.Level, usage string, opts ...Option) *slog.Level {
+	var l slog.Level
+	fs.LogLevelVar(&l, name, value, usage, opts...)
+	return &l
+}
+
+// LogLevelVar is like the package level LogLevelVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) LogLevelVar(p *slog.Level, name string, value slog.Level, usage string, opts ...Option) {
+	LogLevelVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// MAC is like the package level MAC function, but defines the flag in the flag
+// set.
+func (fs *FlagSet) MAC(name string, value net.HardwareAddr, usage string, opts ...Option) *net.HardwareAddr {
+	var a net.HardwareAddr
+	fs.MACVar(&a, name, value, usage, opts...)
+	return &a
+}
+
+// MACVar is like the package level MACVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) MACVar(p *net.HardwareAddr, name string, value net.HardwareAddr, usage string, opts ...Option) {
+	MACVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// MIME is like the package level MIME function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) MIME(name string, value MediaType, usage string, opts ...Option) *MediaType {
+	var t MediaType
+	fs.MIMEVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// MIMEVar is like the package level MIMEVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) MIMEVar(p *MediaType, name string, value MediaType, usage string, opts ...Option) {
+	MIMEVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// OptionalBool is like the package level OptionalBool function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) OptionalBool(name string, usage string, opts ...Option) **bool {
+	var b *bool
+	fs.OptionalBoolVar(&b, name, usage, opts...)
+	return &b
+}
+
+// OptionalBoolVar is like the package level OptionalBoolVar function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) OptionalBoolVar(p **bool, name string, usage string, opts ...Option) {
+	OptionalBoolVarFS(fs.FlagSet, p, name, usage, opts...)
+}
+
+// OrderedMap is like the package level OrderedMap function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) OrderedMap(name string, value OrderedStringMap, usage string, opts ...Option) *OrderedStringMap {
+	var s OrderedStringMap
+	fs.OrderedMapVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// OrderedMapVar is like the package level OrderedMapVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) OrderedMapVar(p *OrderedStringMap, name string, value OrderedStringMap, usage string, opts ...Option) {
+	OrderedMapVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Percent is like the package level Percent function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) Percent(name string, value float64, bareIsPercent bool, usage string, opts ...Option) *float64 {
+	var f float64
+	fs.PercentVar(&f, name, value, bareIsPercent, usage, opts...)
+	return &f
+}
+
+// PercentVar is like the package level PercentVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) PercentVar(p *float64, name string, value float64, bareIsPercent bool, usage string, opts ...Option) {
+	PercentVarFS(fs.FlagSet, p, name, value, bareIsPercent, usage, opts...)
+}
+
+// Port is like the package level Port function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Port(name string, value int, allowZero bool, usage string, opts ...Option) *int {
+	var n int
+	fs.PortVar(&n, name, value, allowZero, usage, opts...)
+	return &n
+}
+
+// PortVar is like the package level PortVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) PortVar(p *int, name string, value int, allowZero bool, usage string, opts ...Option) {
+	PortVarFS(fs.FlagSet, p, name, value, allowZero, usage, opts...)
+}
+
+// PortRange is like the package level PortRange function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) PortRange(name string, value Interval[int], usage string, opts ...Option) *Interval[int] {
+	var r Interval[int]
+	fs.PortRangeVar(&r, name, value, usage, opts...)
+	return &r
+}
+
+// PortRangeVar is like the package level PortRangeVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) PortRangeVar(p *Interval[int], name string, value Interval[int], usage string, opts ...Option) {
+	PortRangeVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// PortSlice is like the package level PortSlice function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) PortSlice(name string, value []int, usage string, opts ...PortSliceOption) *[]int {
+	var s []int
+	fs.PortSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// PortSliceVar is like the package level PortSliceVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) PortSliceVar(p *[]int, name string, value []int, usage string, opts ...PortSliceOption) {
+	PortSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// ProxyURL is like the package level ProxyURL function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) ProxyURL(name string, value *url.URL, usage string, opts ...Option) **url.URL {
+	var u *url.URL
+	fs.ProxyURLVar(&u, name, value, usage, opts...)
+	return &u
+}
+
+// ProxyURLVar is like the package level ProxyURLVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) ProxyURLVar(p **url.URL, name string, value *url.URL, usage string, opts ...Option) {
+	ProxyURLVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Query is like the package level Query function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Query(name string, value url.Values, usage string, opts ...Option) *url.Values {
+	var v url.Values
+	fs.QueryVar(&v, name, value, usage, opts...)
+	return &v
+}
+
+// QueryVar is like the package level QueryVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) QueryVar(p *url.Values, name string, value url.Values, usage string, opts ...Option) {
+	QueryVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Rate is like the package level Rate function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Rate(name string, value Frequency, usage string, opts ...Option) *Frequency {
+	var f Frequency
+	fs.RateVar(&f, name, value, usage, opts...)
+	return &f
+}
+
+// RateVar is like the package level RateVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) RateVar(p *Frequency, name string, value Frequency, usage string, opts ...Option) {
+	RateVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Regexp is like the package level Regexp function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) Regexp(name string, value *regexp.Regexp, usage string, opts ...Option) **regexp.Regexp {
+	var r *regexp.Regexp
+	fs.RegexpVar(&r, name, value, usage, opts...)
+	return &r
+}
+
+// RegexpVar is like the package level RegexpVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) RegexpVar(p **regexp.Regexp, name string, value *regexp.Regexp, usage string, opts ...Option) {
+	RegexpVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Semver is like the package level Semver function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) Semver(name string, value Version, usage string, opts ...Option) *Version {
+	var v Version
+	fs.SemverVar(&v, name, value, usage, opts...)
+	return &v
+}
+
+// SemverVar is like the package level SemverVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) SemverVar(p *Version, name string, value Version, usage string, opts ...Option) {
+	SemverVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// StringSet is like the package level StringSet function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) StringSet(name string, value []string, usage string, opts ...Option) *StringSetValue {
+	var s StringSetValue
+	fs.StringSetVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// StringSetVar is like the package level StringSetVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) StringSetVar(p *StringSetValue, name string, value []string, usage string, opts ...Option) {
+	StringSetVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Signal is like the package level Signal function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) Signal(name string, value os.Signal, usage string, opts ...Option) *os.Signal {
+	var s os.Signal
+	fs.SignalVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// SignalVar is like the package level SignalVar function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) SignalVar(p *os.Signal, name string, value os.Signal, usage string, opts ...Option) {
+	SignalVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// SortedSlice is like the package level SortedSlice function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) SortedSlice(name string, value []string, usage string, opts ...Option) *SortedStringSlice {
+	var s SortedStringSlice
+	fs.SortedSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// SortedSliceVar is like the package level SortedSliceVar function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) SortedSliceVar(p *SortedStringSlice, name string, value []string, usage string, opts ...Option) {
+	SortedSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// StringToBool is like the package level StringToBool function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) StringToBool(name string, value map[string]bool, usage string, opts ...Option) *map[string]bool {
+	var m map[string]bool
+	fs.StringToBoolVar(&m, name, value, usage, opts...)
+	return &m
+}
+
+// StringToBoolVar is like the package level StringToBoolVar function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) StringToBoolVar(p *map[string]bool, name string, value map[string]bool, usage string, opts ...Option) {
+	StringToBoolVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// StringToFloat64 is like the package level StringToFloat64 function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) StringToFloat64(name string, value map[string]float64, usage string, opts ...Option) *map[string]float64 {
+	var m map[string]float64
+	fs.StringToFloat64Var(&m, name, value, usage, opts...)
+	return &m
+}
+
+// StringToFloat64Var is like the package level StringToFloat64Var function,
+// but defines the flag in the flag set.
+func (fs *FlagSet) StringToFloat64Var(p *map[string]float64, name string, value map[string]float64, usage string, opts ...Option) {
+	StringToFloat64VarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// StringToStringSlice is like the package level StringToStringSlice function,
+// but defines the flag in the flag set.
+func (fs *FlagSet) StringToStringSlice(name string, value map[string][]string, usage string, opts ...Option) *map[string][]string {
+	var m map[string][]string
+	fs.StringToStringSliceVar(&m, name, value, usage, opts...)
+	return &m
+}
+
+// StringToStringSliceVar is like the package level StringToStringSliceVar
+// function, but defines the flag in the flag set.
+func (fs *FlagSet) StringToStringSliceVar(p *map[string][]string, name string, value map[string][]string, usage string, opts ...Option) {
+	StringToStringSliceVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Template is like the package level Template function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) Template(name string, value *template.Template, usage string, opts ...Option) **template.Template {
+	var t *template.Template
+	fs.TemplateVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// TemplateVar is like the package level TemplateVar function, but defines the
+// flag in the flag set.
+func (fs *FlagSet) TemplateVar(p **template.Template, name string, value *template.Template, usage string, opts ...Option) {
+	TemplateVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// HTMLTemplate is like the package level HTMLTemplate function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) HTMLTemplate(name string, value *htmltemplate.Template, usage string, opts ...Option) **htmltemplate.Template {
+	var t *htmltemplate.Template
+	fs.HTMLTemplateVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// HTMLTemplateVar is like the package level HTMLTemplateVar function, but
+// defines the flag in the flag set.
+func (fs *FlagSet) HTMLTemplateVar(p **htmltemplate.Template, name string, value *htmltemplate.Template, usage string, opts ...Option) {
+	HTMLTemplateVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Text is like the package level Text function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Text(name string, v encoding.TextUnmarshaler, usage string, opts ...Option) {
+	TextFS(fs.FlagSet, name, v, usage, opts...)
+}
+
+// TextVar is like the package level TextVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) TextVar(p encoding.TextUnmarshaler, name string, value encoding.TextMarshaler, usage string, opts ...Option) {
+	TextVarFS(fs.FlagSet, p, name, value, usage, opts...)
+}
+
+// Time is like the package level Time function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) Time(name string, value time.Time, layouts []string, usage string, opts ...Option) *time.Time {
+	var t time.Time
+	fs.TimeVar(&t, name, value, layouts, usage, opts...)
+	return &t
+}
+
+// TimeVar is like the package level TimeVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) TimeVar(p *time.Time, name string, value time.Time, layouts []string, usage string, opts ...Option) {
+	TimeVarFS(fs.FlagSet, p, name, value, layouts, usage, opts...)
+}
+
+// TimeSlice is like the package level TimeSlice function, but defines the flag
+// in the flag set.
+func (fs *FlagSet) TimeSlice(name string, value []time.Time, layouts []string, usage string, opts ...Option) *[]time.Time {
+	var s []time.Time
+	fs.TimeSliceVar(&s, name, value, layouts, usage, opts...)
+	return &s
+}
+
+// TimeSliceVar is like the package level TimeSliceVar function, but defines
+// the flag in the flag set.
+func (fs *FlagSet) TimeSliceVar(p *[]time.Time, name string, value []time.Time, layouts []string, usage string, opts ...Option) {
+	TimeSliceVarFS(fs.FlagSet, p, name, value, layouts, usage, opts...)
+}
+
+// UUID is like the package level UUID function, but defines the flag in the
+// flag set.
+func (fs *FlagSet) UUID(name string, value string, lenient bool, usage string, opts ...Option) *string {
+	var s string
+	fs.UUIDVar(&s, name, value, lenient, usage, opts...)
+	return &s
+}
+
+// UUIDVar is like the package level UUIDVar function, but defines the flag in
+// the flag set.
+func (fs *FlagSet) UUIDVar(p *string, name string, value string, lenient bool, usage string, opts ...Option) {
+	UUIDVarFS(fs.FlagSet, p, name, value, lenient, usage, opts...)
+}
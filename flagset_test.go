@@ -0,0 +1,292 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"bytes"
+	"flag"
+	"image/color"
+	"strconv"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+func TestFlagSet(t *testing.T) {
+	runIsolated(t, "flag set", func(c *qt.C) {
+		fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+		c.Assert(fs.Name(), qt.Equals, "cmd")
+		c.Assert(fs.ErrorHandling(), qt.Equals, flag.ContinueOnError)
+
+		slice := fs.Slice("slice", nil, "slice usage")
+		m := fs.Map("map", nil, "map usage", flagutils.Merge())
+		level := fs.Enum("level", "info", []string{"debug", "info"}, "enum usage")
+		timeout := fs.Duration("timeout", time.Second, "duration usage")
+		set := fs.StringSet("set", nil, "set usage")
+		verbose := fs.Bool("verbose", false, "bool usage")
+		var ints []int
+		flagutils.SliceOfVarFS(fs.FlagSet, &ints, "ints", strconv.Atoi, nil, "slice of usage")
+
+		err := fs.Parse([]string{
+			"-slice", "a,b",
+			"-map", "flags.profile=true",
+			"-level", "debug",
+			"-timeout", "1d",
+			"-set", "a,b,a",
+			"-verbose",
+			"-ints", "1,2",
+			"arg",
+		})
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(fs.Args(), qt.DeepEquals, []string{"arg"})
+		c.Assert(*slice, qt.DeepEquals, flagutils.StringSlice{"a", "b"})
+		c.Assert(*m, qt.DeepEquals, flagutils.StringMap{
+			"flags": map[string]interface{}{
+				"profile": true,
+			},
+		})
+		c.Assert(*level, qt.Equals, "debug")
+		c.Assert(*timeout, qt.Equals, 24*time.Hour)
+		c.Assert(*set, qt.DeepEquals, flagutils.StringSetValue{"a", "b"})
+		c.Assert(*verbose, qt.Equals, true)
+		c.Assert(ints, qt.DeepEquals, []int{1, 2})
+
+		// The global command line flag set is not affected.
+		c.Assert(flag.Lookup("slice"), qt.IsNil)
+
+		// The Set method of the embedded flag set is still available.
+		err = fs.Set("level", "info")
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(*level, qt.Equals, "info")
+	})
+}
+
+var flagSetParseErrorTests = []struct {
+	about          string
+	args           []string
+	expectedError  string
+	expectedOutput string
+}{{
+	about:          "undefined flag with suggestion",
+	args:           []string{"-verbos"},
+	expectedError:  `flag provided but not defined: -verbos \(did you mean -verbose\?\)`,
+	expectedOutput: "flag provided but not defined: -verbos (did you mean -verbose?)\nusage\n",
+}, {
+	about:          "undefined flag with different case",
+	args:           []string{"--Level=debug"},
+	expectedError:  `flag provided but not defined: -Level \(did you mean -level\?\)`,
+	expectedOutput: "flag provided but not defined: -Level (did you mean -level?)\nusage\n",
+}, {
+	about:          "undefined flag without suggestion",
+	args:           []string{"-color"},
+	expectedError:  `flag provided but not defined: -color`,
+	expectedOutput: "flag provided but not defined: -color\nusage\n",
+}, {
+	about:          "invalid value",
+	args:           []string{"-level", "bad"},
+	expectedError:  `invalid value "bad" for flag -level: invalid value "bad": allowed values are "debug", "info"`,
+	expectedOutput: "invalid value \"bad\" for flag -level: invalid value \"bad\": allowed values are \"debug\", \"info\"\nusage\n",
+}, {
+	about:          "help",
+	args:           []string{"-h"},
+	expectedError:  `flag: help requested`,
+	expectedOutput: "usage\n",
+}}
+
+func TestFlagSetParseError(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range flagSetParseErrorTests {
+		c.Run(test.about, func(c *qt.C) {
+			fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+			var buf bytes.Buffer
+			fs.SetOutput(&buf)
+			fs.Usage = func() {
+				buf.WriteString("usage\n")
+			}
+			fs.Enum("level", "info", []string{"debug", "info"}, "enum usage")
+			fs.Bool("verbose", false, "bool usage")
+			err := fs.Parse(test.args)
+			c.Assert(err, qt.ErrorMatches, test.expectedError)
+			c.Assert(buf.String(), qt.Equals, test.expectedOutput)
+			c.Assert(fs.Output(), qt.Equals, &buf)
+		})
+	}
+}
+
+func TestFlagSetParseDefaultUsage(t *testing.T) {
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.Usage = nil
+	fs.Slice("things", nil, "a list of things")
+	err := fs.Parse([]string{"-thing", "a"})
+	c.Assert(err, qt.ErrorMatches, `flag provided but not defined: -thing \(did you mean -things\?\)`)
+	c.Assert(buf.String(), qt.Equals, `flag provided but not defined: -thing (did you mean -things?)
+Usage of cmd:
+  -things value
+    	a list of things
+`)
+}
+
+func TestFlagSetParsePanicOnError(t *testing.T) {
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.PanicOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	fs.Bool("verbose", false, "bool usage")
+	c.Assert(func() {
+		fs.Parse([]string{"-verbos"})
+	}, qt.PanicMatches, `flag provided but not defined: -verbos \(did you mean -verbose\?\)`)
+}
+
+func TestFlagSetTypes(t *testing.T) {
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.BasicAuth("basicauth", flagutils.Credentials{}, "")
+	fs.BigInt("bigint", nil, "")
+	fs.BoolSlice("boolslice", nil, "")
+	fs.Bytes("bytes", nil, "")
+	fs.ByteSize("bytesize", 0, "")
+	fs.ByteSizeSlice("bytesizeslice", nil, "")
+	fs.CIDR("cidr", nil, false, "")
+	fs.CIDRSlice("cidrslice", nil, "")
+	fs.Color("color", color.NRGBA{}, "")
+	fs.Coordinate("coordinate", flagutils.LatLng{}, "")
+	fs.Count("count", 0, "")
+	fs.Cron("cron", "", nil, "")
+	fs.Date("date", time.Time{}, "")
+	fs.Decimal("decimal", nil, 2, "")
+	fs.DSN("dsn", "", "")
+	fs.Duration("duration", 0, "")
+	fs.Email("email", "", "")
+	fs.Enum("enum", "a", []string{"a"}, "")
+	fs.EnumSlice("enumslice", []string{"a"}, nil, "")
+	fs.File("file", "", 0, "")
+	fs.FileContent("filecontent", nil, 0, "")
+	fs.FileMode("filemode", 0, "")
+	fs.Slice("slice", nil, "")
+	fs.Map("map", nil, "")
+	fs.FloatRange("floatrange", flagutils.Interval[float64]{}, "")
+	fs.Glob("glob", "", "")
+	fs.GlobSlice("globslice", nil, "")
+	fs.Header("header", nil, "")
+	fs.HexBytes("hexbytes", nil, 0, "")
+	fs.HostPortSlice("hostportslice", nil, "")
+	fs.IntRange("intrange", flagutils.Interval[int]{}, "")
+	fs.IP("ip", nil, 0, "")
+	var conf struct{}
+	fs.JSONVar(&conf, "json", "")
+	fs.KeyPair("keypair", "")
+	fs.KeyValueSlice("keyvalueslice", nil, "")
+	fs.Location("location", nil, "")
+	fs.LogLevel("loglevel", 0, "")
+	fs.MAC("mac", nil, "")
+	fs.MIME("mime", flagutils.MediaType{}, "")
+	fs.OptionalBool("optionalbool", "")
+	fs.OrderedMap("orderedmap", flagutils.OrderedStringMap{}, "")
+	fs.Percent("percent", 0, false, "")
+	fs.Port("port", 0, true, "")
+	fs.PortRange("portrange", flagutils.Interval[int]{}, "")
+	fs.PortSlice("portslice", nil, "")
+	fs.ProxyURL("proxyurl", nil, "")
+	fs.Query("query", nil, "")
+	fs.Rate("rate", flagutils.Frequency{}, "")
+	fs.Regexp("regexp", nil, "")
+	fs.Semver("semver", flagutils.Version{}, "")
+	fs.StringSet("set", nil, "")
+	fs.Signal("signal", nil, "")
+	fs.SortedSlice("sortedslice", nil, "")
+	fs.StringToBool("stringtobool", nil, "")
+	fs.StringToFloat64("stringtofloat64", nil, "")
+	fs.StringToStringSlice("stringtostringslice", nil, "")
+	fs.Template("template", nil, "")
+	fs.HTMLTemplate("htmltemplate", nil, "")
+	fs.Time("time", time.Time{}, nil, "")
+	fs.TimeSlice("timeslice", nil, nil, "")
+	fs.UUID("uuid", "", false, "")
+	flagutils.MapOfVarFS(fs.FlagSet, new(map[string]int), "mapof", func(s string) (string, error) { return s, nil }, strconv.Atoi, nil, "")
+	flagutils.OptionalOfFS(fs.FlagSet, "optionalof", 0, strconv.Atoi, "")
+	flagutils.SliceOfVarFS(fs.FlagSet, new([]float64), "sliceof", func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }, nil, "")
+	fs.Slice("required", nil, "", flagutils.Required())
+	fs.Var(new(flagutils.StringSlice), "std", "")
+	flagutils.SliceVarFS(fs.FlagSet, new(flagutils.StringSlice), "hidden", nil, "", flagutils.Hidden())
+
+	types := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		v, ok := f.Value.(interface{ Type() string })
+		c.Assert(ok, qt.Equals, true, qt.Commentf("flag -%s", f.Name))
+		types[f.Name] = v.Type()
+	})
+	c.Assert(types, qt.DeepEquals, map[string]string{
+		"basicauth":           "basicAuth",
+		"bigint":              "bigInt",
+		"boolslice":           "boolSlice",
+		"bytes":               "bytesBase64",
+		"bytesize":            "byteSize",
+		"bytesizeslice":       "byteSizeSlice",
+		"cidr":                "cidr",
+		"cidrslice":           "cidrSlice",
+		"color":               "color",
+		"coordinate":          "coordinate",
+		"count":               "count",
+		"cron":                "cron",
+		"date":                "date",
+		"decimal":             "decimal",
+		"dsn":                 "dsn",
+		"duration":            "duration",
+		"email":               "email",
+		"enum":                "enum",
+		"enumslice":           "enumSlice",
+		"file":                "file",
+		"filecontent":         "fileContent",
+		"filemode":            "fileMode",
+		"slice":               "stringSlice",
+		"map":                 "stringMap",
+		"floatrange":          "floatRange",
+		"glob":                "glob",
+		"globslice":           "globSlice",
+		"header":              "header",
+		"hexbytes":            "bytesHex",
+		"hostportslice":       "hostPortSlice",
+		"intrange":            "intRange",
+		"ip":                  "ip",
+		"json":                "json",
+		"keypair":             "keyPair",
+		"keyvalueslice":       "keyValueSlice",
+		"location":            "location",
+		"loglevel":            "logLevel",
+		"mac":                 "mac",
+		"mime":                "mediaType",
+		"optionalbool":        "bool",
+		"orderedmap":          "orderedStringMap",
+		"percent":             "percent",
+		"port":                "port",
+		"portrange":           "portRange",
+		"portslice":           "portSlice",
+		"proxyurl":            "proxyURL",
+		"query":               "query",
+		"rate":                "rate",
+		"regexp":              "regexp",
+		"semver":              "semver",
+		"set":                 "stringSet",
+		"signal":              "signal",
+		"sortedslice":         "sortedStringSlice",
+		"stringtobool":        "stringToBool",
+		"stringtofloat64":     "stringToFloat64",
+		"stringtostringslice": "stringToStringSlice",
+		"template":            "template",
+		"htmltemplate":        "htmlTemplate",
+		"time":                "time",
+		"timeslice":           "timeSlice",
+		"uuid":                "uuid",
+		"mapof":               "map[string]int",
+		"optionalof":          "int",
+		"sliceof":             "[]float64",
+		"required":            "stringSlice",
+		"std":                 "stringSlice",
+		"hidden":              "stringSlice",
+	})
+}
@@ -0,0 +1,69 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+)
+
+// FloatRange defines a float64 range flag with specified name, default value,
+// and usage string. The value is provided via the command line as "min-max",
+// for instance "0.2-0.8", or as a single number. The return value is the
+// address of an interval variable that stores the value of the flag.
+func FloatRange(name string, value Interval[float64], usage string, opts ...Option) *Interval[float64] {
+	var r Interval[float64]
+	FloatRangeVar(&r, name, value, usage, opts...)
+	return &r
+}
+
+// FloatRangeVar defines a float64 range flag with specified name, default
+// value, and usage string. The argument p points to an interval variable in
+// which to store the value of the flag.
+func FloatRangeVar(p *Interval[float64], name string, value Interval[float64], usage string, opts ...Option) {
+	FloatRangeVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// FloatRangeVarFS is like FloatRangeVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func FloatRangeVarFS(fs *flag.FlagSet, p *Interval[float64], name string, value Interval[float64], usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*FloatRangeValue)(p), name, usage, opts)
+}
+
+// FloatRangeValue holds a range of float64 numbers that can be provided via
+// the command line. Both bounds are included in the range, must be finite,
+// and can be negative, as in "-1.5--0.5". A single number is stored as a
+// range including only that value.
+type FloatRangeValue Interval[float64]
+
+// String implements flag.Value by returning the range as "min-max".
+func (r *FloatRangeValue) String() string {
+	return formatFloat(r.Min) + "-" + formatFloat(r.Max)
+}
+
+// Set implements flag.Value by parsing the given float64 range.
+func (r *FloatRangeValue) Set(value string) error {
+	lo, hi := splitRange(value)
+	loFloat, err := parseFloat(lo)
+	if err != nil {
+		return fmt.Errorf("invalid float range %q: invalid bound %q", value, lo)
+	}
+	hiFloat, err := parseFloat(hi)
+	if err != nil {
+		return fmt.Errorf("invalid float range %q: invalid bound %q", value, hi)
+	}
+	if loFloat > hiFloat {
+		return fmt.Errorf("invalid float range %q: %s is greater than %s", value, formatFloat(loFloat), formatFloat(hiFloat))
+	}
+	*r = FloatRangeValue{
+		Min: loFloat,
+		Max: hiFloat,
+	}
+	return nil
+}
+
+// Type implements pflag.Value by returning "floatRange".
+func (r *FloatRangeValue) Type() string {
+	return "floatRange"
+}
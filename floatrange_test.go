@@ -0,0 +1,146 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.FloatRangeValue)(nil)
+
+var floatRangeTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.Interval[float64]
+	expectedValue       flagutils.Interval[float64]
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "range",
+	name:                "range",
+	value:               "0.2-0.8",
+	expectedValue:       flagutils.Interval[float64]{Min: 0.2, Max: 0.8},
+	expectedStringValue: "0.2-0.8",
+}, {
+	about:               "spaces",
+	name:                "spaces",
+	value:               " 1 - 2.5 ",
+	expectedValue:       flagutils.Interval[float64]{Min: 1, Max: 2.5},
+	expectedStringValue: "1-2.5",
+}, {
+	about:               "single value",
+	name:                "single",
+	value:               "0.5",
+	expectedValue:       flagutils.Interval[float64]{Min: 0.5, Max: 0.5},
+	expectedStringValue: "0.5-0.5",
+}, {
+	about:               "negative bounds",
+	name:                "negative",
+	value:               "-1.5--.5",
+	expectedValue:       flagutils.Interval[float64]{Min: -1.5, Max: -0.5},
+	expectedStringValue: "-1.5--0.5",
+}, {
+	about:               "exponents",
+	name:                "exponents",
+	value:               "1e-3-2E+2",
+	expectedValue:       flagutils.Interval[float64]{Min: 0.001, Max: 200},
+	expectedStringValue: "0.001-200",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "0.1-0.9",
+	defaultValue:  flagutils.Interval[float64]{Min: 0, Max: 1},
+	expectedValue: flagutils.Interval[float64]{Min: 0.1, Max: 0.9},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  flagutils.Interval[float64]{Min: 0, Max: 1},
+	expectedValue: flagutils.Interval[float64]{Min: 0, Max: 1},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid float range "": invalid bound ""`,
+}, {
+	about:         "error: invalid bound",
+	name:          "err",
+	value:         "0.2-high",
+	expectedError: `invalid float range "0.2-high": invalid bound "high"`,
+}, {
+	about:         "error: not a number",
+	name:          "err",
+	value:         "NaN",
+	expectedError: `invalid float range "NaN": invalid bound "NaN"`,
+}, {
+	about:         "error: infinite bound",
+	name:          "err",
+	value:         "0-Inf",
+	expectedError: `invalid float range "0-Inf": invalid bound "Inf"`,
+}, {
+	about:         "error: wrong order",
+	name:          "err",
+	value:         "0.8-0.2",
+	expectedError: `invalid float range "0.8-0.2": 0.8 is greater than 0.2`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "1-0",
+	defaultValue:  flagutils.Interval[float64]{Min: 0, Max: 1},
+	expectedValue: flagutils.Interval[float64]{Min: 0, Max: 1},
+	expectedError: `invalid float range "1-0": 1 is greater than 0`,
+}}
+
+func TestFloatRange(t *testing.T) {
+	for _, test := range floatRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.FloatRange(test.name, test.defaultValue, "float range usage")
+			if test.value != "" || test.defaultValue == (flagutils.Interval[float64]{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestFloatRangeVar(t *testing.T) {
+	for _, test := range floatRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.Interval[float64]
+			flagutils.FloatRangeVar(&v, test.name, test.defaultValue, "float range usage")
+			if test.value != "" || test.defaultValue == (flagutils.Interval[float64]{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestFloatRangeValueString(t *testing.T) {
+	for _, test := range floatRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != (flagutils.Interval[float64]{}) || test.expectedError != "" {
+				return
+			}
+			var v flagutils.FloatRangeValue
+			c.Assert(v.String(), qt.Equals, "0-0")
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
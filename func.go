@@ -0,0 +1,78 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// Func defines a flag of an arbitrary type with specified name, default
+// value, parsing function and usage string. The value provided via the
+// command line is converted using the given parse function, so that custom
+// types can be used as flags without implementing flag.Value. The return
+// value is the address of a variable that stores the value of the flag. For
+// instance:
+//
+//	level := flagutils.Func("level", 1, parseLevel, "the compression level")
+func Func[T any](name string, value T, parse func(string) (T, error), usage string, opts ...Option) *T {
+	var v T
+	FuncVar(&v, name, value, parse, usage, opts...)
+	return &v
+}
+
+// FuncVar defines a flag of an arbitrary type with specified name, default
+// value, parsing function and usage string. The argument p points to a
+// variable in which to store the value of the flag.
+func FuncVar[T any](p *T, name string, value T, parse func(string) (T, error), usage string, opts ...Option) {
+	FuncVarFS(flag.CommandLine, p, name, value, parse, usage, opts...)
+}
+
+// FuncVarFS is like FuncVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func FuncVarFS[T any](fs *flag.FlagSet, p *T, name string, value T, parse func(string) (T, error), usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewFuncValue(p, parse), name, usage, opts)
+}
+
+// NewFuncValue returns a FuncValue storing its value in p and using the given
+// function to parse values.
+func NewFuncValue[T any](p *T, parse func(string) (T, error)) *FuncValue[T] {
+	return &FuncValue[T]{
+		p:     p,
+		parse: parse,
+	}
+}
+
+// FuncValue holds a value of an arbitrary type that can be provided via the
+// command line, and that is converted using a parse function.
+type FuncValue[T any] struct {
+	p     *T
+	parse func(string) (T, error)
+}
+
+// String implements flag.Value by returning the value in its default format.
+func (f *FuncValue[T]) String() string {
+	if f.p == nil {
+		return ""
+	}
+	return fmt.Sprint(*f.p)
+}
+
+// Set implements flag.Value by parsing the given value and storing it. The
+// value is left untouched if parsing fails.
+func (f *FuncValue[T]) Set(value string) error {
+	v, err := f.parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q: %v", value, err)
+	}
+	*f.p = v
+	return nil
+}
+
+// Type implements pflag.Value by returning the name of the value type,
+// for instance "int".
+func (f *FuncValue[T]) Type() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
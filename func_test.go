@@ -0,0 +1,148 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.FuncValue[int])(nil)
+
+type funcSize struct {
+	Width, Height int
+}
+
+func (s funcSize) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+func parseFuncSize(value string) (funcSize, error) {
+	w, h, ok := strings.Cut(value, "x")
+	if !ok {
+		return funcSize{}, fmt.Errorf("expected WIDTHxHEIGHT")
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil {
+		return funcSize{}, err
+	}
+	height, err := strconv.Atoi(h)
+	if err != nil {
+		return funcSize{}, err
+	}
+	return funcSize{Width: width, Height: height}, nil
+}
+
+var funcTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        funcSize
+	expectedValue       funcSize
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "value",
+	name:                "value",
+	value:               "640x480",
+	expectedValue:       funcSize{Width: 640, Height: 480},
+	expectedStringValue: "640x480",
+}, {
+	about:               "default value: with value",
+	name:                "def1",
+	value:               "1x2",
+	defaultValue:        funcSize{Width: 800, Height: 600},
+	expectedValue:       funcSize{Width: 1, Height: 2},
+	expectedStringValue: "1x2",
+}, {
+	about:               "default value: without value",
+	name:                "def2",
+	defaultValue:        funcSize{Width: 800, Height: 600},
+	expectedValue:       funcSize{Width: 800, Height: 600},
+	expectedStringValue: "800x600",
+}, {
+	about:               "error: parse failure",
+	name:                "err",
+	value:               "640",
+	defaultValue:        funcSize{Width: 800, Height: 600},
+	expectedValue:       funcSize{Width: 800, Height: 600},
+	expectedStringValue: "800x600",
+	expectedError:       `invalid value "640": expected WIDTHxHEIGHT`,
+}, {
+	about:               "error: invalid number",
+	name:                "err",
+	value:               "axb",
+	expectedStringValue: "0x0",
+	expectedError:       `invalid value "axb": strconv.Atoi: parsing "a": invalid syntax`,
+}}
+
+func TestFunc(t *testing.T) {
+	for _, test := range funcTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Func(test.name, test.defaultValue, parseFuncSize, "func usage")
+			c.Assert(flag.Lookup(test.name).DefValue, qt.Equals, test.defaultValue.String())
+			if test.value != "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestFuncVar(t *testing.T) {
+	for _, test := range funcTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v funcSize
+			flagutils.FuncVar(&v, test.name, test.defaultValue, parseFuncSize, "func usage")
+			if test.value != "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestFuncValueString(t *testing.T) {
+	for _, test := range funcTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := test.defaultValue
+			fv := flagutils.NewFuncValue(&v, parseFuncSize)
+			c.Assert(fv.Type(), qt.Equals, "flagutils_test.funcSize")
+			if test.value != "" {
+				fv.Set(test.value)
+			}
+			c.Assert(fv.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestFuncWithOptions(t *testing.T) {
+	t.Setenv("FLAGUTILS_RETRIES", "3")
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	var retries int
+	flagutils.FuncVarFS(fs.FlagSet, &retries, "retries", 1, strconv.Atoi, "the retries", flagutils.Env("FLAGUTILS_RETRIES"))
+	c.Assert(fs.Lookup("retries").Usage, qt.Equals, "the retries (env FLAGUTILS_RETRIES)")
+	err := fs.Parse(nil)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(retries, qt.Equals, 3)
+}
@@ -0,0 +1,156 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GlobPattern holds a glob pattern. If DoubleStar is true, a "**" path
+// segment in the pattern matches zero or more path segments, so that for
+// instance "src/**/*.go" matches both "src/main.go" and "src/a/b/main.go".
+// Otherwise the pattern follows filepath.Match semantics.
+type GlobPattern struct {
+	Pattern    string
+	DoubleStar bool
+}
+
+// String returns the pattern.
+func (g GlobPattern) String() string {
+	return g.Pattern
+}
+
+// Match reports whether the given path matches the pattern. When using
+// double star semantics, paths are matched segment by segment after being
+// converted to slash separated form.
+func (g GlobPattern) Match(name string) bool {
+	if !g.DoubleStar {
+		ok, _ := filepath.Match(g.Pattern, name)
+		return ok
+	}
+	return matchSegments(strings.Split(g.Pattern, "/"), strings.Split(filepath.ToSlash(name), "/"))
+}
+
+// Glob defines a glob pattern flag with specified name, default pattern, and
+// usage string. Patterns follow filepath.Match semantics unless the
+// DoubleStar option is provided. The return value is the address of a
+// GlobPattern variable that stores the value of the flag.
+func Glob(name string, value string, usage string, opts ...GlobOption) *GlobPattern {
+	var g GlobPattern
+	GlobVar(&g, name, value, usage, opts...)
+	return &g
+}
+
+// GlobVar defines a glob pattern flag with specified name, default pattern,
+// and usage string. Patterns follow filepath.Match semantics unless the
+// DoubleStar option is provided. The argument p points to a GlobPattern
+// variable in which to store the value of the flag.
+func GlobVar(p *GlobPattern, name string, value string, usage string, opts ...GlobOption) {
+	GlobVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// GlobVarFS is like GlobVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func GlobVarFS(fs *flag.FlagSet, p *GlobPattern, name string, value string, usage string, opts ...GlobOption) {
+	var o globOptions
+	for _, opt := range opts {
+		opt.applyGlobOption(&o)
+	}
+	*p = GlobPattern{Pattern: value, DoubleStar: o.doubleStar}
+	o.flag.define(fs, (*GlobValue)(p), name, usage)
+}
+
+// GlobOption configures the behavior of flags defined with Glob and GlobVar.
+// In addition to DoubleStar, the options returned by Required, Env and Hidden
+// can be used.
+type GlobOption interface {
+	applyGlobOption(*globOptions)
+}
+
+// globOption implements GlobOption with a function.
+type globOption func(*globOptions)
+
+// applyGlobOption implements GlobOption.
+func (f globOption) applyGlobOption(o *globOptions) {
+	f(o)
+}
+
+// globOptions holds the configuration of a glob pattern flag.
+type globOptions struct {
+	doubleStar bool
+	flag       flagOptions
+}
+
+// DoubleStar returns an option making glob pattern flags support "**" path
+// segments matching zero or more path segments.
+func DoubleStar() GlobOption {
+	return globOption(func(o *globOptions) {
+		o.doubleStar = true
+	})
+}
+
+// GlobValue holds a glob pattern that can be provided via the command line.
+// The pattern is validated when the flag is set, and keeps the double star
+// semantics of the current value.
+type GlobValue GlobPattern
+
+// String implements flag.Value by returning the pattern.
+func (g *GlobValue) String() string {
+	return g.Pattern
+}
+
+// Set implements flag.Value by validating and storing the given pattern.
+func (g *GlobValue) Set(value string) error {
+	if !g.DoubleStar {
+		if err := checkGlob(value); err != nil {
+			return err
+		}
+		g.Pattern = value
+		return nil
+	}
+	for _, segment := range strings.Split(value, "/") {
+		if segment == "**" {
+			continue
+		}
+		if strings.Contains(segment, "**") {
+			return fmt.Errorf("invalid glob pattern %q: ** must be a whole path segment", value)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %v", value, err)
+		}
+	}
+	g.Pattern = value
+	return nil
+}
+
+// Type implements pflag.Value by returning "glob".
+func (g *GlobValue) Type() string {
+	return "glob"
+}
+
+// matchSegments reports whether the given path segments match the pattern
+// segments, with "**" matching zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,220 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.GlobValue)(nil)
+
+var globTests = []struct {
+	about         string
+	name          string
+	value         string
+	defaultValue  string
+	doubleStar    bool
+	expectedValue flagutils.GlobPattern
+	expectedError string
+}{{
+	about:         "valid pattern",
+	name:          "valid",
+	value:         "*.go",
+	expectedValue: flagutils.GlobPattern{Pattern: "*.go"},
+}, {
+	about:         "double star pattern",
+	name:          "double",
+	value:         "src/**/*_test.go",
+	doubleStar:    true,
+	expectedValue: flagutils.GlobPattern{Pattern: "src/**/*_test.go", DoubleStar: true},
+}, {
+	about:         "double star in filepath mode",
+	name:          "no-double",
+	value:         "src/**/*.go",
+	expectedValue: flagutils.GlobPattern{Pattern: "src/**/*.go"},
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "*.txt",
+	defaultValue:  "*.md",
+	expectedValue: flagutils.GlobPattern{Pattern: "*.txt"},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  "**/*.md",
+	doubleStar:    true,
+	expectedValue: flagutils.GlobPattern{Pattern: "**/*.md", DoubleStar: true},
+}, {
+	about:         "error: malformed pattern",
+	name:          "err",
+	value:         "[a-",
+	expectedError: `invalid glob pattern "\[a-": syntax error in pattern`,
+}, {
+	about:         "error: malformed double star pattern",
+	name:          "err",
+	value:         "src/**/[a-",
+	defaultValue:  "*",
+	doubleStar:    true,
+	expectedValue: flagutils.GlobPattern{Pattern: "*", DoubleStar: true},
+	expectedError: `invalid glob pattern "src/\*\*/\[a-": syntax error in pattern`,
+}, {
+	about:         "error: partial double star segment",
+	name:          "err",
+	value:         "src/a**/*.go",
+	defaultValue:  "*",
+	doubleStar:    true,
+	expectedValue: flagutils.GlobPattern{Pattern: "*", DoubleStar: true},
+	expectedError: `invalid glob pattern "src/a\*\*/\*.go": \*\* must be a whole path segment`,
+}}
+
+func TestGlob(t *testing.T) {
+	for _, test := range globTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Glob(test.name, test.defaultValue, "glob usage", globOptions(test.doubleStar)...)
+			if test.value != "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestGlobVar(t *testing.T) {
+	for _, test := range globTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.GlobPattern
+			flagutils.GlobVar(&v, test.name, test.defaultValue, "glob usage", globOptions(test.doubleStar)...)
+			if test.value != "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestGlobValueString(t *testing.T) {
+	for _, test := range globTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.expectedError != "" {
+				return
+			}
+			v := flagutils.GlobValue{Pattern: test.defaultValue, DoubleStar: test.doubleStar}
+			c.Assert(v.String(), qt.Equals, test.defaultValue)
+			if test.value != "" {
+				err := v.Set(test.value)
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(v.String(), qt.Equals, test.expectedValue.Pattern)
+		})
+	}
+}
+
+func TestGlobDoubleStar(t *testing.T) {
+	t.Setenv("FLAGUTILS_SRC", "src/**/*.go")
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	src := fs.Glob("src", "*.go", "the sources", flagutils.DoubleStar(), flagutils.Env("FLAGUTILS_SRC"))
+	docs := fs.Glob("docs", "*.md", "the docs")
+	err := fs.Parse([]string{"-docs", "doc/*.md"})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(*src, qt.Equals, flagutils.GlobPattern{Pattern: "src/**/*.go", DoubleStar: true})
+	c.Assert(src.Match("src/a/b/main.go"), qt.Equals, true)
+	c.Assert(*docs, qt.Equals, flagutils.GlobPattern{Pattern: "doc/*.md"})
+	c.Assert(docs.Match("doc/a/README.md"), qt.Equals, false)
+}
+
+// globOptions returns the options for defining a glob pattern flag.
+func globOptions(doubleStar bool) []flagutils.GlobOption {
+	if doubleStar {
+		return []flagutils.GlobOption{flagutils.DoubleStar()}
+	}
+	return nil
+}
+
+var globPatternMatchTests = []struct {
+	pattern    string
+	doubleStar bool
+	name       string
+	expected   bool
+}{{
+	pattern:  "*.go",
+	name:     "main.go",
+	expected: true,
+}, {
+	pattern:  "*.go",
+	name:     "src/main.go",
+	expected: false,
+}, {
+	pattern:  "src/**/*.go",
+	name:     "src/a/b/main.go",
+	expected: false,
+}, {
+	pattern:  "src/*/*.go",
+	name:     "src/a/main.go",
+	expected: true,
+}, {
+	pattern:    "src/**/*.go",
+	doubleStar: true,
+	name:       "src/main.go",
+	expected:   true,
+}, {
+	pattern:    "src/**/*.go",
+	doubleStar: true,
+	name:       "src/a/b/c/main.go",
+	expected:   true,
+}, {
+	pattern:    "src/**/*.go",
+	doubleStar: true,
+	name:       "lib/a/main.go",
+	expected:   false,
+}, {
+	pattern:    "**",
+	doubleStar: true,
+	name:       "a/b/c",
+	expected:   true,
+}, {
+	pattern:    "**/testdata/**",
+	doubleStar: true,
+	name:       "pkg/testdata/golden/out.txt",
+	expected:   true,
+}, {
+	pattern:    "**/testdata/**",
+	doubleStar: true,
+	name:       "pkg/data/out.txt",
+	expected:   false,
+}, {
+	pattern:    "a/**/b/*.txt",
+	doubleStar: true,
+	name:       "a/x/b/y/b/c.txt",
+	expected:   true,
+}, {
+	pattern:    "a/**/b",
+	doubleStar: true,
+	name:       "a/b/c",
+	expected:   false,
+}}
+
+func TestGlobPatternMatch(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range globPatternMatchTests {
+		g := flagutils.GlobPattern{Pattern: test.pattern, DoubleStar: test.doubleStar}
+		c.Assert(g.Match(test.name), qt.Equals, test.expected, qt.Commentf("pattern %q (double star: %v), name %q", test.pattern, test.doubleStar, test.name))
+	}
+}
@@ -0,0 +1,73 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// GlobSlice defines a glob pattern slice flag with specified name, default
+// value, and usage string. The return value is the address of a string slice
+// variable that stores the value of the flag.
+func GlobSlice(name string, value []string, usage string, opts ...Option) *[]string {
+	var s []string
+	GlobSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// GlobSliceVar defines a glob pattern slice flag with specified name, default
+// value, and usage string. The argument p points to a string slice variable
+// in which to store the value of the flag.
+func GlobSliceVar(p *[]string, name string, value []string, usage string, opts ...Option) {
+	GlobSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// GlobSliceVarFS is like GlobSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func GlobSliceVarFS(fs *flag.FlagSet, p *[]string, name string, value []string, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*GlobSliceValue)(p), name, usage, opts)
+}
+
+// GlobSliceValue holds a slice of glob patterns that can be provided via the
+// command line as a comma separated list of values. Each pattern is validated
+// using filepath.Match syntax.
+type GlobSliceValue []string
+
+// String implements flag.Value by returning the slice as a string.
+func (s *GlobSliceValue) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *GlobSliceValue) Set(value string) error {
+	*s = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := checkGlob(v); err != nil {
+			return err
+		}
+	}
+	*s = values
+	return nil
+}
+
+// Type implements pflag.Value by returning "globSlice".
+func (s *GlobSliceValue) Type() string {
+	return "globSlice"
+}
+
+// checkGlob returns an error if the given glob pattern is malformed.
+func checkGlob(pattern string) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern %q: %v", pattern, err)
+	}
+	return nil
+}
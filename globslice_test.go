@@ -0,0 +1,109 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.GlobSliceValue)(nil)
+
+var globSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []string
+	expectedValue       []string
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single pattern",
+	name:                "single",
+	value:               "*.go",
+	expectedValue:       []string{"*.go"},
+	expectedStringValue: "*.go",
+}, {
+	about:               "multiple patterns",
+	name:                "multiple",
+	value:               "*.go, vendor/* ,file?.[ch],[!_]*",
+	expectedValue:       []string{"*.go", "vendor/*", "file?.[ch]", "[!_]*"},
+	expectedStringValue: "*.go,vendor/*,file?.[ch],[!_]*",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "*.go",
+	defaultValue:  []string{"*"},
+	expectedValue: []string{"*.go"},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []string{"*"},
+	expectedValue: []string{"*"},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: unclosed character class",
+	name:          "err",
+	value:         "*.go,[abc",
+	expectedError: `invalid glob pattern "\[abc": syntax error in pattern`,
+}, {
+	about:         "error: bad pattern after a literal",
+	name:          "err",
+	value:         `abc[`,
+	expectedError: `invalid glob pattern "abc\[": syntax error in pattern`,
+}}
+
+func TestGlobSlice(t *testing.T) {
+	for _, test := range globSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.GlobSlice(test.name, test.defaultValue, "glob slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestGlobSliceVar(t *testing.T) {
+	for _, test := range globSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []string
+			flagutils.GlobSliceVar(&v, test.name, test.defaultValue, "glob slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestGlobSliceValueString(t *testing.T) {
+	for _, test := range globSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.GlobSliceValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -1,3 +1,19 @@
 module github.com/frankban/flagutils
 
-require github.com/frankban/quicktest v1.0.0
+go 1.21
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/frankban/quicktest v1.0.0
+	github.com/itchyny/gojq v0.12.17
+	golang.org/x/text v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/google/go-cmp v0.5.4 // indirect
+	github.com/itchyny/timefmt-go v0.1.6 // indirect
+	github.com/kr/pretty v0.1.0 // indirect
+	github.com/kr/text v0.1.0 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+)
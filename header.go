@@ -0,0 +1,123 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Header defines an HTTP header flag with specified name, default value, and
+// usage string. The return value is the address of an http.Header variable
+// that stores the value of the flag.
+func Header(name string, value http.Header, usage string, opts ...Option) *http.Header {
+	var h http.Header
+	HeaderVar(&h, name, value, usage, opts...)
+	return &h
+}
+
+// HeaderVar defines an HTTP header flag with specified name, default value,
+// and usage string. The argument p points to an http.Header variable in which
+// to store the value of the flag.
+func HeaderVar(p *http.Header, name string, value http.Header, usage string, opts ...Option) {
+	HeaderVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// HeaderVarFS is like HeaderVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func HeaderVarFS(fs *flag.FlagSet, p *http.Header, name string, value http.Header, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewHeaderValue(p), name, usage, opts)
+}
+
+// NewHeaderValue returns a HeaderValue storing its value in p.
+func NewHeaderValue(p *http.Header) *HeaderValue {
+	return &HeaderValue{
+		p: p,
+	}
+}
+
+// HeaderValue holds HTTP headers that can be provided via the command line
+// using the same syntax as the curl -H option. The flag can be repeated, with
+// one "Name: value" header per occurrence, and repeated names add further
+// values to the same header. As in curl, "Name:" with no value removes the
+// header, and "Name;" adds the header with an empty value. Header names are
+// canonicalized. The first occurrence replaces the default value.
+type HeaderValue struct {
+	p   *http.Header
+	set bool
+}
+
+// String implements flag.Value by returning the headers sorted by name, as
+// comma separated "Name: value" items.
+func (h *HeaderValue) String() string {
+	if h.p == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(*h.p))
+	for k := range *h.p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var values []string
+	for _, k := range keys {
+		for _, v := range (*h.p)[k] {
+			values = append(values, k+": "+v)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// Set implements flag.Value by adding the given "Name: value" header.
+func (h *HeaderValue) Set(value string) error {
+	if !h.set {
+		*h.p = make(http.Header)
+		h.set = true
+	}
+	if name, ok := strings.CutSuffix(value, ";"); ok && !strings.Contains(name, ":") {
+		if !isToken(name) {
+			return fmt.Errorf("invalid header %q: invalid name", value)
+		}
+		h.p.Add(name, "")
+		return nil
+	}
+	name, v, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("invalid header %q: expected \"Name: value\"", value)
+	}
+	if !isToken(name) {
+		return fmt.Errorf("invalid header %q: invalid name", value)
+	}
+	v = strings.TrimSpace(v)
+	if v == "" {
+		h.p.Del(name)
+		return nil
+	}
+	if strings.ContainsAny(v, "\r\n\x00") {
+		return fmt.Errorf("invalid header %q: invalid value", value)
+	}
+	h.p.Add(name, v)
+	return nil
+}
+
+// Type implements pflag.Value by returning "header".
+func (h *HeaderValue) Type() string {
+	return "header"
+}
+
+// isToken reports whether the given string is a valid HTTP token, as defined
+// by RFC 7230, and can therefore be used as a header name.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r >= 0x7f || r <= ' ' || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", r) {
+			return false
+		}
+	}
+	return true
+}
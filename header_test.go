@@ -0,0 +1,153 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"net/http"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.HeaderValue)(nil)
+
+var headerTests = []struct {
+	about               string
+	name                string
+	values              []string
+	defaultValue        http.Header
+	expectedValue       http.Header
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single header",
+	name:                "single",
+	values:              []string{"Accept: application/json"},
+	expectedValue:       http.Header{"Accept": {"application/json"}},
+	expectedStringValue: "Accept: application/json",
+}, {
+	about:  "repeated headers",
+	name:   "repeated",
+	values: []string{"x-request-id:42", "Cookie: a=1", "cookie:  b=2, c=3 "},
+	expectedValue: http.Header{
+		"X-Request-Id": {"42"},
+		"Cookie":       {"a=1", "b=2, c=3"},
+	},
+	expectedStringValue: "Cookie: a=1, Cookie: b=2, c=3, X-Request-Id: 42",
+}, {
+	about:               "header with empty value",
+	name:                "empty",
+	values:              []string{"X-Empty;"},
+	expectedValue:       http.Header{"X-Empty": {""}},
+	expectedStringValue: "X-Empty: ",
+}, {
+	about:               "header removal",
+	name:                "remove",
+	values:              []string{"Accept: text/html", "X-Debug: 1", "accept:"},
+	expectedValue:       http.Header{"X-Debug": {"1"}},
+	expectedStringValue: "X-Debug: 1",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	values:        []string{"Accept: text/plain"},
+	defaultValue:  http.Header{"User-Agent": {"flagutils"}},
+	expectedValue: http.Header{"Accept": {"text/plain"}},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  http.Header{"User-Agent": {"flagutils"}},
+	expectedValue: http.Header{"User-Agent": {"flagutils"}},
+}, {
+	about:         "error: missing colon",
+	name:          "err",
+	values:        []string{"Accept application/json"},
+	expectedError: `invalid header "Accept application/json": expected "Name: value"`,
+}, {
+	about:         "error: empty name",
+	name:          "err",
+	values:        []string{": value"},
+	expectedError: `invalid header ": value": invalid name`,
+}, {
+	about:         "error: invalid name",
+	name:          "err",
+	values:        []string{"Bad Name: value"},
+	expectedError: `invalid header "Bad Name: value": invalid name`,
+}, {
+	about:         "error: invalid value",
+	name:          "err",
+	values:        []string{"X-Test: a\nb"},
+	expectedError: `invalid header "X-Test: a\\nb": invalid value`,
+}}
+
+func TestHeader(t *testing.T) {
+	for _, test := range headerTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Header(test.name, test.defaultValue, "header usage")
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set(test.name, value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+				c.Assert(*v, qt.DeepEquals, test.expectedValue)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestHeaderVar(t *testing.T) {
+	for _, test := range headerTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v http.Header
+			flagutils.HeaderVar(&v, test.name, test.defaultValue, "header usage")
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set(test.name, value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+				c.Assert(v, qt.DeepEquals, test.expectedValue)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestHeaderDefaultNotModified(t *testing.T) {
+	runIsolated(t, "default not modified", func(c *qt.C) {
+		defaultValue := http.Header{"User-Agent": {"flagutils"}}
+		flagutils.Header("h", defaultValue, "header usage")
+		err := flag.Set("h", "User-Agent: curl")
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(defaultValue, qt.DeepEquals, http.Header{"User-Agent": {"flagutils"}})
+	})
+}
+
+func TestHeaderValueString(t *testing.T) {
+	for _, test := range headerTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v http.Header
+			h := flagutils.NewHeaderValue(&v)
+			c.Assert(h.String(), qt.Equals, "")
+			for _, value := range test.values {
+				h.Set(value)
+			}
+			if test.expectedError == "" {
+				c.Assert(h.String(), qt.Equals, test.expectedStringValue)
+			}
+		})
+	}
+}
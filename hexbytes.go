@@ -0,0 +1,79 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+)
+
+// HexBytes defines a byte slice flag with specified name, default value,
+// required length and usage string. The value is provided via the command
+// line as a hexadecimal string. If length is positive, the decoded value
+// must be exactly length bytes long, which is useful for keys. The return
+// value is the address of a byte slice variable that stores the decoded
+// value of the flag.
+func HexBytes(name string, value []byte, length int, usage string, opts ...Option) *[]byte {
+	var b []byte
+	HexBytesVar(&b, name, value, length, usage, opts...)
+	return &b
+}
+
+// HexBytesVar defines a byte slice flag with specified name, default value,
+// required length and usage string. See HexBytes for details. The argument p
+// points to a byte slice variable in which to store the decoded value of the
+// flag.
+func HexBytesVar(p *[]byte, name string, value []byte, length int, usage string, opts ...Option) {
+	HexBytesVarFS(flag.CommandLine, p, name, value, length, usage, opts...)
+}
+
+// HexBytesVarFS is like HexBytesVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func HexBytesVarFS(fs *flag.FlagSet, p *[]byte, name string, value []byte, length int, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewHexBytesValue(p, length), name, usage, opts)
+}
+
+// NewHexBytesValue returns a HexBytesValue storing its value in p. If length
+// is positive, only values decoding to exactly length bytes are accepted.
+func NewHexBytesValue(p *[]byte, length int) *HexBytesValue {
+	return &HexBytesValue{
+		p:      p,
+		length: length,
+	}
+}
+
+// HexBytesValue holds a byte slice that can be provided via the command line
+// as a hexadecimal string.
+type HexBytesValue struct {
+	p      *[]byte
+	length int
+}
+
+// String implements flag.Value by returning the bytes encoded as a lowercase
+// hexadecimal string.
+func (h *HexBytesValue) String() string {
+	if h.p == nil {
+		return ""
+	}
+	return hex.EncodeToString(*h.p)
+}
+
+// Set implements flag.Value by decoding the given hexadecimal value.
+func (h *HexBytesValue) Set(value string) error {
+	b, err := hex.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("invalid hex value %q", value)
+	}
+	if h.length > 0 && len(b) != h.length {
+		return fmt.Errorf("invalid hex value %q: expected %d bytes, got %d", value, h.length, len(b))
+	}
+	*h.p = b
+	return nil
+}
+
+// Type implements pflag.Value by returning "bytesHex".
+func (h *HexBytesValue) Type() string {
+	return "bytesHex"
+}
@@ -0,0 +1,138 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.HexBytesValue)(nil)
+
+var hexBytesTests = []struct {
+	about               string
+	name                string
+	value               string
+	length              int
+	defaultValue        []byte
+	expectedValue       []byte
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "lowercase",
+	name:                "lower",
+	value:               "deadbeef",
+	expectedValue:       []byte{0xde, 0xad, 0xbe, 0xef},
+	expectedStringValue: "deadbeef",
+}, {
+	about:               "uppercase",
+	name:                "upper",
+	value:               "DEADBEEF",
+	expectedValue:       []byte{0xde, 0xad, 0xbe, 0xef},
+	expectedStringValue: "deadbeef",
+}, {
+	about:               "required length",
+	name:                "length",
+	value:               "00010203",
+	length:              4,
+	expectedValue:       []byte{0, 1, 2, 3},
+	expectedStringValue: "00010203",
+}, {
+	about:               "empty string",
+	name:                "empty",
+	expectedValue:       []byte{},
+	expectedStringValue: "",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "ff",
+	defaultValue:  []byte{0},
+	expectedValue: []byte{0xff},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []byte{0},
+	expectedValue: []byte{0},
+}, {
+	about:         "error: odd length",
+	name:          "err",
+	value:         "abc",
+	expectedError: `invalid hex value "abc"`,
+}, {
+	about:         "error: invalid characters",
+	name:          "err",
+	value:         "bad wolf",
+	expectedError: `invalid hex value "bad wolf"`,
+}, {
+	about:         "error: wrong length",
+	name:          "err",
+	value:         "deadbeef",
+	length:        32,
+	expectedError: `invalid hex value "deadbeef": expected 32 bytes, got 4`,
+}, {
+	about:         "error: empty string with required length",
+	name:          "err",
+	length:        32,
+	expectedError: `invalid hex value "": expected 32 bytes, got 0`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "0x00",
+	defaultValue:  []byte{0},
+	expectedValue: []byte{0},
+	expectedError: `invalid hex value "0x00"`,
+}}
+
+func TestHexBytes(t *testing.T) {
+	for _, test := range hexBytesTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.HexBytes(test.name, test.defaultValue, test.length, "hex bytes usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestHexBytesVar(t *testing.T) {
+	for _, test := range hexBytesTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []byte
+			flagutils.HexBytesVar(&v, test.name, test.defaultValue, test.length, "hex bytes usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestHexBytesValueString(t *testing.T) {
+	for _, test := range hexBytesTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil || test.expectedError != "" {
+				return
+			}
+			var v []byte
+			h := flagutils.NewHexBytesValue(&v, test.length)
+			err := h.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(h.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
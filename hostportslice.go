@@ -0,0 +1,102 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// HostPort holds a host and a port, for instance as provided in the
+// "example.com:8080" form.
+type HostPort struct {
+	Host string
+	Port int
+}
+
+// String returns the host and port joined by net.JoinHostPort.
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(hp.Port))
+}
+
+// HostPortSlice defines a host:port slice flag with specified name, default
+// value, and usage string. The return value is the address of a HostPort
+// slice variable that stores the value of the flag.
+func HostPortSlice(name string, value []HostPort, usage string, opts ...Option) *[]HostPort {
+	var s []HostPort
+	HostPortSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// HostPortSliceVar defines a host:port slice flag with specified name,
+// default value, and usage string. The argument p points to a HostPort slice
+// variable in which to store the value of the flag.
+func HostPortSliceVar(p *[]HostPort, name string, value []HostPort, usage string, opts ...Option) {
+	HostPortSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// HostPortSliceVarFS is like HostPortSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func HostPortSliceVarFS(fs *flag.FlagSet, p *[]HostPort, name string, value []HostPort, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*HostPortSliceValue)(p), name, usage, opts)
+}
+
+// HostPortSliceValue holds a slice of host and port pairs that can be
+// provided via the command line as a comma separated list of values, for
+// instance "a:7000,b:7000,[::1]:7000".
+type HostPortSliceValue []HostPort
+
+// String implements flag.Value by returning the slice as a string.
+func (s *HostPortSliceValue) String() string {
+	values := make([]string, len(*s))
+	for i, hp := range *s {
+		values[i] = hp.String()
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *HostPortSliceValue) Set(value string) error {
+	*s = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	hps := make([]HostPort, len(values))
+	for i, v := range values {
+		if hps[i], err = parseHostPort(v); err != nil {
+			return err
+		}
+	}
+	*s = hps
+	return nil
+}
+
+// Type implements pflag.Value by returning "hostPortSlice".
+func (s *HostPortSliceValue) Type() string {
+	return "hostPortSlice"
+}
+
+// parseHostPort parses the given "host:port" value.
+func parseHostPort(value string) (HostPort, error) {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return HostPort{}, fmt.Errorf("invalid host:port value %q: %v", value, err)
+	}
+	if host == "" {
+		return HostPort{}, fmt.Errorf("invalid host:port value %q: missing host", value)
+	}
+	p, err := parsePort(port)
+	if err != nil {
+		return HostPort{}, fmt.Errorf("invalid host:port value %q: %v", value, err)
+	}
+	return HostPort{
+		Host: host,
+		Port: p,
+	}, nil
+}
@@ -0,0 +1,119 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.HostPortSliceValue)(nil)
+
+var hostPortSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []flagutils.HostPort
+	expectedValue       []flagutils.HostPort
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single pair",
+	name:                "single",
+	value:               "example.com:8080",
+	expectedValue:       []flagutils.HostPort{{Host: "example.com", Port: 8080}},
+	expectedStringValue: "example.com:8080",
+}, {
+	about: "multiple pairs",
+	name:  "multiple",
+	value: "a:7000, b:7001 ,1.2.3.4:7002,[::1]:7003",
+	expectedValue: []flagutils.HostPort{
+		{Host: "a", Port: 7000},
+		{Host: "b", Port: 7001},
+		{Host: "1.2.3.4", Port: 7002},
+		{Host: "::1", Port: 7003},
+	},
+	expectedStringValue: "a:7000,b:7001,1.2.3.4:7002,[::1]:7003",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "a:7000",
+	defaultValue:  []flagutils.HostPort{{Host: "localhost", Port: 80}},
+	expectedValue: []flagutils.HostPort{{Host: "a", Port: 7000}},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []flagutils.HostPort{{Host: "localhost", Port: 80}},
+	expectedValue: []flagutils.HostPort{{Host: "localhost", Port: 80}},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: missing port",
+	name:          "err",
+	value:         "a:7000,b",
+	expectedError: `invalid host:port value "b": address b: missing port in address`,
+}, {
+	about:         "error: missing host",
+	name:          "err",
+	value:         ":7000",
+	expectedError: `invalid host:port value ":7000": missing host`,
+}, {
+	about:         "error: invalid port",
+	name:          "err",
+	value:         "a:http",
+	expectedError: `invalid host:port value "a:http": invalid port "http": must be a number between 1 and 65535`,
+}}
+
+func TestHostPortSlice(t *testing.T) {
+	for _, test := range hostPortSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.HostPortSlice(test.name, test.defaultValue, "host port slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestHostPortSliceVar(t *testing.T) {
+	for _, test := range hostPortSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []flagutils.HostPort
+			flagutils.HostPortSliceVar(&v, test.name, test.defaultValue, "host port slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestHostPortSliceValueString(t *testing.T) {
+	for _, test := range hostPortSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.HostPortSliceValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
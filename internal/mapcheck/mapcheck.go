@@ -0,0 +1,60 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+// Package mapcheck provides checks on decoded string map values shared by the
+// flagutils subpackages.
+package mapcheck
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/frankban/flagutils"
+)
+
+// Finite returns a *flagutils.PathError if any value in the given map, or
+// any value nested in it, is a NaN or infinite number. Such numbers are valid
+// in YAML and TOML, but they cannot be encoded as JSON.
+func Finite(m map[string]interface{}) error {
+	return checkFinite("", m)
+}
+
+// checkFinite checks the given decoded value stored at the given path.
+func checkFinite(path string, v interface{}) error {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return &flagutils.PathError{
+				Path: path,
+				Err:  fmt.Errorf("unsupported number %v: NaN and infinity are not allowed", v),
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := checkFinite(joinPath(path, key), v[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			if err := checkFinite(joinPath(path, strconv.Itoa(i)), elem); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// joinPath appends the given key to the dot separated path.
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
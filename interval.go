@@ -0,0 +1,17 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import "cmp"
+
+// Interval holds a closed interval of ordered values, for instance a port
+// range.
+type Interval[T cmp.Ordered] struct {
+	Min T
+	Max T
+}
+
+// Contains reports whether v is included in the interval, bounds included.
+func (i Interval[T]) Contains(v T) bool {
+	return i.Min <= v && v <= i.Max
+}
@@ -0,0 +1,21 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+func TestIntervalContains(t *testing.T) {
+	c := qt.New(t)
+	r := flagutils.Interval[int]{Min: 80, Max: 443}
+	c.Assert(r.Contains(79), qt.Equals, false)
+	c.Assert(r.Contains(80), qt.Equals, true)
+	c.Assert(r.Contains(200), qt.Equals, true)
+	c.Assert(r.Contains(443), qt.Equals, true)
+	c.Assert(r.Contains(444), qt.Equals, false)
+}
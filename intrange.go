@@ -0,0 +1,89 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IntRange defines an integer range flag with specified name, default value,
+// and usage string. The value is provided via the command line as "min-max",
+// for instance "1-10", or as a single integer. The return value is the
+// address of an interval variable that stores the value of the flag.
+func IntRange(name string, value Interval[int], usage string, opts ...Option) *Interval[int] {
+	var r Interval[int]
+	IntRangeVar(&r, name, value, usage, opts...)
+	return &r
+}
+
+// IntRangeVar defines an integer range flag with specified name, default
+// value, and usage string. The argument p points to an interval variable in
+// which to store the value of the flag.
+func IntRangeVar(p *Interval[int], name string, value Interval[int], usage string, opts ...Option) {
+	IntRangeVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// IntRangeVarFS is like IntRangeVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func IntRangeVarFS(fs *flag.FlagSet, p *Interval[int], name string, value Interval[int], usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*IntRangeValue)(p), name, usage, opts)
+}
+
+// IntRangeValue holds a range of integers that can be provided via the
+// command line. Both bounds are included in the range, and can be negative,
+// as in "-10--5". A single integer is stored as a range including only that
+// value.
+type IntRangeValue Interval[int]
+
+// String implements flag.Value by returning the range as "min-max".
+func (r *IntRangeValue) String() string {
+	return strconv.Itoa(r.Min) + "-" + strconv.Itoa(r.Max)
+}
+
+// Set implements flag.Value by parsing the given integer range.
+func (r *IntRangeValue) Set(value string) error {
+	lo, hi := splitRange(value)
+	loInt, err := strconv.Atoi(lo)
+	if err != nil {
+		return fmt.Errorf("invalid integer range %q: invalid bound %q", value, lo)
+	}
+	hiInt, err := strconv.Atoi(hi)
+	if err != nil {
+		return fmt.Errorf("invalid integer range %q: invalid bound %q", value, hi)
+	}
+	if loInt > hiInt {
+		return fmt.Errorf("invalid integer range %q: %d is greater than %d", value, loInt, hiInt)
+	}
+	*r = IntRangeValue{
+		Min: loInt,
+		Max: hiInt,
+	}
+	return nil
+}
+
+// Type implements pflag.Value by returning "intRange".
+func (r *IntRangeValue) Type() string {
+	return "intRange"
+}
+
+// splitRange splits the given "min-max" range into its bounds, with spaces
+// removed. A dash only separates the bounds when it follows a digit or a
+// dot, so that negative bounds can be provided. If there is no separator,
+// the value is returned as both bounds.
+func splitRange(value string) (lo, hi string) {
+	value = strings.TrimSpace(value)
+	for i := 1; i < len(value); i++ {
+		if value[i] != '-' {
+			continue
+		}
+		lo = strings.TrimSpace(value[:i])
+		if last := lo[len(lo)-1]; last == '.' || '0' <= last && last <= '9' {
+			return lo, strings.TrimSpace(value[i+1:])
+		}
+	}
+	return value, value
+}
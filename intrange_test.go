@@ -0,0 +1,141 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.IntRangeValue)(nil)
+
+var intRangeTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.Interval[int]
+	expectedValue       flagutils.Interval[int]
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "range",
+	name:                "range",
+	value:               "1-10",
+	expectedValue:       flagutils.Interval[int]{Min: 1, Max: 10},
+	expectedStringValue: "1-10",
+}, {
+	about:               "spaces",
+	name:                "spaces",
+	value:               " 0 - 7 ",
+	expectedValue:       flagutils.Interval[int]{Min: 0, Max: 7},
+	expectedStringValue: "0-7",
+}, {
+	about:               "single value",
+	name:                "single",
+	value:               "3",
+	expectedValue:       flagutils.Interval[int]{Min: 3, Max: 3},
+	expectedStringValue: "3-3",
+}, {
+	about:               "negative bounds",
+	name:                "negative",
+	value:               "-10--5",
+	expectedValue:       flagutils.Interval[int]{Min: -10, Max: -5},
+	expectedStringValue: "-10--5",
+}, {
+	about:               "negative single value",
+	name:                "negative-single",
+	value:               "-4",
+	expectedValue:       flagutils.Interval[int]{Min: -4, Max: -4},
+	expectedStringValue: "-4--4",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "5-8",
+	defaultValue:  flagutils.Interval[int]{Min: 0, Max: 3},
+	expectedValue: flagutils.Interval[int]{Min: 5, Max: 8},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  flagutils.Interval[int]{Min: 0, Max: 3},
+	expectedValue: flagutils.Interval[int]{Min: 0, Max: 3},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid integer range "": invalid bound ""`,
+}, {
+	about:         "error: invalid bound",
+	name:          "err",
+	value:         "1-ten",
+	expectedError: `invalid integer range "1-ten": invalid bound "ten"`,
+}, {
+	about:         "error: missing bound",
+	name:          "err",
+	value:         "1-",
+	expectedError: `invalid integer range "1-": invalid bound ""`,
+}, {
+	about:         "error: wrong order",
+	name:          "err",
+	value:         "10-1",
+	expectedError: `invalid integer range "10-1": 10 is greater than 1`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "3-2",
+	defaultValue:  flagutils.Interval[int]{Min: 0, Max: 3},
+	expectedValue: flagutils.Interval[int]{Min: 0, Max: 3},
+	expectedError: `invalid integer range "3-2": 3 is greater than 2`,
+}}
+
+func TestIntRange(t *testing.T) {
+	for _, test := range intRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.IntRange(test.name, test.defaultValue, "int range usage")
+			if test.value != "" || test.defaultValue == (flagutils.Interval[int]{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestIntRangeVar(t *testing.T) {
+	for _, test := range intRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.Interval[int]
+			flagutils.IntRangeVar(&v, test.name, test.defaultValue, "int range usage")
+			if test.value != "" || test.defaultValue == (flagutils.Interval[int]{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestIntRangeValueString(t *testing.T) {
+	for _, test := range intRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != (flagutils.Interval[int]{}) || test.expectedError != "" {
+				return
+			}
+			var v flagutils.IntRangeValue
+			c.Assert(v.String(), qt.Equals, "0-0")
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
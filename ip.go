@@ -0,0 +1,90 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net"
+)
+
+// IPVersion restricts the IP addresses accepted by an IP flag.
+type IPVersion int
+
+const (
+	// AnyIP accepts both IPv4 and IPv6 addresses.
+	AnyIP IPVersion = iota
+	// IPv4Only only accepts IPv4 addresses.
+	IPv4Only
+	// IPv6Only only accepts IPv6 addresses.
+	IPv6Only
+)
+
+// IP defines an IP address flag with specified name, default value, accepted
+// IP version and usage string. The return value is the address of an IP
+// variable that stores the value of the flag.
+func IP(name string, value net.IP, version IPVersion, usage string, opts ...Option) *net.IP {
+	var ip net.IP
+	IPVar(&ip, name, value, version, usage, opts...)
+	return &ip
+}
+
+// IPVar defines an IP address flag with specified name, default value,
+// accepted IP version and usage string. The argument p points to an IP
+// variable in which to store the value of the flag.
+func IPVar(p *net.IP, name string, value net.IP, version IPVersion, usage string, opts ...Option) {
+	IPVarFS(flag.CommandLine, p, name, value, version, usage, opts...)
+}
+
+// IPVarFS is like IPVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func IPVarFS(fs *flag.FlagSet, p *net.IP, name string, value net.IP, version IPVersion, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewIPValue(p, version), name, usage, opts)
+}
+
+// NewIPValue returns an IPValue storing its value in p and only accepting
+// addresses of the given IP version.
+func NewIPValue(p *net.IP, version IPVersion) *IPValue {
+	return &IPValue{
+		p:       p,
+		version: version,
+	}
+}
+
+// IPValue holds an IP address that can be provided via the command line in
+// either IPv4 dotted decimal ("192.0.2.1") or IPv6 ("2001:db8::1") form.
+type IPValue struct {
+	p       *net.IP
+	version IPVersion
+}
+
+// String implements flag.Value by returning the IP address as a string.
+func (ip *IPValue) String() string {
+	if ip.p == nil || *ip.p == nil {
+		return ""
+	}
+	return ip.p.String()
+}
+
+// Set implements flag.Value by parsing the given IP address.
+func (ip *IPValue) Set(value string) error {
+	parsed := net.ParseIP(value)
+	if parsed == nil {
+		return fmt.Errorf("invalid IP address %q", value)
+	}
+	isV4 := parsed.To4() != nil
+	switch {
+	case ip.version == IPv4Only && !isV4:
+		return fmt.Errorf("invalid IP address %q: not an IPv4 address", value)
+	case ip.version == IPv6Only && isV4:
+		return fmt.Errorf("invalid IP address %q: not an IPv6 address", value)
+	}
+	*ip.p = parsed
+	return nil
+}
+
+// Type implements pflag.Value by returning "ip".
+func (ip *IPValue) Type() string {
+	return "ip"
+}
@@ -0,0 +1,140 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"net"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.IPValue)(nil)
+
+var ipTests = []struct {
+	about               string
+	name                string
+	value               string
+	version             flagutils.IPVersion
+	defaultValue        net.IP
+	expectedValue       net.IP
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "IPv4 address",
+	name:                "v4",
+	value:               "192.0.2.1",
+	expectedValue:       net.ParseIP("192.0.2.1"),
+	expectedStringValue: "192.0.2.1",
+}, {
+	about:               "IPv6 address",
+	name:                "v6",
+	value:               "2001:db8:0:0::1",
+	expectedValue:       net.ParseIP("2001:db8::1"),
+	expectedStringValue: "2001:db8::1",
+}, {
+	about:               "IPv4 only",
+	name:                "v4only",
+	value:               "10.0.0.1",
+	version:             flagutils.IPv4Only,
+	expectedValue:       net.ParseIP("10.0.0.1"),
+	expectedStringValue: "10.0.0.1",
+}, {
+	about:               "IPv6 only",
+	name:                "v6only",
+	value:               "::1",
+	version:             flagutils.IPv6Only,
+	expectedValue:       net.ParseIP("::1"),
+	expectedStringValue: "::1",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "10.0.0.1",
+	defaultValue:  net.ParseIP("127.0.0.1"),
+	expectedValue: net.ParseIP("10.0.0.1"),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  net.ParseIP("127.0.0.1"),
+	expectedValue: net.ParseIP("127.0.0.1"),
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid IP address ""`,
+}, {
+	about:         "error: invalid address",
+	name:          "err",
+	value:         "192.0.2.256",
+	expectedError: `invalid IP address "192.0.2.256"`,
+}, {
+	about:         "error: not IPv4",
+	name:          "err",
+	value:         "2001:db8::1",
+	version:       flagutils.IPv4Only,
+	expectedError: `invalid IP address "2001:db8::1": not an IPv4 address`,
+}, {
+	about:         "error: not IPv6",
+	name:          "err",
+	value:         "192.0.2.1",
+	version:       flagutils.IPv6Only,
+	expectedError: `invalid IP address "192.0.2.1": not an IPv6 address`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "localhost",
+	defaultValue:  net.ParseIP("127.0.0.1"),
+	expectedValue: net.ParseIP("127.0.0.1"),
+	expectedError: `invalid IP address "localhost"`,
+}}
+
+func TestIP(t *testing.T) {
+	for _, test := range ipTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.IP(test.name, test.defaultValue, test.version, "IP usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestIPVar(t *testing.T) {
+	for _, test := range ipTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v net.IP
+			flagutils.IPVar(&v, test.name, test.defaultValue, test.version, "IP usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestIPValueString(t *testing.T) {
+	for _, test := range ipTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v net.IP
+			ip := flagutils.NewIPValue(&v, test.version)
+			ip.Set(test.value)
+			c.Assert(ip.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+// Package jqflag provides command line flags holding jq queries. It is kept
+// separate from the flagutils package so that programs not using jq queries
+// do not depend on gojq.
+package jqflag
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+
+	"github.com/frankban/flagutils"
+)
+
+// JQ defines a jq query flag with specified name, default value, and usage
+// string. The return value is the address of a *gojq.Query variable that
+// stores the parsed value of the flag.
+func JQ(name string, value *gojq.Query, usage string, opts ...flagutils.Option) **gojq.Query {
+	var q *gojq.Query
+	JQVar(&q, name, value, usage, opts...)
+	return &q
+}
+
+// JQVar defines a jq query flag with specified name, default value, and
+// usage string. The argument p points to a *gojq.Query variable in which to
+// store the parsed value of the flag.
+func JQVar(p **gojq.Query, name string, value *gojq.Query, usage string, opts ...flagutils.Option) {
+	JQVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// JQVarFS is like JQVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func JQVarFS(fs *flag.FlagSet, p **gojq.Query, name string, value *gojq.Query, usage string, opts ...flagutils.Option) {
+	*p = value
+	flagutils.VarFS(fs, NewJQValue(p), name, usage, opts...)
+}
+
+// NewJQValue returns a JQValue storing its value in p.
+func NewJQValue(p **gojq.Query) *JQValue {
+	return &JQValue{
+		p: p,
+	}
+}
+
+// JQValue holds a jq query that can be provided via the command line, as in
+// ".items[] | select(.enabled)". The query is parsed with gojq.Parse when
+// the flag is set, so that syntax errors are reported as flag errors. The
+// query can then be compiled with gojq.Compile, for instance providing
+// variables.
+type JQValue struct {
+	p **gojq.Query
+}
+
+// String implements flag.Value by returning the query as a string.
+func (q *JQValue) String() string {
+	if q.p == nil || *q.p == nil {
+		return ""
+	}
+	return (*q.p).String()
+}
+
+// Set implements flag.Value by parsing the given jq query.
+func (q *JQValue) Set(value string) error {
+	query, err := gojq.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid jq query %q: %v", value, err)
+	}
+	*q.p = query
+	return nil
+}
+
+// Type implements pflag.Value by returning "jq".
+func (q *JQValue) Type() string {
+	return "jq"
+}
@@ -0,0 +1,158 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package jqflag_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/itchyny/gojq"
+
+	"github.com/frankban/flagutils"
+	"github.com/frankban/flagutils/jqflag"
+)
+
+var _ flag.Value = (*jqflag.JQValue)(nil)
+
+var jqTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        string
+	input               interface{}
+	expectedStringValue string
+	expectedResult      interface{}
+	expectedError       string
+}{{
+	about:               "valid query",
+	name:                "valid",
+	value:               ".items[] | select(.enabled) | .name",
+	input:               map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": "bad", "enabled": false}, map[string]interface{}{"name": "wolf", "enabled": true}}},
+	expectedStringValue: ".items[] | select(.enabled) | .name",
+	expectedResult:      "wolf",
+}, {
+	about:               "default value: with value",
+	name:                "def1",
+	value:               ".b",
+	defaultValue:        ".a",
+	input:               map[string]interface{}{"a": 1, "b": 2},
+	expectedStringValue: ".b",
+	expectedResult:      2,
+}, {
+	about:               "default value: without value",
+	name:                "def2",
+	defaultValue:        ".a",
+	input:               map[string]interface{}{"a": 1, "b": 2},
+	expectedStringValue: ".a",
+	expectedResult:      1,
+}, {
+	about:         "error: unexpected end",
+	name:          "err",
+	value:         ".items[",
+	expectedError: `invalid jq query "\.items\[": unexpected EOF`,
+}, {
+	about:         "error: unexpected token",
+	name:          "err",
+	value:         ".a | | .b",
+	expectedError: `invalid jq query "\.a \| \| \.b": unexpected token "\|"`,
+}}
+
+func TestJQ(t *testing.T) {
+	for _, test := range jqTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := jqflag.JQ(test.name, parseJQ(c, test.defaultValue), "jq usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					c.Assert(*v, qt.IsNil)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert((*v).String(), qt.Equals, test.expectedStringValue)
+			c.Assert(runJQ(c, *v, test.input), qt.Equals, test.expectedResult)
+		})
+	}
+}
+
+func TestJQVar(t *testing.T) {
+	for _, test := range jqTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v *gojq.Query
+			jqflag.JQVar(&v, test.name, parseJQ(c, test.defaultValue), "jq usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					c.Assert(v, qt.IsNil)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+			c.Assert(runJQ(c, v, test.input), qt.Equals, test.expectedResult)
+		})
+	}
+}
+
+func TestJQValueString(t *testing.T) {
+	for _, test := range jqTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != "" || test.expectedError != "" {
+				return
+			}
+			var v *gojq.Query
+			q := jqflag.NewJQValue(&v)
+			c.Assert(q.String(), qt.Equals, "")
+			c.Assert(q.Type(), qt.Equals, "jq")
+			err := q.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(q.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestJQWithOptions(t *testing.T) {
+	t.Setenv("FLAGUTILS_QUERY", ".name")
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	var q *gojq.Query
+	jqflag.JQVarFS(fs.FlagSet, &q, "query", nil, "the query", flagutils.Env("FLAGUTILS_QUERY"))
+	c.Assert(fs.Lookup("query").Usage, qt.Equals, "the query (env FLAGUTILS_QUERY)")
+	err := fs.Parse(nil)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(q.String(), qt.Equals, ".name")
+}
+
+// parseJQ parses the given jq query, returning nil if the query is empty.
+func parseJQ(c *qt.C, value string) *gojq.Query {
+	if value == "" {
+		return nil
+	}
+	q, err := gojq.Parse(value)
+	c.Assert(err, qt.Equals, nil)
+	return q
+}
+
+// runJQ runs the given query against the input and returns the first result.
+func runJQ(c *qt.C, q *gojq.Query, input interface{}) interface{} {
+	v, ok := q.Run(input).Next()
+	c.Assert(ok, qt.Equals, true)
+	err, _ := v.(error)
+	c.Assert(err, qt.IsNil)
+	return v
+}
+
+// runIsolated runs the given test function without clobbering global flags.
+func runIsolated(t *testing.T, name string, f func(c *qt.C)) {
+	original := flag.CommandLine
+	flag.CommandLine = flag.NewFlagSet("", flag.ContinueOnError)
+	defer func() {
+		flag.CommandLine = original
+	}()
+	qt.New(t).Run(name, f)
+}
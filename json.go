@@ -0,0 +1,159 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// JSONVar defines a flag with specified name and usage string, whose JSON
+// encoded value is decoded into the value pointed to by p. The value pointed
+// to by p is used as the default value, and fields not included in the JSON
+// provided via the command line are left untouched. When the
+// DisallowUnknownFields option is provided, decoding fails if the JSON
+// includes object keys which do not match any exported field of the
+// destination struct.
+func JSONVar(p interface{}, name string, usage string, opts ...JSONOption) {
+	JSONVarFS(flag.CommandLine, p, name, usage, opts...)
+}
+
+// JSONVarFS is like JSONVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func JSONVarFS(fs *flag.FlagSet, p interface{}, name string, usage string, opts ...JSONOption) {
+	var o jsonOptions
+	for _, opt := range opts {
+		opt.applyJSONOption(&o)
+	}
+	v := NewJSONValue(p)
+	v.opts = o.decode
+	o.flag.define(fs, v, name, usage)
+}
+
+// JSONOption configures the behavior of flags defined with JSONVar. The
+// DisallowUnknownFields option and the options returned by Required, Env and
+// Hidden can be used.
+type JSONOption interface {
+	applyJSONOption(*jsonOptions)
+}
+
+// jsonOptions holds the configuration of a JSON flag.
+type jsonOptions struct {
+	decode decodeOptions
+	flag   flagOptions
+}
+
+// NewJSONValue returns a JSONValue decoding values into p, which must be a
+// non-nil pointer. The DisallowUnknownFields option makes unknown keys in
+// JSON objects be reported as errors.
+func NewJSONValue(p interface{}, opts ...DecodeOption) *JSONValue {
+	if v := reflect.ValueOf(p); v.Kind() != reflect.Ptr || v.IsNil() {
+		panic(fmt.Sprintf("flagutils: JSON flag destination must be a non-nil pointer, got %T", p))
+	}
+	v := &JSONValue{
+		p: p,
+	}
+	for _, opt := range opts {
+		opt(&v.opts)
+	}
+	return v
+}
+
+// JSONValue holds an arbitrary value that can be provided via the command
+// line as a JSON encoded string.
+type JSONValue struct {
+	p    interface{}
+	opts decodeOptions
+}
+
+// String implements flag.Value by returning the JSON encoded value.
+func (v *JSONValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+	b, err := json.Marshal(v.p)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Set implements flag.Value by unmarshaling the JSON encoded value into the
+// destination. The destination is only modified if decoding succeeds.
+func (v *JSONValue) Set(value string) error {
+	dst := reflect.ValueOf(v.p).Elem()
+	tmp := reflect.New(dst.Type())
+	tmp.Elem().Set(deepCopy(dst))
+	dec := json.NewDecoder(bytes.NewReader([]byte(value)))
+	if v.opts.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(tmp.Interface()); err != nil {
+		return fmt.Errorf("cannot unmarshal JSON: %v", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("cannot unmarshal JSON: unexpected data after top-level value")
+	}
+	dst.Set(tmp.Elem())
+	return nil
+}
+
+// Type implements pflag.Value by returning "json".
+func (v *JSONValue) Type() string {
+	return "json"
+}
+
+// deepCopy returns a copy of the given value in which the maps, slices and
+// pointers reachable through exported struct fields are copied rather than
+// shared, so that decoding JSON into the copy never modifies the original.
+// Unexported fields, which are ignored when decoding JSON, are copied as is.
+func deepCopy(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			p := reflect.New(v.Type().Elem())
+			p.Elem().Set(deepCopy(v.Elem()))
+			c.Set(p)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			c.Set(deepCopy(v.Elem()))
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			m := reflect.MakeMapWithSize(v.Type(), v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				m.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+			c.Set(m)
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				s.Index(i).Set(deepCopy(v.Index(i)))
+			}
+			c.Set(s)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+	case reflect.Struct:
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+	default:
+		c.Set(v)
+	}
+	return c
+}
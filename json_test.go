@@ -0,0 +1,177 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.JSONValue)(nil)
+
+type jsonConfig struct {
+	URL     string          `json:"url"`
+	Retries int             `json:"retries"`
+	Flags   map[string]bool `json:"flags,omitempty"`
+}
+
+var jsonTests = []struct {
+	about               string
+	name                string
+	value               string
+	strict              bool
+	defaultValue        jsonConfig
+	expectedValue       jsonConfig
+	expectedStringValue string
+	expectedError       string
+}{{
+	about: "all fields",
+	name:  "all",
+	value: `{"url": "https://1.2.3.4", "retries": 3, "flags": {"gisf": true}}`,
+	expectedValue: jsonConfig{
+		URL:     "https://1.2.3.4",
+		Retries: 3,
+		Flags:   map[string]bool{"gisf": true},
+	},
+	expectedStringValue: `{"url":"https://1.2.3.4","retries":3,"flags":{"gisf":true}}`,
+}, {
+	about: "some fields",
+	name:  "some",
+	value: `{"retries": 3}`,
+	defaultValue: jsonConfig{
+		URL:     "https://4.3.2.1",
+		Retries: 1,
+	},
+	expectedValue: jsonConfig{
+		URL:     "https://4.3.2.1",
+		Retries: 3,
+	},
+	expectedStringValue: `{"url":"https://4.3.2.1","retries":3}`,
+}, {
+	about: "unknown fields",
+	name:  "unknown",
+	value: `{"retries": 3, "retires": 4}`,
+	expectedValue: jsonConfig{
+		Retries: 3,
+	},
+	expectedStringValue: `{"url":"","retries":3}`,
+}, {
+	about:  "strict",
+	name:   "strict",
+	value:  `{"retries": 3}`,
+	strict: true,
+	expectedValue: jsonConfig{
+		Retries: 3,
+	},
+	expectedStringValue: `{"url":"","retries":3}`,
+}, {
+	about:               "error: strict with unknown fields",
+	name:                "err",
+	value:               `{"retries": 3, "retires": 4}`,
+	strict:              true,
+	expectedStringValue: `{"url":"","retries":0}`,
+	expectedError:       `cannot unmarshal JSON: json: unknown field "retires"`,
+}, {
+	about: "error: invalid type",
+	name:  "err",
+	value: `{"url": "https://1.2.3.4", "retries": "three"}`,
+	defaultValue: jsonConfig{
+		Retries: 1,
+	},
+	expectedValue: jsonConfig{
+		Retries: 1,
+	},
+	expectedStringValue: `{"url":"","retries":1}`,
+	expectedError:       `cannot unmarshal JSON: json: cannot unmarshal string into Go struct field .* of type int`,
+}, {
+	about: "error: partially decoded map",
+	name:  "err",
+	value: `{"flags": {"b": true, "c": "yes"}}`,
+	defaultValue: jsonConfig{
+		Flags: map[string]bool{"a": true},
+	},
+	expectedValue: jsonConfig{
+		Flags: map[string]bool{"a": true},
+	},
+	expectedStringValue: `{"url":"","retries":0,"flags":{"a":true}}`,
+	expectedError:       `cannot unmarshal JSON: json: cannot unmarshal string into Go struct field .* of type bool`,
+}, {
+	about:               "error: invalid JSON",
+	name:                "err",
+	value:               "!",
+	expectedStringValue: `{"url":"","retries":0}`,
+	expectedError:       "cannot unmarshal JSON: invalid character .*",
+}, {
+	about:               "error: trailing data",
+	name:                "err",
+	value:               `{"retries": 3} {}`,
+	expectedStringValue: `{"url":"","retries":0}`,
+	expectedError:       "cannot unmarshal JSON: unexpected data after top-level value",
+}, {
+	about:               "error: trailing closing bracket",
+	name:                "err",
+	value:               `{"retries": 3}]`,
+	expectedStringValue: `{"url":"","retries":0}`,
+	expectedError:       "cannot unmarshal JSON: unexpected data after top-level value",
+}}
+
+func TestJSONVar(t *testing.T) {
+	for _, test := range jsonTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := test.defaultValue
+			var opts []flagutils.JSONOption
+			if test.strict {
+				opts = append(opts, flagutils.DisallowUnknownFields())
+			}
+			flagutils.JSONVar(&v, test.name, "JSON usage", opts...)
+			err := flag.Set(test.name, test.value)
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestJSONValueString(t *testing.T) {
+	for _, test := range jsonTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := test.defaultValue
+			var opts []flagutils.DecodeOption
+			if test.strict {
+				opts = append(opts, flagutils.DisallowUnknownFields())
+			}
+			j := flagutils.NewJSONValue(&v, opts...)
+			j.Set(test.value)
+			c.Assert(j.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestNewJSONValueNotAPointer(t *testing.T) {
+	c := qt.New(t)
+	var v jsonConfig
+	c.Assert(func() {
+		flagutils.NewJSONValue(v)
+	}, qt.PanicMatches, `flagutils: JSON flag destination must be a non-nil pointer, got flagutils_test.jsonConfig`)
+}
+
+func TestJSONWithOptions(t *testing.T) {
+	t.Setenv("FLAGUTILS_CONF", `{"retries": 3, "timeout": 10}`)
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	var conf jsonConfig
+	fs.JSONVar(&conf, "conf", "the configuration", flagutils.DisallowUnknownFields(), flagutils.Env("FLAGUTILS_CONF"))
+	c.Assert(fs.Lookup("conf").Usage, qt.Equals, "the configuration (env FLAGUTILS_CONF)")
+	err := fs.Parse(nil)
+	c.Assert(err, qt.ErrorMatches, `invalid value for flag -conf from environment variable FLAGUTILS_CONF: cannot unmarshal JSON: json: unknown field "timeout"`)
+	c.Assert(conf, qt.DeepEquals, jsonConfig{})
+}
@@ -0,0 +1,77 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// KeyPair defines a TLS certificate flag with specified name and usage
+// string. The value is provided via the command line as the comma separated
+// paths of a PEM encoded certificate and its private key, as in
+// "cert.pem,key.pem", and the certificate is loaded when the flag is set.
+// The return value is the address of a tls.Certificate variable that stores
+// the value of the flag.
+func KeyPair(name string, usage string, opts ...Option) *tls.Certificate {
+	var cert tls.Certificate
+	KeyPairVar(&cert, name, usage, opts...)
+	return &cert
+}
+
+// KeyPairVar defines a TLS certificate flag with specified name and usage
+// string. See KeyPair for details on the accepted value. The argument p
+// points to a tls.Certificate variable in which to store the value of the
+// flag.
+func KeyPairVar(p *tls.Certificate, name string, usage string, opts ...Option) {
+	KeyPairVarFS(flag.CommandLine, p, name, usage, opts...)
+}
+
+// KeyPairVarFS is like KeyPairVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func KeyPairVarFS(fs *flag.FlagSet, p *tls.Certificate, name string, usage string, opts ...Option) {
+	defineVar(fs, NewKeyPairValue(p), name, usage, opts)
+}
+
+// NewKeyPairValue returns a KeyPairValue storing the loaded certificate in p.
+func NewKeyPairValue(p *tls.Certificate) *KeyPairValue {
+	return &KeyPairValue{
+		p: p,
+	}
+}
+
+// KeyPairValue holds a TLS certificate loaded from the certificate and key
+// files provided via the command line.
+type KeyPairValue struct {
+	p     *tls.Certificate
+	paths []string
+}
+
+// String implements flag.Value by returning the comma separated certificate
+// and key paths.
+func (k *KeyPairValue) String() string {
+	return strings.Join(k.paths, ",")
+}
+
+// Set implements flag.Value by loading the certificate and key from the
+// given comma separated paths.
+func (k *KeyPairValue) Set(value string) error {
+	paths, err := splitList(value)
+	if err != nil || len(paths) != 2 {
+		return fmt.Errorf("invalid key pair %q: expected comma separated certificate and key paths", value)
+	}
+	cert, err := tls.LoadX509KeyPair(paths[0], paths[1])
+	if err != nil {
+		return fmt.Errorf("cannot load key pair %q: %v", value, err)
+	}
+	*k.p = cert
+	k.paths = paths
+	return nil
+}
+
+// Type implements pflag.Value by returning "keyPair".
+func (k *KeyPairValue) Type() string {
+	return "keyPair"
+}
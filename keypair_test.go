@@ -0,0 +1,140 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.KeyPairValue)(nil)
+
+var keyPairTests = []struct {
+	about         string
+	name          string
+	value         string
+	expectedError string
+}{{
+	about: "certificate and key",
+	name:  "pair",
+	value: "cert.pem,key.pem",
+}, {
+	about: "spaces are trimmed",
+	name:  "spaces",
+	value: " cert.pem , key.pem ",
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid key pair "": expected comma separated certificate and key paths`,
+}, {
+	about:         "error: missing key",
+	name:          "err",
+	value:         "cert.pem",
+	expectedError: `invalid key pair ".*cert.pem": expected comma separated certificate and key paths`,
+}, {
+	about:         "error: too many paths",
+	name:          "err",
+	value:         "cert.pem,key.pem,ca.pem",
+	expectedError: `invalid key pair ".*ca.pem": expected comma separated certificate and key paths`,
+}, {
+	about:         "error: no such file",
+	name:          "err",
+	value:         "cert.pem,no-such.pem",
+	expectedError: `cannot load key pair ".*": open .*no-such.pem: no such file or directory`,
+}, {
+	about:         "error: swapped files",
+	name:          "err",
+	value:         "key.pem,cert.pem",
+	expectedError: `cannot load key pair ".*": tls: .*`,
+}}
+
+func TestKeyPair(t *testing.T) {
+	for _, test := range keyPairTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			dir := makeKeyPair(c)
+			v := flagutils.KeyPair(test.name, "key pair usage")
+			value := prefixPaths(dir, test.value)
+			err := flag.Set(test.name, value)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				c.Assert(*v, qt.DeepEquals, tls.Certificate{})
+				c.Assert(flag.Lookup(test.name).Value.String(), qt.Equals, "")
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.Certificate, qt.HasLen, 1)
+			c.Assert(v.PrivateKey, qt.Not(qt.IsNil))
+			c.Assert(flag.Lookup(test.name).Value.String(), qt.Equals,
+				filepath.Join(dir, "cert.pem")+","+filepath.Join(dir, "key.pem"))
+		})
+	}
+}
+
+func TestKeyPairVar(t *testing.T) {
+	for _, test := range keyPairTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			dir := makeKeyPair(c)
+			var v tls.Certificate
+			flagutils.KeyPairVar(&v, test.name, "key pair usage")
+			err := flag.Set(test.name, prefixPaths(dir, test.value))
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				c.Assert(v, qt.DeepEquals, tls.Certificate{})
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.Certificate, qt.HasLen, 1)
+		})
+	}
+}
+
+// prefixPaths joins the given directory to each comma separated path in
+// value.
+func prefixPaths(dir, value string) string {
+	if value == "" {
+		return ""
+	}
+	paths := strings.Split(value, ",")
+	for i, p := range paths {
+		paths[i] = filepath.Join(dir, strings.TrimSpace(p))
+	}
+	return strings.Join(paths, ",")
+}
+
+// makeKeyPair creates a temporary directory including a self signed
+// "cert.pem" certificate and its "key.pem" private key, and returns its
+// path.
+func makeKeyPair(c *qt.C) string {
+	dir := c.TB.(*testing.T).TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, qt.Equals, nil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, qt.Equals, nil)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, qt.Equals, nil)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	c.Assert(os.WriteFile(filepath.Join(dir, "cert.pem"), certPEM, 0600), qt.Equals, nil)
+	c.Assert(os.WriteFile(filepath.Join(dir, "key.pem"), keyPEM, 0600), qt.Equals, nil)
+	return dir
+}
@@ -0,0 +1,114 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// KeyValue holds a key and its associated value.
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// String returns the pair in the "key=value" form.
+func (kv KeyValue) String() string {
+	return kv.Key + "=" + kv.Value
+}
+
+// KeyValueSlice defines a key/value pairs slice flag with specified name,
+// default value, and usage string. The return value is the address of a
+// KeyValue slice variable that stores the value of the flag.
+func KeyValueSlice(name string, value []KeyValue, usage string, opts ...Option) *[]KeyValue {
+	var s []KeyValue
+	KeyValueSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// KeyValueSliceVar defines a key/value pairs slice flag with specified name,
+// default value, and usage string. The argument p points to a KeyValue slice
+// variable in which to store the value of the flag.
+func KeyValueSliceVar(p *[]KeyValue, name string, value []KeyValue, usage string, opts ...Option) {
+	KeyValueSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// KeyValueSliceVarFS is like KeyValueSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func KeyValueSliceVarFS(fs *flag.FlagSet, p *[]KeyValue, name string, value []KeyValue, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewKeyValueSliceValue(p), name, usage, opts)
+}
+
+// NewKeyValueSliceValue returns a KeyValueSliceValue storing its value in p.
+func NewKeyValueSliceValue(p *[]KeyValue) *KeyValueSliceValue {
+	return &KeyValueSliceValue{
+		p: p,
+	}
+}
+
+// KeyValueSliceValue holds an ordered slice of key/value pairs that can be
+// provided via the command line as a comma separated list of "key=value"
+// items. The flag can be repeated, in which case pairs are accumulated in the
+// order they are provided. The first occurrence replaces the default value.
+type KeyValueSliceValue struct {
+	p   *[]KeyValue
+	set bool
+}
+
+// String implements flag.Value by returning the pairs as a string.
+func (s *KeyValueSliceValue) String() string {
+	if s.p == nil {
+		return ""
+	}
+	values := make([]string, len(*s.p))
+	for i, kv := range *s.p {
+		values[i] = kv.String()
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value by appending the pairs in the given comma
+// separated value.
+func (s *KeyValueSliceValue) Set(value string) error {
+	if !s.set {
+		*s.p = nil
+		s.set = true
+	}
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	kvs := make([]KeyValue, len(values))
+	for i, v := range values {
+		if kvs[i], err = parseKeyValue(v); err != nil {
+			return err
+		}
+	}
+	*s.p = append(*s.p, kvs...)
+	return nil
+}
+
+// Type implements pflag.Value by returning "keyValueSlice".
+func (s *KeyValueSliceValue) Type() string {
+	return "keyValueSlice"
+}
+
+// parseKeyValue parses the given "key=value" pair. Spaces around the key are
+// removed, and the value can be empty.
+func parseKeyValue(value string) (KeyValue, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return KeyValue{}, fmt.Errorf("invalid key=value pair %q: missing \"=\"", value)
+	}
+	key := strings.TrimSpace(parts[0])
+	if key == "" {
+		return KeyValue{}, fmt.Errorf("invalid key=value pair %q: empty key", value)
+	}
+	return KeyValue{
+		Key:   key,
+		Value: strings.TrimSpace(parts[1]),
+	}, nil
+}
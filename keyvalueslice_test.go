@@ -0,0 +1,135 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.KeyValueSliceValue)(nil)
+
+var keyValueSliceTests = []struct {
+	about               string
+	name                string
+	values              []string
+	defaultValue        []flagutils.KeyValue
+	expectedValue       []flagutils.KeyValue
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single pair",
+	name:                "single",
+	values:              []string{"auth=jwt"},
+	expectedValue:       []flagutils.KeyValue{{Key: "auth", Value: "jwt"}},
+	expectedStringValue: "auth=jwt",
+}, {
+	about:  "comma separated pairs",
+	name:   "multiple",
+	values: []string{"log=debug, auth=jwt ,gzip=,log=info"},
+	expectedValue: []flagutils.KeyValue{
+		{Key: "log", Value: "debug"},
+		{Key: "auth", Value: "jwt"},
+		{Key: "gzip", Value: ""},
+		{Key: "log", Value: "info"},
+	},
+	expectedStringValue: "log=debug,auth=jwt,gzip=,log=info",
+}, {
+	about:  "repeated flags",
+	name:   "repeated",
+	values: []string{"log=debug", "auth=jwt,gzip=9", "url=http://1.2.3.4/?a=b"},
+	expectedValue: []flagutils.KeyValue{
+		{Key: "log", Value: "debug"},
+		{Key: "auth", Value: "jwt"},
+		{Key: "gzip", Value: "9"},
+		{Key: "url", Value: "http://1.2.3.4/?a=b"},
+	},
+	expectedStringValue: "log=debug,auth=jwt,gzip=9,url=http://1.2.3.4/?a=b",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	values:        []string{"log=debug", "auth=jwt"},
+	defaultValue:  []flagutils.KeyValue{{Key: "log", Value: "info"}},
+	expectedValue: []flagutils.KeyValue{{Key: "log", Value: "debug"}, {Key: "auth", Value: "jwt"}},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []flagutils.KeyValue{{Key: "log", Value: "info"}},
+	expectedValue: []flagutils.KeyValue{{Key: "log", Value: "info"}},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	values:        []string{""},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: missing equal sign",
+	name:          "err",
+	values:        []string{"log=debug,auth"},
+	expectedError: `invalid key=value pair "auth": missing "="`,
+}, {
+	about:         "error: empty key",
+	name:          "err",
+	values:        []string{" =debug"},
+	expectedError: `invalid key=value pair "=debug": empty key`,
+}}
+
+func TestKeyValueSlice(t *testing.T) {
+	for _, test := range keyValueSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.KeyValueSlice(test.name, test.defaultValue, "key value slice usage")
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set(test.name, value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+				c.Assert(*v, qt.DeepEquals, test.expectedValue)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestKeyValueSliceVar(t *testing.T) {
+	for _, test := range keyValueSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []flagutils.KeyValue
+			flagutils.KeyValueSliceVar(&v, test.name, test.defaultValue, "key value slice usage")
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set(test.name, value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+				c.Assert(v, qt.DeepEquals, test.expectedValue)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestKeyValueSliceValueString(t *testing.T) {
+	for _, test := range keyValueSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v []flagutils.KeyValue
+			s := flagutils.NewKeyValueSliceValue(&v)
+			for _, value := range test.values {
+				s.Set(value)
+			}
+			c.Assert(s.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
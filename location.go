@@ -0,0 +1,73 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// Location defines a time zone flag with specified name, default value, and
+// usage string. The value is provided via the command line as an IANA time
+// zone name, as in "Europe/Rome", or as "UTC" or "Local". The return value
+// is the address of a *time.Location variable that stores the value of the
+// flag.
+func Location(name string, value *time.Location, usage string, opts ...Option) **time.Location {
+	var l *time.Location
+	LocationVar(&l, name, value, usage, opts...)
+	return &l
+}
+
+// LocationVar defines a time zone flag with specified name, default value,
+// and usage string. The argument p points to a *time.Location variable in
+// which to store the value of the flag.
+func LocationVar(p **time.Location, name string, value *time.Location, usage string, opts ...Option) {
+	LocationVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// LocationVarFS is like LocationVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func LocationVarFS(fs *flag.FlagSet, p **time.Location, name string, value *time.Location, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewLocationValue(p), name, usage, opts)
+}
+
+// NewLocationValue returns a LocationValue storing its value in p.
+func NewLocationValue(p **time.Location) *LocationValue {
+	return &LocationValue{
+		p: p,
+	}
+}
+
+// LocationValue holds a time zone that can be provided via the command line
+// and is resolved using time.LoadLocation.
+type LocationValue struct {
+	p **time.Location
+}
+
+// String implements flag.Value by returning the time zone name.
+func (l *LocationValue) String() string {
+	if l.p == nil || *l.p == nil {
+		return ""
+	}
+	return (*l.p).String()
+}
+
+// Set implements flag.Value by loading the given time zone.
+func (l *LocationValue) Set(value string) error {
+	if value == "" {
+		return fmt.Errorf("invalid time zone %q", value)
+	}
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return fmt.Errorf("invalid time zone %q", value)
+	}
+	*l.p = loc
+	return nil
+}
+
+// Type implements pflag.Value by returning "location".
+func (l *LocationValue) Type() string {
+	return "location"
+}
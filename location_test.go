@@ -0,0 +1,105 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.LocationValue)(nil)
+
+var locationTests = []struct {
+	about         string
+	name          string
+	value         string
+	defaultValue  *time.Location
+	expectedValue string
+	expectedError string
+}{{
+	about:         "IANA name",
+	name:          "iana",
+	value:         "Europe/Rome",
+	expectedValue: "Europe/Rome",
+}, {
+	about:         "UTC",
+	name:          "utc",
+	value:         "UTC",
+	expectedValue: "UTC",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "America/New_York",
+	defaultValue:  time.UTC,
+	expectedValue: "America/New_York",
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  time.UTC,
+	expectedValue: "UTC",
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid time zone ""`,
+}, {
+	about:         "error: unknown time zone",
+	name:          "err",
+	value:         "Europe/Gallifrey",
+	expectedError: `invalid time zone "Europe/Gallifrey"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "CEST+1",
+	defaultValue:  time.UTC,
+	expectedValue: "UTC",
+	expectedError: `invalid time zone "CEST\+1"`,
+}}
+
+func TestLocation(t *testing.T) {
+	for _, test := range locationTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Location(test.name, test.defaultValue, "location usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(flag.Lookup(test.name).Value.String(), qt.Equals, test.expectedValue)
+			if test.expectedValue == "" {
+				c.Assert(*v, qt.IsNil)
+				return
+			}
+			c.Assert((*v).String(), qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestLocationVar(t *testing.T) {
+	for _, test := range locationTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v *time.Location
+			flagutils.LocationVar(&v, test.name, test.defaultValue, "location usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			if test.expectedValue == "" {
+				c.Assert(v, qt.IsNil)
+				return
+			}
+			c.Assert(v.String(), qt.Equals, test.expectedValue)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// LogLevel defines a log level flag with specified name, default value, and
+// usage string. The value can be one of debug, info, warn (or warning) and
+// error, case insensitively and optionally followed by an offset as in
+// "info+2", or a number. The return value is the address of a slog.Level
+// variable that stores the value of the flag.
+func LogLevel(name string, value slog.Level, usage string, opts ...Option) *slog.Level {
+	var l slog.Level
+	LogLevelVar(&l, name, value, usage, opts...)
+	return &l
+}
+
+// LogLevelVar defines a log level flag with specified name, default value,
+// and usage string. See LogLevel for details on the accepted values. The
+// argument p points to a slog.Level variable in which to store the value of
+// the flag.
+func LogLevelVar(p *slog.Level, name string, value slog.Level, usage string, opts ...Option) {
+	LogLevelVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// LogLevelVarFS is like LogLevelVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func LogLevelVarFS(fs *flag.FlagSet, p *slog.Level, name string, value slog.Level, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*LogLevelValue)(p), name, usage, opts)
+}
+
+// LogLevelValue holds a log level that can be provided via the command line
+// either by name or as a number.
+type LogLevelValue slog.Level
+
+// String implements flag.Value by returning the level name, as in "INFO" or
+// "WARN+1".
+func (l *LogLevelValue) String() string {
+	return slog.Level(*l).String()
+}
+
+// Set implements flag.Value by parsing the given level name or number.
+func (l *LogLevelValue) Set(value string) error {
+	if n, err := strconv.Atoi(value); err == nil {
+		*l = LogLevelValue(n)
+		return nil
+	}
+	name := strings.ToLower(value)
+	if rest := strings.TrimPrefix(name, "warning"); rest != name {
+		name = "warn" + rest
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("invalid log level %q: allowed values are debug, info, warn, error or a number", value)
+	}
+	*l = LogLevelValue(level)
+	return nil
+}
+
+// Type implements pflag.Value by returning "logLevel".
+func (l *LogLevelValue) Type() string {
+	return "logLevel"
+}
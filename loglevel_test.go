@@ -0,0 +1,137 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"log/slog"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.LogLevelValue)(nil)
+
+var logLevelTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        slog.Level
+	expectedValue       slog.Level
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "debug",
+	name:                "debug",
+	value:               "debug",
+	expectedValue:       slog.LevelDebug,
+	expectedStringValue: "DEBUG",
+}, {
+	about:               "uppercase",
+	name:                "upper",
+	value:               "ERROR",
+	expectedValue:       slog.LevelError,
+	expectedStringValue: "ERROR",
+}, {
+	about:               "warn",
+	name:                "warn",
+	value:               "Warn",
+	expectedValue:       slog.LevelWarn,
+	expectedStringValue: "WARN",
+}, {
+	about:               "warning",
+	name:                "warning",
+	value:               "warning",
+	expectedValue:       slog.LevelWarn,
+	expectedStringValue: "WARN",
+}, {
+	about:               "offset",
+	name:                "offset",
+	value:               "info+2",
+	expectedValue:       slog.LevelInfo + 2,
+	expectedStringValue: "INFO+2",
+}, {
+	about:               "number",
+	name:                "number",
+	value:               "-4",
+	expectedValue:       slog.LevelDebug,
+	expectedStringValue: "DEBUG",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "debug",
+	defaultValue:  slog.LevelWarn,
+	expectedValue: slog.LevelDebug,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  slog.LevelWarn,
+	expectedValue: slog.LevelWarn,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid log level "": allowed values are debug, info, warn, error or a number`,
+}, {
+	about:         "error: unknown level",
+	name:          "err",
+	value:         "verbose",
+	expectedError: `invalid log level "verbose": allowed values are debug, info, warn, error or a number`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "fatal",
+	defaultValue:  slog.LevelWarn,
+	expectedValue: slog.LevelWarn,
+	expectedError: `invalid log level "fatal": allowed values are debug, info, warn, error or a number`,
+}}
+
+func TestLogLevel(t *testing.T) {
+	for _, test := range logLevelTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.LogLevel(test.name, test.defaultValue, "log level usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestLogLevelVar(t *testing.T) {
+	for _, test := range logLevelTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v slog.Level
+			flagutils.LogLevelVar(&v, test.name, test.defaultValue, "log level usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestLogLevelValueString(t *testing.T) {
+	for _, test := range logLevelTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != 0 || test.expectedError != "" {
+				return
+			}
+			var v flagutils.LogLevelValue
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
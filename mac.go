@@ -0,0 +1,57 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net"
+)
+
+// MAC defines a hardware address flag with specified name, default value,
+// and usage string. The return value is the address of a hardware address
+// variable that stores the value of the flag.
+func MAC(name string, value net.HardwareAddr, usage string, opts ...Option) *net.HardwareAddr {
+	var a net.HardwareAddr
+	MACVar(&a, name, value, usage, opts...)
+	return &a
+}
+
+// MACVar defines a hardware address flag with specified name, default value,
+// and usage string. The argument p points to a hardware address variable in
+// which to store the value of the flag.
+func MACVar(p *net.HardwareAddr, name string, value net.HardwareAddr, usage string, opts ...Option) {
+	MACVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// MACVarFS is like MACVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func MACVarFS(fs *flag.FlagSet, p *net.HardwareAddr, name string, value net.HardwareAddr, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*MACValue)(p), name, usage, opts)
+}
+
+// MACValue holds a hardware address that can be provided via the command
+// line in any of the formats accepted by net.ParseMAC, for instance
+// "00:00:5e:00:53:01" or "0000.5e00.5301".
+type MACValue net.HardwareAddr
+
+// String implements flag.Value by returning the hardware address as a string.
+func (a *MACValue) String() string {
+	return net.HardwareAddr(*a).String()
+}
+
+// Set implements flag.Value by parsing the given hardware address.
+func (a *MACValue) Set(value string) error {
+	addr, err := net.ParseMAC(value)
+	if err != nil {
+		return fmt.Errorf("invalid MAC address %q", value)
+	}
+	*a = MACValue(addr)
+	return nil
+}
+
+// Type implements pflag.Value by returning "mac".
+func (a *MACValue) Type() string {
+	return "mac"
+}
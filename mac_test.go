@@ -0,0 +1,126 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"net"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.MACValue)(nil)
+
+var macTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        net.HardwareAddr
+	expectedValue       net.HardwareAddr
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "colon separated",
+	name:                "colon",
+	value:               "00:00:5e:00:53:01",
+	expectedValue:       mustParseMAC("00:00:5e:00:53:01"),
+	expectedStringValue: "00:00:5e:00:53:01",
+}, {
+	about:               "hyphen separated",
+	name:                "hyphen",
+	value:               "00-00-5E-00-53-01",
+	expectedValue:       mustParseMAC("00:00:5e:00:53:01"),
+	expectedStringValue: "00:00:5e:00:53:01",
+}, {
+	about:               "dot separated",
+	name:                "dot",
+	value:               "0000.5e00.5301",
+	expectedValue:       mustParseMAC("00:00:5e:00:53:01"),
+	expectedStringValue: "00:00:5e:00:53:01",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "00:00:5e:00:53:02",
+	defaultValue:  mustParseMAC("00:00:5e:00:53:01"),
+	expectedValue: mustParseMAC("00:00:5e:00:53:02"),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  mustParseMAC("00:00:5e:00:53:01"),
+	expectedValue: mustParseMAC("00:00:5e:00:53:01"),
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid MAC address ""`,
+}, {
+	about:         "error: invalid address",
+	name:          "err",
+	value:         "00:00:5e:00:53",
+	expectedError: `invalid MAC address "00:00:5e:00:53"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "bad-wolf",
+	defaultValue:  mustParseMAC("00:00:5e:00:53:01"),
+	expectedValue: mustParseMAC("00:00:5e:00:53:01"),
+	expectedError: `invalid MAC address "bad-wolf"`,
+}}
+
+func TestMAC(t *testing.T) {
+	for _, test := range macTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.MAC(test.name, test.defaultValue, "MAC usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestMACVar(t *testing.T) {
+	for _, test := range macTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v net.HardwareAddr
+			flagutils.MACVar(&v, test.name, test.defaultValue, "MAC usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestMACValueString(t *testing.T) {
+	for _, test := range macTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.MACValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func mustParseMAC(s string) net.HardwareAddr {
+	a, err := net.ParseMAC(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
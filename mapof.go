@@ -0,0 +1,100 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MapOf defines a map flag with specified name, key and value parsing
+// functions, default value and usage string. Each "key=value" pair provided
+// via the command line is converted using the given parse functions. The
+// return value is the address of a map variable that stores the value of the
+// flag.
+func MapOf[K comparable, V any](name string, parseKey func(string) (K, error), parseValue func(string) (V, error), value map[K]V, usage string, opts ...Option) *map[K]V {
+	var m map[K]V
+	MapOfVar(&m, name, parseKey, parseValue, value, usage, opts...)
+	return &m
+}
+
+// MapOfVar defines a map flag with specified name, key and value parsing
+// functions, default value and usage string. The argument p points to a map
+// variable in which to store the value of the flag.
+func MapOfVar[K comparable, V any](p *map[K]V, name string, parseKey func(string) (K, error), parseValue func(string) (V, error), value map[K]V, usage string, opts ...Option) {
+	MapOfVarFS(flag.CommandLine, p, name, parseKey, parseValue, value, usage, opts...)
+}
+
+// MapOfVarFS is like MapOfVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func MapOfVarFS[K comparable, V any](fs *flag.FlagSet, p *map[K]V, name string, parseKey func(string) (K, error), parseValue func(string) (V, error), value map[K]V, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewMapOfValue(p, parseKey, parseValue), name, usage, opts)
+}
+
+// NewMapOfValue returns a MapOfValue storing its value in p and using the
+// given functions to parse keys and values.
+func NewMapOfValue[K comparable, V any](p *map[K]V, parseKey func(string) (K, error), parseValue func(string) (V, error)) *MapOfValue[K, V] {
+	return &MapOfValue[K, V]{
+		p:          p,
+		parseKey:   parseKey,
+		parseValue: parseValue,
+	}
+}
+
+// MapOfValue holds a map of arbitrary keys and values that can be provided
+// via the command line as a comma separated list of "key=value" pairs.
+type MapOfValue[K comparable, V any] struct {
+	p          *map[K]V
+	parseKey   func(string) (K, error)
+	parseValue func(string) (V, error)
+}
+
+// String implements flag.Value by returning the map as a string. Keys and
+// values are formatted using their default format, and pairs are sorted.
+func (m *MapOfValue[K, V]) String() string {
+	if m.p == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(*m.p))
+	for k, v := range *m.p {
+		pairs = append(pairs, fmt.Sprint(k)+"="+fmt.Sprint(v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value by populating the map from the given comma
+// separated value.
+func (m *MapOfValue[K, V]) Set(value string) error {
+	*m.p = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	result := make(map[K]V, len(values))
+	for _, v := range values {
+		kv, err := parseKeyValue(v)
+		if err != nil {
+			return err
+		}
+		key, err := m.parseKey(kv.Key)
+		if err != nil {
+			return fmt.Errorf("invalid key %q: %v", kv.Key, err)
+		}
+		if result[key], err = m.parseValue(kv.Value); err != nil {
+			return fmt.Errorf("invalid value %q for key %q: %v", kv.Value, kv.Key, err)
+		}
+	}
+	*m.p = result
+	return nil
+}
+
+// Type implements pflag.Value by returning the map type, for instance
+// "map[string]int".
+func (m *MapOfValue[K, V]) Type() string {
+	return reflect.TypeOf((*map[K]V)(nil)).Elem().String()
+}
@@ -0,0 +1,116 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"strconv"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.MapOfValue[int, time.Duration])(nil)
+
+var mapOfTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        map[int]time.Duration
+	expectedValue       map[int]time.Duration
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single pair",
+	name:                "single",
+	value:               "1=1s",
+	expectedValue:       map[int]time.Duration{1: time.Second},
+	expectedStringValue: "1=1s",
+}, {
+	about: "multiple pairs",
+	name:  "multiple",
+	value: "3=1h, 1 = 1s ,2=1m30s",
+	expectedValue: map[int]time.Duration{
+		1: time.Second,
+		2: 90 * time.Second,
+		3: time.Hour,
+	},
+	expectedStringValue: "1=1s,2=1m30s,3=1h0m0s",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "1=1s",
+	defaultValue:  map[int]time.Duration{42: time.Minute},
+	expectedValue: map[int]time.Duration{1: time.Second},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  map[int]time.Duration{42: time.Minute},
+	expectedValue: map[int]time.Duration{42: time.Minute},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: invalid key",
+	name:          "err",
+	value:         "1=1s,two=2s",
+	expectedError: `invalid key "two": strconv.Atoi: parsing "two": invalid syntax`,
+}, {
+	about:         "error: invalid value",
+	name:          "err",
+	value:         "1=forever",
+	expectedError: `invalid value "forever" for key "1": time: invalid duration "forever"`,
+}}
+
+func TestMapOf(t *testing.T) {
+	for _, test := range mapOfTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.MapOf(test.name, strconv.Atoi, time.ParseDuration, test.defaultValue, "map of usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestMapOfVar(t *testing.T) {
+	for _, test := range mapOfTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v map[int]time.Duration
+			flagutils.MapOfVar(&v, test.name, strconv.Atoi, time.ParseDuration, test.defaultValue, "map of usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestMapOfValueString(t *testing.T) {
+	for _, test := range mapOfTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v map[int]time.Duration
+			s := flagutils.NewMapOfValue(&v, strconv.Atoi, time.ParseDuration)
+			s.Set(test.value)
+			c.Assert(s.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
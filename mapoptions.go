@@ -0,0 +1,527 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MapOption configures the behavior of flags defined with Map and MapVar. In
+// addition to the options defined in this file, the options returned by
+// Required, Env and Hidden can be used.
+type MapOption interface {
+	applyMapOption(*mapOptions)
+}
+
+// mapOption implements MapOption with a function.
+type mapOption func(*mapOptions)
+
+// applyMapOption implements MapOption.
+func (f mapOption) applyMapOption(o *mapOptions) {
+	f(o)
+}
+
+// mapOptions holds the configuration of a string map flag.
+type mapOptions struct {
+	merge           bool
+	accumulate      bool
+	useNumber       bool
+	noDuplicates    bool
+	expandEnv       bool
+	allowedKeys     []string
+	keyValidators   []func(string) error
+	valueValidators []func(string, interface{}) error
+	validate        func(StringMap) error
+	flag            flagOptions
+}
+
+// Merge returns an option making repeated occurrences of a string map flag
+// deep-merge into the current value of the flag, including its default,
+// rather than replacing it. Nested maps are merged recursively, while any
+// other value replaces the existing one. For instance, providing
+// `-conf '{"flags": {"profile": true}}' -conf '{"flags": {"status": true}}'`
+// results in `{"flags": {"profile": true, "status": true}}`.
+func Merge() MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.merge = true
+	})
+}
+
+// AccumulateEntries returns an option making repeated occurrences of a string
+// map flag add entries to the map rather than replacing it, as done by
+// docker or kubectl for repeated map flags. For instance,
+// "-conf key1=v1 -conf key2=v2" results in `{"key1": "v1", "key2": "v2"}`.
+// Entries are deep-merged as described in Merge, but, unlike Merge, the
+// first occurrence replaces the default value.
+func AccumulateEntries() MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.accumulate = true
+	})
+}
+
+// UseNumber returns an option making string map flags decode JSON numbers as
+// json.Number values rather than as float64, so that large integers, like
+// IDs, are preserved exactly.
+func UseNumber() MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.useNumber = true
+	})
+}
+
+// DisallowDuplicateKeys returns an option making string map flags reject
+// values including the same key more than once in a JSON object, or the
+// same path more than once in a list of "key=value" pairs. Without this
+// option, the last occurrence silently wins.
+func DisallowDuplicateKeys() MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.noDuplicates = true
+	})
+}
+
+// ExpandEnv returns an option making string map flags replace references to
+// environment variables in string values, at any nesting level, so that
+// secrets can be referenced rather than embedded in the command line. Only
+// the "${VAR}" syntax is recognized, and "$$" can be used to include a
+// literal dollar sign. For instance, with PASSWORD set to "secret",
+// `{"password": "${PASSWORD}", "price": "$$5"}` results in
+// `{"password": "secret", "price": "$5"}`. Keys are never expanded, and
+// referencing a variable which is not set is an error.
+func ExpandEnv() MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.expandEnv = true
+	})
+}
+
+// AllowKeys returns an option making string map flags only accept the given
+// top-level keys. Unknown keys are reported as flag parsing errors,
+// suggesting the closest allowed key when one is similar enough, so that
+// typos are easy to spot. Nested keys are not restricted. When the option is
+// provided multiple times, all the given keys are allowed.
+func AllowKeys(keys ...string) MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.allowedKeys = append(o.allowedKeys, keys...)
+	})
+}
+
+// ValidateKeys returns an option making string map flags validate the keys
+// of the provided value using the given function, at any nesting level, so
+// that unexpected keys are reported as flag parsing errors naming the
+// offending key path. When the option is provided multiple times, or with
+// MatchKeys, all validations are applied in order.
+func ValidateKeys(validate func(key string) error) MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.keyValidators = append(o.keyValidators, validate)
+	})
+}
+
+// MatchKeys returns an option making string map flags require the keys of
+// the provided value to match the given regular expression, at any nesting
+// level.
+func MatchKeys(re *regexp.Regexp) MapOption {
+	return ValidateKeys(func(key string) error {
+		if !re.MatchString(key) {
+			return fmt.Errorf("does not match %q", re)
+		}
+		return nil
+	})
+}
+
+// ValidateValues returns an option making string map flags validate each
+// entry of the provided value using the given function, which is called
+// with the dot separated path and the value of each entry not holding a
+// nested map. Errors are reported as *PathError values including the path.
+// When the option is provided multiple times, all validations are applied
+// in order.
+func ValidateValues(validate func(path string, value interface{}) error) MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.valueValidators = append(o.valueValidators, validate)
+	})
+}
+
+// Validate returns an option making string map flags validate their value
+// using the given function, so that invalid values are reported as flag
+// parsing errors. The function is called with the resulting value of the
+// flag, after merging if the Merge option is also provided. Validation
+// functions can return a *PathError to point to the offending value.
+func Validate(validate func(m StringMap) error) MapOption {
+	return mapOption(func(o *mapOptions) {
+		o.validate = validate
+	})
+}
+
+// PathError records an error about the value at a specific path in a string
+// map, where the path is a dot separated list of keys.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *PathError) Error() string {
+	return fmt.Sprintf("invalid value at %q: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// newMapValue returns a mapValue storing its value in p and configured with
+// the given options.
+func newMapValue(p *StringMap, opts []MapOption) *mapValue {
+	v := &mapValue{
+		p: p,
+	}
+	for _, opt := range opts {
+		opt.applyMapOption(&v.opts)
+	}
+	return v
+}
+
+// mapValue implements flag.Value for string maps configured with options.
+type mapValue struct {
+	p    *StringMap
+	opts mapOptions
+	set  bool
+}
+
+// String implements flag.Value by returning the map as a string.
+func (v *mapValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+	return v.p.String()
+}
+
+// Set implements flag.Value by setting the map from the given value
+// according to the configured options. The current value is left untouched
+// if an error occurs.
+func (v *mapValue) Set(value string) error {
+	m, err := parseMap(value, &v.opts)
+	if err != nil {
+		return err
+	}
+	if v.opts.expandEnv {
+		if err := expandEnvValues(m, ""); err != nil {
+			return err
+		}
+	}
+	if err := v.opts.validateEntries(m); err != nil {
+		return err
+	}
+	if v.opts.merge || v.opts.accumulate && v.set {
+		m = mergeMaps(*v.p, m)
+	}
+	if v.opts.validate != nil {
+		if err := v.opts.validate(m); err != nil {
+			return err
+		}
+	}
+	*v.p = m
+	v.set = true
+	return nil
+}
+
+// Type implements pflag.Value by returning "stringMap".
+func (v *mapValue) Type() string {
+	return "stringMap"
+}
+
+// unmarshal decodes the given JSON data into v according to the options.
+func (o *mapOptions) unmarshal(data []byte, v interface{}) error {
+	if !o.useNumber {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid data after top-level value")
+	}
+	return nil
+}
+
+// walkMap calls f for each entry of the given map and of its nested maps,
+// sorted by key, with the dot separated path of the entry. The prefix
+// argument is the path of the map.
+func walkMap(m map[string]interface{}, prefix string, f func(path, key string, value interface{}) error) error {
+	for _, key := range sortedKeys(m) {
+		path := joinPath(prefix, key)
+		if err := f(path, key, m[key]); err != nil {
+			return err
+		}
+		if nested, ok := m[key].(map[string]interface{}); ok {
+			if err := walkMap(nested, path, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the given map in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinPath returns the dot separated path of the given key in the map at the
+// given prefix path.
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// expandEnvValues expands references to environment variables in the string
+// values stored in the given map at the given path, including the values
+// stored in nested maps and arrays. The map is modified in place.
+func expandEnvValues(m map[string]interface{}, prefix string) error {
+	for _, key := range sortedKeys(m) {
+		expanded, err := expandEnvValue(joinPath(prefix, key), m[key])
+		if err != nil {
+			return err
+		}
+		m[key] = expanded
+	}
+	return nil
+}
+
+// expandEnvValue returns the given value, found at the given path, with
+// references to environment variables expanded.
+func expandEnvValue(path string, v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		s, err := expandEnvString(v)
+		if err != nil {
+			return nil, &PathError{
+				Path: path,
+				Err:  err,
+			}
+		}
+		return s, nil
+	case map[string]interface{}:
+		return v, expandEnvValues(v, path)
+	case []interface{}:
+		for i, elem := range v {
+			expanded, err := expandEnvValue(joinPath(path, strconv.Itoa(i)), elem)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+	}
+	return v, nil
+}
+
+// expandEnvString replaces "${VAR}" references in the given string with the
+// value of the corresponding environment variable, and "$$" with a single
+// dollar sign. Any other dollar sign is left unchanged.
+func expandEnvString(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i == -1 || i == len(s)-1 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		b.WriteString(s[:i])
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			s = s[i+2:]
+		case '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				return "", fmt.Errorf("unterminated variable reference in %q", s[i:])
+			}
+			name := s[i+2 : i+end]
+			if name == "" {
+				return "", errors.New("empty variable reference")
+			}
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				return "", fmt.Errorf("environment variable %q is not set", name)
+			}
+			b.WriteString(value)
+			s = s[i+end+1:]
+		default:
+			b.WriteByte('$')
+			s = s[i+1:]
+		}
+	}
+}
+
+// validateEntries validates the keys and the non-map values of the given
+// map and of its nested maps according to the options.
+func (o *mapOptions) validateEntries(m map[string]interface{}) error {
+	if o.allowedKeys != nil {
+		for _, key := range sortedKeys(m) {
+			if err := checkAllowedKey(key, o.allowedKeys); err != nil {
+				return err
+			}
+		}
+	}
+	if len(o.keyValidators) == 0 && len(o.valueValidators) == 0 {
+		return nil
+	}
+	return walkMap(m, "", func(path, key string, value interface{}) error {
+		for _, validate := range o.keyValidators {
+			if err := validate(key); err != nil {
+				return fmt.Errorf("invalid key %q: %v", path, err)
+			}
+		}
+		if _, ok := value.(map[string]interface{}); ok {
+			return nil
+		}
+		for _, validate := range o.valueValidators {
+			if err := validate(path, value); err != nil {
+				return &PathError{
+					Path: path,
+					Err:  err,
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// checkAllowedKey returns an error if the given key is not one of the
+// allowed keys, suggesting the closest allowed key if any.
+func checkAllowedKey(key string, allowed []string) error {
+	for _, a := range allowed {
+		if key == a {
+			return nil
+		}
+	}
+	if suggestion := suggest(key, allowed); suggestion != "" {
+		return fmt.Errorf("invalid key %q: did you mean %q?", key, suggestion)
+	}
+	return fmt.Errorf("invalid key %q: allowed keys are %s", key, formatChoices(allowed))
+}
+
+// suggest returns the candidate closest to the given value, or an empty
+// string if no candidate is close enough to be a likely typo. Candidates
+// differing only in case are always suggested.
+func suggest(value string, candidates []string) string {
+	best, bestDistance := "", (len(value)+2)/3+1
+	for _, c := range candidates {
+		if strings.EqualFold(value, c) {
+			return c
+		}
+		if d := editDistance(value, c); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b, counting
+// the minimum number of single character insertions, deletions and
+// substitutions required to change one into the other.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// checkDuplicateKeys returns an error naming the first key included more
+// than once in a JSON object at any nesting level in the given valid JSON
+// data. The key is reported as a dot separated path.
+func checkDuplicateKeys(data []byte) error {
+	return walkDuplicateKeys(json.NewDecoder(bytes.NewReader(data)), "")
+}
+
+// walkDuplicateKeys reads the next JSON value from dec, checking for
+// duplicate keys in objects. The path argument is the path of the value.
+func walkDuplicateKeys(dec *json.Decoder, path string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		seen := make(map[string]bool)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			if seen[key] {
+				return fmt.Errorf("duplicate key %q", keyPath)
+			}
+			seen[key] = true
+			if err := walkDuplicateKeys(dec, keyPath); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for dec.More() {
+			if err := walkDuplicateKeys(dec, path); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+	// Consume the closing delimiter.
+	_, err = dec.Token()
+	return err
+}
+
+// mergeMaps returns a new map resulting from deep-merging src into dst.
+// Neither dst nor src are modified.
+func mergeMaps(dst, src map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(dst)+len(src))
+	for k, v := range dst {
+		result[k] = v
+	}
+	for k, v := range src {
+		srcMap, srcOK := v.(map[string]interface{})
+		dstMap, dstOK := result[k].(map[string]interface{})
+		if srcOK && dstOK {
+			v = mergeMaps(dstMap, srcMap)
+		}
+		result[k] = v
+	}
+	return result
+}
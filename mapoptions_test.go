@@ -0,0 +1,540 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"regexp"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var mapOptionsTests = []struct {
+	about         string
+	values        []string
+	opts          []flagutils.MapOption
+	defaultValue  map[string]interface{}
+	expectedValue flagutils.StringMap
+	expectedError string
+}{{
+	about:  "no options: repeated flags replace the value",
+	values: []string{`{"gisf": true}`, `{"answer": 42}`},
+	expectedValue: flagutils.StringMap{
+		"answer": float64(42),
+	},
+}, {
+	about:  "merge: repeated flags",
+	values: []string{`{"gisf": true, "url": "https://1.2.3.4"}`, `"answer": 42, "url": "https://4.3.2.1"`},
+	opts:   []flagutils.MapOption{flagutils.Merge()},
+	expectedValue: flagutils.StringMap{
+		"gisf":   true,
+		"answer": float64(42),
+		"url":    "https://4.3.2.1",
+	},
+}, {
+	about: "merge: nested maps",
+	values: []string{
+		`{"flags": {"profile": true, "nested": {"a": 1}}}`,
+		`{"flags": {"status": true, "nested": {"b": 2}}}`,
+		"flags.nested.c=3",
+	},
+	opts: []flagutils.MapOption{flagutils.Merge()},
+	expectedValue: flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"profile": true,
+			"status":  true,
+			"nested": map[string]interface{}{
+				"a": float64(1),
+				"b": float64(2),
+				"c": float64(3),
+			},
+		},
+	},
+}, {
+	about:  "merge: non-map values replace maps",
+	values: []string{`{"flags": {"profile": true}}`, `{"flags": false}`},
+	opts:   []flagutils.MapOption{flagutils.Merge()},
+	expectedValue: flagutils.StringMap{
+		"flags": false,
+	},
+}, {
+	about:  "merge: default value",
+	values: []string{"flags.status=true"},
+	opts:   []flagutils.MapOption{flagutils.Merge()},
+	defaultValue: map[string]interface{}{
+		"answer": 42,
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+	},
+	expectedValue: flagutils.StringMap{
+		"answer": 42,
+		"flags": map[string]interface{}{
+			"profile": true,
+			"status":  true,
+		},
+	},
+}, {
+	about:  "merge: error",
+	values: []string{`{"gisf": true}`, `{"answer": }`},
+	opts:   []flagutils.MapOption{flagutils.Merge()},
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+	},
+	expectedError: "cannot unmarshal JSON: invalid character .*",
+}, {
+	about:  "accumulate entries: repeated flags",
+	values: []string{"key1=v1", "key2=v2,nested.a=1", `{"nested": {"b": 2}}`, "key1=v3"},
+	opts:   []flagutils.MapOption{flagutils.AccumulateEntries()},
+	expectedValue: flagutils.StringMap{
+		"key1": "v3",
+		"key2": "v2",
+		"nested": map[string]interface{}{
+			"a": float64(1),
+			"b": float64(2),
+		},
+	},
+}, {
+	about:  "accumulate entries: default value replaced",
+	values: []string{"key1=v1", "key2=v2"},
+	opts:   []flagutils.MapOption{flagutils.AccumulateEntries()},
+	defaultValue: map[string]interface{}{
+		"key0": "v0",
+	},
+	expectedValue: flagutils.StringMap{
+		"key1": "v1",
+		"key2": "v2",
+	},
+}, {
+	about:  "accumulate entries: error",
+	values: []string{"key1=v1", "key2"},
+	opts:   []flagutils.MapOption{flagutils.AccumulateEntries()},
+	expectedValue: flagutils.StringMap{
+		"key1": "v1",
+	},
+	expectedError: "cannot unmarshal JSON: .*",
+}, {
+	about:  "use number: JSON",
+	values: []string{`{"id": 12345678901234567890, "ratio": 0.25, "nested": {"n": 1e3}}`},
+	opts:   []flagutils.MapOption{flagutils.UseNumber()},
+	expectedValue: flagutils.StringMap{
+		"id":    json.Number("12345678901234567890"),
+		"ratio": json.Number("0.25"),
+		"nested": map[string]interface{}{
+			"n": json.Number("1e3"),
+		},
+	},
+}, {
+	about:  "use number: pairs",
+	values: []string{"id=9007199254740993,list=[1, 2],name=42abc,twice=1 2"},
+	opts:   []flagutils.MapOption{flagutils.UseNumber()},
+	expectedValue: flagutils.StringMap{
+		"id":    json.Number("9007199254740993"),
+		"list":  []interface{}{json.Number("1"), json.Number("2")},
+		"name":  "42abc",
+		"twice": "1 2",
+	},
+}, {
+	about:         "use number: invalid JSON",
+	values:        []string{`{"id": 1} {"id": 2}`},
+	opts:          []flagutils.MapOption{flagutils.UseNumber()},
+	expectedError: "cannot unmarshal JSON: invalid data after top-level value",
+}, {
+	about:  "disallow duplicate keys: no duplicates",
+	values: []string{`{"a": 1, "b": {"a": 2}, "c": [{"a": 3}, {"a": 4}]}`},
+	opts:   []flagutils.MapOption{flagutils.DisallowDuplicateKeys()},
+	expectedValue: flagutils.StringMap{
+		"a": float64(1),
+		"b": map[string]interface{}{
+			"a": float64(2),
+		},
+		"c": []interface{}{
+			map[string]interface{}{"a": float64(3)},
+			map[string]interface{}{"a": float64(4)},
+		},
+	},
+}, {
+	about:         "disallow duplicate keys: top-level key",
+	values:        []string{`"url": "https://1.2.3.4", "gisf": true, "url": "https://4.3.2.1"`},
+	opts:          []flagutils.MapOption{flagutils.DisallowDuplicateKeys()},
+	expectedError: `duplicate key "url"`,
+}, {
+	about:         "disallow duplicate keys: nested key",
+	values:        []string{`{"flags": {"profile": true, "status": true, "profile": false}}`},
+	opts:          []flagutils.MapOption{flagutils.DisallowDuplicateKeys()},
+	expectedError: `duplicate key "flags.profile"`,
+}, {
+	about:         "disallow duplicate keys: key in array",
+	values:        []string{`{"list": [{"a": 1, "a": 2}]}`},
+	opts:          []flagutils.MapOption{flagutils.DisallowDuplicateKeys()},
+	expectedError: `duplicate key "list.a"`,
+}, {
+	about:         "disallow duplicate keys: pairs",
+	values:        []string{"flags.profile=true,url=https://1.2.3.4, flags.profile=false"},
+	opts:          []flagutils.MapOption{flagutils.DisallowDuplicateKeys()},
+	expectedError: `duplicate key "flags.profile"`,
+}, {
+	about:  "disallow duplicate keys: repeated flags",
+	values: []string{"a=1", "a=2"},
+	opts:   []flagutils.MapOption{flagutils.DisallowDuplicateKeys(), flagutils.AccumulateEntries()},
+	expectedValue: flagutils.StringMap{
+		"a": float64(2),
+	},
+}, {
+	about:  "duplicate keys allowed without option",
+	values: []string{`{"a": 1, "a": 2}`},
+	expectedValue: flagutils.StringMap{
+		"a": float64(2),
+	},
+}, {
+	about:  "allow keys: allowed keys",
+	values: []string{"log_level=debug,flags.Profile=true"},
+	opts:   []flagutils.MapOption{flagutils.AllowKeys("log_level", "flags", "url")},
+	expectedValue: flagutils.StringMap{
+		"log_level": "debug",
+		"flags": map[string]interface{}{
+			"Profile": true,
+		},
+	},
+}, {
+	about:  "allow keys: typo",
+	values: []string{`{"lgo_level": "debug"}`},
+	opts:   []flagutils.MapOption{flagutils.AllowKeys("log_level", "flags", "url")},
+	defaultValue: map[string]interface{}{
+		"url": "https://1.2.3.4",
+	},
+	expectedValue: flagutils.StringMap{
+		"url": "https://1.2.3.4",
+	},
+	expectedError: `invalid key "lgo_level": did you mean "log_level"\?`,
+}, {
+	about:         "allow keys: different case",
+	values:        []string{"URL=https://1.2.3.4"},
+	opts:          []flagutils.MapOption{flagutils.AllowKeys("log_level", "flags", "url")},
+	expectedError: `invalid key "URL": did you mean "url"\?`,
+}, {
+	about:         "allow keys: no suggestion",
+	values:        []string{"url=https://1.2.3.4,verbose=true"},
+	opts:          []flagutils.MapOption{flagutils.AllowKeys("log_level", "flags", "url")},
+	expectedError: `invalid key "verbose": allowed keys are "log_level", "flags", "url"`,
+}, {
+	about:  "allow keys: multiple options",
+	values: []string{"url=https://1.2.3.4", "log_level=info"},
+	opts: []flagutils.MapOption{
+		flagutils.AllowKeys("url"),
+		flagutils.AllowKeys("log_level"),
+		flagutils.AccumulateEntries(),
+	},
+	expectedValue: flagutils.StringMap{
+		"log_level": "info",
+		"url":       "https://1.2.3.4",
+	},
+}, {
+	about:  "validate keys: valid keys",
+	values: []string{"log_level=debug,flags.profile=true"},
+	opts:   []flagutils.MapOption{flagutils.ValidateKeys(validateKey)},
+	expectedValue: flagutils.StringMap{
+		"log_level": "debug",
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+	},
+}, {
+	about:  "validate keys: invalid key",
+	values: []string{`{"log_level": "debug", "Bad": 1, "Worse": 2}`},
+	opts:   []flagutils.MapOption{flagutils.ValidateKeys(validateKey)},
+	defaultValue: map[string]interface{}{
+		"Default": true,
+	},
+	expectedValue: flagutils.StringMap{
+		"Default": true,
+	},
+	expectedError: `invalid key "Bad": must be lower case`,
+}, {
+	about:         "validate keys: invalid nested key",
+	values:        []string{"flags.Profile=true"},
+	opts:          []flagutils.MapOption{flagutils.ValidateKeys(validateKey)},
+	expectedError: `invalid key "flags.Profile": must be lower case`,
+}, {
+	about:         "match keys: invalid key",
+	values:        []string{"log-level=debug"},
+	opts:          []flagutils.MapOption{flagutils.MatchKeys(regexp.MustCompile(`^[a-z_]+$`))},
+	expectedError: `invalid key "log-level": does not match "\^\[a-z_\]\+\$"`,
+}, {
+	about:  "match keys: default value not validated",
+	values: []string{"log_level=debug"},
+	opts:   []flagutils.MapOption{flagutils.MatchKeys(regexp.MustCompile(`^[a-z_]+$`)), flagutils.Merge()},
+	defaultValue: map[string]interface{}{
+		"Default": true,
+	},
+	expectedValue: flagutils.StringMap{
+		"Default":   true,
+		"log_level": "debug",
+	},
+}, {
+	about:  "validate values: valid values",
+	values: []string{`{"retries": 3, "server": {"port": 8080}}`},
+	opts:   []flagutils.MapOption{flagutils.ValidateValues(validateValue)},
+	expectedValue: flagutils.StringMap{
+		"retries": 3.0,
+		"server": map[string]interface{}{
+			"port": 8080.0,
+		},
+	},
+}, {
+	about:  "validate values: invalid value",
+	values: []string{`{"retries": "many"}`},
+	opts:   []flagutils.MapOption{flagutils.ValidateValues(validateValue)},
+	defaultValue: map[string]interface{}{
+		"retries": 1.0,
+	},
+	expectedValue: flagutils.StringMap{
+		"retries": 1.0,
+	},
+	expectedError: `invalid value at "retries": must be a number`,
+}, {
+	about:         "validate values: invalid nested value",
+	values:        []string{"server.port=70000"},
+	opts:          []flagutils.MapOption{flagutils.ValidateValues(validateValue)},
+	expectedError: `invalid value at "server.port": must be between 1 and 65535`,
+}, {
+	about:  "validate values: multiple validators",
+	values: []string{"name="},
+	opts: []flagutils.MapOption{
+		flagutils.ValidateValues(validateValue),
+		flagutils.ValidateValues(func(path string, value interface{}) error {
+			if value == "" {
+				return errors.New("must not be empty")
+			}
+			return nil
+		}),
+	},
+	expectedError: `invalid value at "name": must not be empty`,
+}, {
+	about:  "validate: valid value",
+	values: []string{"url=https://1.2.3.4,retries=3"},
+	opts:   []flagutils.MapOption{flagutils.Validate(validateConf)},
+	expectedValue: flagutils.StringMap{
+		"url":     "https://1.2.3.4",
+		"retries": float64(3),
+	},
+}, {
+	about:  "validate: invalid value",
+	values: []string{"url=https://1.2.3.4,retries=3", "retries=3"},
+	opts:   []flagutils.MapOption{flagutils.Validate(validateConf)},
+	expectedValue: flagutils.StringMap{
+		"url":     "https://1.2.3.4",
+		"retries": float64(3),
+	},
+	expectedError: "missing url",
+}, {
+	about:  "validate: path error",
+	values: []string{"url=https://1.2.3.4,retries=many"},
+	opts:   []flagutils.MapOption{flagutils.Validate(validateConf)},
+	defaultValue: map[string]interface{}{
+		"url": "https://4.3.2.1",
+	},
+	expectedValue: flagutils.StringMap{
+		"url": "https://4.3.2.1",
+	},
+	expectedError: `invalid value at "retries": must be a number`,
+}, {
+	about:  "validate: with merge",
+	values: []string{"url=https://1.2.3.4", "retries=3"},
+	opts:   []flagutils.MapOption{flagutils.Merge(), flagutils.Validate(validateConf)},
+	expectedValue: flagutils.StringMap{
+		"url":     "https://1.2.3.4",
+		"retries": float64(3),
+	},
+}}
+
+func validateKey(key string) error {
+	if strings.ToLower(key) != key {
+		return errors.New("must be lower case")
+	}
+	return nil
+}
+
+func validateValue(path string, value interface{}) error {
+	switch path {
+	case "retries":
+		if _, ok := value.(float64); !ok {
+			return errors.New("must be a number")
+		}
+	case "server.port":
+		if port, ok := value.(float64); !ok || port < 1 || port > 65535 {
+			return errors.New("must be between 1 and 65535")
+		}
+	}
+	return nil
+}
+
+func validateConf(m flagutils.StringMap) error {
+	if _, ok := m["url"].(string); !ok {
+		return errors.New("missing url")
+	}
+	if retries, ok := m["retries"]; ok {
+		if _, ok := retries.(float64); !ok {
+			return &flagutils.PathError{
+				Path: "retries",
+				Err:  errors.New("must be a number"),
+			}
+		}
+	}
+	return nil
+}
+
+func TestMapOptions(t *testing.T) {
+	for _, test := range mapOptionsTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Map("conf", test.defaultValue, "map usage", test.opts...)
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set("conf", value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestMapMergeDoesNotModifyDefault(t *testing.T) {
+	runIsolated(t, "default not modified", func(c *qt.C) {
+		defaultValue := map[string]interface{}{
+			"flags": map[string]interface{}{
+				"profile": true,
+			},
+		}
+		var v flagutils.StringMap
+		flagutils.MapVar(&v, "conf", defaultValue, "map usage", flagutils.Merge())
+		err := flag.Set("conf", "flags.status=true")
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(defaultValue, qt.DeepEquals, map[string]interface{}{
+			"flags": map[string]interface{}{
+				"profile": true,
+			},
+		})
+	})
+}
+
+func TestPathErrorUnwrap(t *testing.T) {
+	c := qt.New(t)
+	err := errors.New("bad wolf")
+	var perr error = &flagutils.PathError{
+		Path: "flags.profile",
+		Err:  err,
+	}
+	c.Assert(perr, qt.ErrorMatches, `invalid value at "flags.profile": bad wolf`)
+	c.Assert(errors.Is(perr, err), qt.Equals, true)
+}
+
+func TestMapUseNumberString(t *testing.T) {
+	runIsolated(t, "round trip", func(c *qt.C) {
+		flagutils.Map("conf", nil, "map usage", flagutils.UseNumber())
+		err := flag.Set("conf", `{"id": 12345678901234567890}`)
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(flag.Lookup("conf").Value.String(), qt.Equals, `{"id":12345678901234567890}`)
+	})
+}
+
+var mapExpandEnvTests = []struct {
+	about         string
+	value         string
+	expectedValue flagutils.StringMap
+	expectedError string
+}{{
+	about: "string values",
+	value: `{"password": "${FLAGUTILS_PASSWORD}", "dsn": "${FLAGUTILS_USER}:${FLAGUTILS_PASSWORD}@localhost"}`,
+	expectedValue: flagutils.StringMap{
+		"password": "secret",
+		"dsn":      "who:secret@localhost",
+	},
+}, {
+	about: "nested values",
+	value: "db.password=${FLAGUTILS_PASSWORD},hosts=[\"${FLAGUTILS_USER}.example.com\"],port=5432",
+	expectedValue: flagutils.StringMap{
+		"db": map[string]interface{}{
+			"password": "secret",
+		},
+		"hosts": []interface{}{"who.example.com"},
+		"port":  5432.0,
+	},
+}, {
+	about: "keys not expanded",
+	value: `{"${FLAGUTILS_USER}": true}`,
+	expectedValue: flagutils.StringMap{
+		"${FLAGUTILS_USER}": true,
+	},
+}, {
+	about: "escaped dollars",
+	value: `{"price": "$$5", "ref": "$${FLAGUTILS_USER}", "plain": "$FLAGUTILS_USER costs $"}`,
+	expectedValue: flagutils.StringMap{
+		"price": "$5",
+		"ref":   "${FLAGUTILS_USER}",
+		"plain": "$FLAGUTILS_USER costs $",
+	},
+}, {
+	about: "empty variable",
+	value: `{"empty": "[${FLAGUTILS_EMPTY}]"}`,
+	expectedValue: flagutils.StringMap{
+		"empty": "[]",
+	},
+}, {
+	about:         "error: variable not set",
+	value:         `{"db": {"password": "${FLAGUTILS_NO_SUCH_VAR}"}}`,
+	expectedError: `invalid value at "db.password": environment variable "FLAGUTILS_NO_SUCH_VAR" is not set`,
+}, {
+	about:         "error: variable not set in array",
+	value:         `{"hosts": ["localhost", "${FLAGUTILS_NO_SUCH_VAR}"]}`,
+	expectedError: `invalid value at "hosts.1": environment variable "FLAGUTILS_NO_SUCH_VAR" is not set`,
+}, {
+	about:         "error: unterminated reference",
+	value:         `{"password": "${FLAGUTILS_PASSWORD"}`,
+	expectedError: `invalid value at "password": unterminated variable reference in "\${FLAGUTILS_PASSWORD"`,
+}, {
+	about:         "error: empty reference",
+	value:         `{"password": "${}"}`,
+	expectedError: `invalid value at "password": empty variable reference`,
+}}
+
+func TestMapExpandEnv(t *testing.T) {
+	t.Setenv("FLAGUTILS_USER", "who")
+	t.Setenv("FLAGUTILS_PASSWORD", "secret")
+	t.Setenv("FLAGUTILS_EMPTY", "")
+	for _, test := range mapExpandEnvTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Map("conf", nil, "map usage", flagutils.ExpandEnv())
+			err := flag.Set("conf", test.value)
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestMapExpandEnvDisabled(t *testing.T) {
+	t.Setenv("FLAGUTILS_PASSWORD", "secret")
+	runIsolated(t, "disabled", func(c *qt.C) {
+		v := flagutils.Map("conf", nil, "map usage")
+		err := flag.Set("conf", "password=${FLAGUTILS_PASSWORD}")
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(*v, qt.DeepEquals, flagutils.StringMap{
+			"password": "${FLAGUTILS_PASSWORD}",
+		})
+	})
+}
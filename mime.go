@@ -0,0 +1,93 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// MediaType holds a MIME media type, like "text/html", and its parameters,
+// like "charset".
+type MediaType struct {
+	Type   string
+	Params map[string]string
+}
+
+// String returns the media type formatted as in a Content-Type header, as
+// in "text/html; charset=utf-8".
+func (t MediaType) String() string {
+	if t.Type == "" {
+		return ""
+	}
+	return mime.FormatMediaType(t.Type, t.Params)
+}
+
+// MIME defines a media type flag with specified name, default value, and
+// usage string. The return value is the address of a MediaType variable
+// that stores the value of the flag.
+func MIME(name string, value MediaType, usage string, opts ...Option) *MediaType {
+	var t MediaType
+	MIMEVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// MIMEVar defines a media type flag with specified name, default value, and
+// usage string. The argument p points to a MediaType variable in which to
+// store the value of the flag.
+func MIMEVar(p *MediaType, name string, value MediaType, usage string, opts ...Option) {
+	MIMEVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// MIMEVarFS is like MIMEVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func MIMEVarFS(fs *flag.FlagSet, p *MediaType, name string, value MediaType, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewMIMEValue(p), name, usage, opts)
+}
+
+// NewMIMEValue returns a MIMEValue storing its value in p.
+func NewMIMEValue(p *MediaType) *MIMEValue {
+	return &MIMEValue{
+		p: p,
+	}
+}
+
+// MIMEValue holds a media type that can be provided via the command line in
+// the Content-Type header format, for instance
+// "application/json; charset=utf-8". The value is validated with
+// mime.ParseMediaType, and the type is stored lower case.
+type MIMEValue struct {
+	p *MediaType
+}
+
+// String implements flag.Value by returning the formatted media type.
+func (t *MIMEValue) String() string {
+	if t.p == nil {
+		return ""
+	}
+	return t.p.String()
+}
+
+// Set implements flag.Value by parsing the given media type.
+func (t *MIMEValue) Set(value string) error {
+	typ, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return fmt.Errorf("invalid media type %q: %v", value, err)
+	}
+	if !strings.Contains(typ, "/") {
+		return fmt.Errorf("invalid media type %q: missing subtype", value)
+	}
+	*t.p = MediaType{
+		Type:   typ,
+		Params: params,
+	}
+	return nil
+}
+
+// Type implements pflag.Value by returning "mediaType".
+func (t *MIMEValue) Type() string {
+	return "mediaType"
+}
@@ -0,0 +1,132 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.MIMEValue)(nil)
+
+var mimeTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.MediaType
+	expectedValue       flagutils.MediaType
+	expectedStringValue string
+	expectedError       string
+}{{
+	about: "type only",
+	name:  "type",
+	value: "application/json",
+	expectedValue: flagutils.MediaType{
+		Type:   "application/json",
+		Params: map[string]string{},
+	},
+	expectedStringValue: "application/json",
+}, {
+	about: "type and parameters",
+	name:  "params",
+	value: `Multipart/Form-Data; charset=UTF-8; Boundary="bad wolf"`,
+	expectedValue: flagutils.MediaType{
+		Type:   "multipart/form-data",
+		Params: map[string]string{"charset": "UTF-8", "boundary": "bad wolf"},
+	},
+	expectedStringValue: `multipart/form-data; boundary="bad wolf"; charset=UTF-8`,
+}, {
+	about: "default value: with value",
+	name:  "def1",
+	value: "text/plain",
+	defaultValue: flagutils.MediaType{
+		Type: "text/html",
+	},
+	expectedValue: flagutils.MediaType{
+		Type:   "text/plain",
+		Params: map[string]string{},
+	},
+}, {
+	about: "default value: without value",
+	name:  "def2",
+	defaultValue: flagutils.MediaType{
+		Type: "text/html",
+	},
+	expectedValue: flagutils.MediaType{
+		Type: "text/html",
+	},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid media type "": mime: no media type`,
+}, {
+	about:         "error: missing subtype",
+	name:          "err",
+	value:         "text",
+	expectedError: `invalid media type "text": missing subtype`,
+}, {
+	about:         "error: invalid type",
+	name:          "err",
+	value:         "text/html/x",
+	expectedError: `invalid media type "text/html/x": mime: unexpected content after media subtype`,
+}, {
+	about:         "error: invalid parameter",
+	name:          "err",
+	value:         "text/html; charset",
+	expectedError: `invalid media type "text/html; charset": mime: invalid media parameter`,
+}}
+
+func TestMIME(t *testing.T) {
+	for _, test := range mimeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.MIME(test.name, test.defaultValue, "mime usage")
+			if test.value != "" || test.defaultValue.Type == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestMIMEVar(t *testing.T) {
+	for _, test := range mimeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.MediaType
+			flagutils.MIMEVar(&v, test.name, test.defaultValue, "mime usage")
+			if test.value != "" || test.defaultValue.Type == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestMIMEValueString(t *testing.T) {
+	for _, test := range mimeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue.Type != "" || test.expectedError != "" {
+				return
+			}
+			var t flagutils.MediaType
+			v := flagutils.NewMIMEValue(&t)
+			c.Assert(v.String(), qt.Equals, "")
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Option configures behavior shared by all the flags defined in this
+// package, and can be provided to any flag constructor, for instance:
+//
+//	flagutils.Slice("tags", nil, "the tags", flagutils.Required(), flagutils.Env("APP_TAGS"))
+//
+// Options are also accepted where a SliceOption, a MapOption, a
+// PortSliceOption, a GlobOption or a JSONOption is expected.
+// The Env and Required options are applied when flags are parsed with Parse
+// or FlagSet.Parse, and the Hidden option is applied when flag defaults are
+// printed with PrintDefaults or FlagSet.PrintDefaults.
+type Option func(*flagOptions)
+
+// flagOptions holds the configuration shared by all flags.
+type flagOptions struct {
+	required bool
+	env      string
+	hidden   bool
+}
+
+// applySliceOption implements SliceOption.
+func (f Option) applySliceOption(o *sliceOptions) {
+	f(&o.flag)
+}
+
+// applyMapOption implements MapOption.
+func (f Option) applyMapOption(o *mapOptions) {
+	f(&o.flag)
+}
+
+// applyPortSliceOption implements PortSliceOption.
+func (f Option) applyPortSliceOption(o *portSliceOptions) {
+	f(&o.flag)
+}
+
+// applyGlobOption implements GlobOption.
+func (f Option) applyGlobOption(o *globOptions) {
+	f(&o.flag)
+}
+
+// applyJSONOption implements JSONOption.
+func (f Option) applyJSONOption(o *jsonOptions) {
+	f(&o.flag)
+}
+
+// Required returns an option making a flag mandatory: parsing fails if the
+// flag is not provided via the command line or, when the Env option is also
+// provided, via the environment.
+func Required() Option {
+	return func(o *flagOptions) {
+		o.required = true
+	}
+}
+
+// Env returns an option making a flag fall back to the value of the given
+// environment variable when the flag is not provided via the command line.
+// The environment variable is also mentioned in the usage string.
+func Env(name string) Option {
+	return func(o *flagOptions) {
+		o.env = name
+	}
+}
+
+// Hidden returns an option excluding a flag from the usage message, for
+// instance for deprecated or internal flags. Hidden flags can still be
+// provided via the command line.
+func Hidden() Option {
+	return func(o *flagOptions) {
+		o.hidden = true
+	}
+}
+
+// Var defines a flag with specified name and usage string, as done by
+// flag.Var, also applying the given options. It can be used to define flags
+// of values implemented outside this package, like the ones provided by the
+// flagutils subpackages.
+func Var(value flag.Value, name string, usage string, opts ...Option) {
+	VarFS(flag.CommandLine, value, name, usage, opts...)
+}
+
+// VarFS is like Var, but defines the flag in the given flag set rather than
+// in the default command line flag set.
+func VarFS(fs *flag.FlagSet, value flag.Value, name string, usage string, opts ...Option) {
+	defineVar(fs, value, name, usage, opts)
+}
+
+// defineVar defines a flag in fs with the given value, name, usage string and
+// options.
+func defineVar(fs *flag.FlagSet, value flag.Value, name, usage string, opts []Option) {
+	var o flagOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.define(fs, value, name, usage)
+}
+
+// define defines a flag in fs with the given value, name and usage string,
+// configured with the options. The flag value is wrapped only if any option
+// has been provided.
+func (o flagOptions) define(fs *flag.FlagSet, value flag.Value, name, usage string) {
+	if o == (flagOptions{}) {
+		fs.Var(value, name, usage)
+		return
+	}
+	if o.env != "" {
+		usage += " (env " + o.env + ")"
+	}
+	if o.required {
+		usage += " (required)"
+	}
+	fs.Var(&optionValue{
+		Value: value,
+		opts:  o,
+	}, name, usage)
+}
+
+// optionValue wraps a flag value configured with options.
+type optionValue struct {
+	flag.Value
+	opts flagOptions
+}
+
+// String implements flag.Value by returning the string representation of
+// the wrapped value. The flag package calls this method on zero values.
+func (v *optionValue) String() string {
+	if v.Value == nil {
+		return ""
+	}
+	return v.Value.String()
+}
+
+// Type implements pflag.Value by returning the type of the wrapped flag
+// value, or "value" if it does not implement pflag.Value.
+func (v *optionValue) Type() string {
+	if t, ok := v.Value.(interface{ Type() string }); ok {
+		return t.Type()
+	}
+	return "value"
+}
+
+// IsBoolFlag reports whether the wrapped flag value is a boolean flag, which
+// can be provided via the command line without a value.
+func (v *optionValue) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// flagOptionsOf returns the options of the given flag.
+func flagOptionsOf(f *flag.Flag) flagOptions {
+	if v, ok := f.Value.(*optionValue); ok {
+		return v.opts
+	}
+	return flagOptions{}
+}
+
+// parsedChecker is implemented by flag values whose constraints can only be
+// checked once all the occurrences of the flag have been parsed.
+type parsedChecker interface {
+	checkParsed() error
+}
+
+// applyOptions applies the Env and Required options to the flags defined in
+// fs, after the command line has been parsed. Flags are first set from the
+// environment, then the values implementing parsedChecker are checked, and
+// finally required flags are checked.
+func applyOptions(fs *flag.FlagSet) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		o := flagOptionsOf(f)
+		if err != nil || set[f.Name] || o.env == "" {
+			return
+		}
+		value, ok := os.LookupEnv(o.env)
+		if !ok {
+			return
+		}
+		if e := fs.Set(f.Name, value); e != nil {
+			err = fmt.Errorf("invalid value for flag -%s from environment variable %s: %v", f.Name, o.env, e)
+			return
+		}
+		set[f.Name] = true
+	})
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || !set[f.Name] {
+			return
+		}
+		value := f.Value
+		if v, ok := value.(*optionValue); ok {
+			value = v.Value
+		}
+		if c, ok := value.(parsedChecker); ok {
+			if e := c.checkParsed(); e != nil {
+				err = fmt.Errorf("invalid value for flag -%s: %v", f.Name, e)
+			}
+		}
+	})
+	fs.VisitAll(func(f *flag.Flag) {
+		o := flagOptionsOf(f)
+		if err != nil || set[f.Name] || !o.required {
+			return
+		}
+		if o.env != "" {
+			err = fmt.Errorf("required flag -%s not provided (environment variable %s is not set)", f.Name, o.env)
+			return
+		}
+		err = fmt.Errorf("required flag -%s not provided", f.Name)
+	})
+	return err
+}
+
+// printDefaults prints the default values of all the flags defined in fs,
+// except hidden ones, as done by flag.FlagSet.PrintDefaults.
+func printDefaults(fs *flag.FlagSet) {
+	visible := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+	visible.SetOutput(fs.Output())
+	fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value
+		if v, ok := value.(*optionValue); ok {
+			if v.opts.hidden {
+				return
+			}
+			value = v.Value
+		}
+		visible.Var(value, f.Name, f.Usage)
+		visible.Lookup(f.Name).DefValue = f.DefValue
+	})
+	visible.PrintDefaults()
+}
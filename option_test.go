@@ -0,0 +1,201 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var optionTests = []struct {
+	about         string
+	args          []string
+	env           map[string]string
+	opts          []flagutils.Option
+	expectedValue time.Duration
+	expectedError string
+}{{
+	about:         "no options",
+	args:          []string{"-timeout", "1m"},
+	expectedValue: time.Minute,
+}, {
+	about:         "required: provided",
+	args:          []string{"-timeout", "1m"},
+	opts:          []flagutils.Option{flagutils.Required()},
+	expectedValue: time.Minute,
+}, {
+	about:         "required: not provided",
+	opts:          []flagutils.Option{flagutils.Required()},
+	expectedValue: time.Second,
+	expectedError: "required flag -timeout not provided",
+}, {
+	about:         "env: not provided",
+	opts:          []flagutils.Option{flagutils.Env("FLAGUTILS_TIMEOUT")},
+	expectedValue: time.Second,
+}, {
+	about:         "env: provided via the environment",
+	env:           map[string]string{"FLAGUTILS_TIMEOUT": "1d"},
+	opts:          []flagutils.Option{flagutils.Env("FLAGUTILS_TIMEOUT")},
+	expectedValue: 24 * time.Hour,
+}, {
+	about:         "env: provided via the command line and the environment",
+	args:          []string{"-timeout", "1m"},
+	env:           map[string]string{"FLAGUTILS_TIMEOUT": "1d"},
+	opts:          []flagutils.Option{flagutils.Env("FLAGUTILS_TIMEOUT")},
+	expectedValue: time.Minute,
+}, {
+	about:         "env: invalid value",
+	env:           map[string]string{"FLAGUTILS_TIMEOUT": "forever"},
+	opts:          []flagutils.Option{flagutils.Env("FLAGUTILS_TIMEOUT")},
+	expectedValue: time.Second,
+	expectedError: `invalid value for flag -timeout from environment variable FLAGUTILS_TIMEOUT: invalid duration "forever"`,
+}, {
+	about:         "required and env: provided via the environment",
+	env:           map[string]string{"FLAGUTILS_TIMEOUT": "1h"},
+	opts:          []flagutils.Option{flagutils.Required(), flagutils.Env("FLAGUTILS_TIMEOUT")},
+	expectedValue: time.Hour,
+}, {
+	about:         "required and env: not provided",
+	opts:          []flagutils.Option{flagutils.Required(), flagutils.Env("FLAGUTILS_TIMEOUT")},
+	expectedValue: time.Second,
+	expectedError: "required flag -timeout not provided \\(environment variable FLAGUTILS_TIMEOUT is not set\\)",
+}, {
+	about:         "hidden",
+	args:          []string{"-timeout", "1m"},
+	opts:          []flagutils.Option{flagutils.Hidden()},
+	expectedValue: time.Minute,
+}}
+
+func TestOptions(t *testing.T) {
+	for _, test := range optionTests {
+		t.Run(test.about, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+			c := qt.New(t)
+			fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+			fs.SetOutput(new(bytes.Buffer))
+			v := fs.Duration("timeout", time.Second, "duration usage", test.opts...)
+			err := fs.Parse(test.args)
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestOptionsWithSliceAndMapOptions(t *testing.T) {
+	t.Setenv("FLAGUTILS_TAGS", "a;b")
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	tags := fs.Slice("tags", nil, "the tags", flagutils.Separator(";"), flagutils.Env("FLAGUTILS_TAGS"), flagutils.Accumulate())
+	conf := fs.Map("conf", nil, "the configuration", flagutils.Required(), flagutils.Merge())
+	err := fs.Parse([]string{"-conf", "a=1", "-conf", "b=2"})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(*tags, qt.DeepEquals, flagutils.StringSlice{"a", "b"})
+	c.Assert(*conf, qt.DeepEquals, flagutils.StringMap{"a": 1.0, "b": 2.0})
+
+	fs = flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	tags = fs.Slice("tags", nil, "the tags", flagutils.Separator(";"), flagutils.Env("FLAGUTILS_TAGS"), flagutils.Accumulate())
+	fs.Map("conf", nil, "the configuration", flagutils.Required(), flagutils.Merge())
+	err = fs.Parse([]string{"-tags", "c", "-tags", "d"})
+	c.Assert(err, qt.ErrorMatches, "required flag -conf not provided")
+	c.Assert(*tags, qt.DeepEquals, flagutils.StringSlice{"c", "d"})
+}
+
+func TestOptionsGlobal(t *testing.T) {
+	t.Setenv("FLAGUTILS_LEVEL", "debug")
+	runIsolated(t, "global", func(c *qt.C) {
+		c.Patch(&os.Args, []string{"cmd", "-n", "42", "arg"})
+		level := flagutils.Enum("level", "info", []string{"debug", "info"}, "the level", flagutils.Env("FLAGUTILS_LEVEL"))
+		n := flagutils.OptionalOf("n", 0, strconv.Atoi, "a number", flagutils.Required())
+		flagutils.Parse()
+		c.Assert(*level, qt.Equals, "debug")
+		c.Assert(n.Value(), qt.Equals, 42)
+		c.Assert(flag.Args(), qt.DeepEquals, []string{"arg"})
+	})
+}
+
+func TestOptionsVar(t *testing.T) {
+	t.Setenv("FLAGUTILS_TAGS", "a,b")
+	runIsolated(t, "var", func(c *qt.C) {
+		c.Patch(&os.Args, []string{"cmd"})
+		var tags flagutils.StringSlice
+		flagutils.Var(&tags, "tags", "the tags", flagutils.Env("FLAGUTILS_TAGS"))
+		c.Assert(flag.Lookup("tags").Usage, qt.Equals, "the tags (env FLAGUTILS_TAGS)")
+		flagutils.Parse()
+		c.Assert(tags, qt.DeepEquals, flagutils.StringSlice{"a", "b"})
+	})
+}
+
+func TestOptionsVarFS(t *testing.T) {
+	c := qt.New(t)
+	fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+	var tags flagutils.StringSlice
+	flagutils.VarFS(fs, &tags, "tags", "the tags")
+	c.Assert(fs.Lookup("tags").Value, qt.Equals, flag.Value(&tags))
+	err := fs.Parse([]string{"-tags", "a,b"})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(tags, qt.DeepEquals, flagutils.StringSlice{"a", "b"})
+}
+
+func TestOptionsBoolFlag(t *testing.T) {
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	verbosity := fs.Count("v", 0, "the verbosity", flagutils.Hidden())
+	err := fs.Parse([]string{"-v", "-v", "arg"})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(*verbosity, qt.Equals, 2)
+	c.Assert(fs.Args(), qt.DeepEquals, []string{"arg"})
+}
+
+func TestOptionsUsage(t *testing.T) {
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.Slice("tags", []string{"a", "b"}, "the tags", flagutils.Env("APP_TAGS"))
+	fs.Duration("timeout", time.Minute, "the timeout", flagutils.Required())
+	fs.OptionalBool("debug", "enable debug mode", flagutils.Hidden())
+	fs.Count("v", 0, "the verbosity")
+	err := fs.Parse([]string{"-h"})
+	c.Assert(err, qt.Equals, flag.ErrHelp)
+	c.Assert(buf.String(), qt.Equals, `Usage of cmd:
+  -tags value
+    	the tags (env APP_TAGS) (default a,b)
+  -timeout value
+    	the timeout (required) (default 1m)
+  -v	the verbosity
+`)
+}
+
+func TestOptionsUsageGlobal(t *testing.T) {
+	runIsolated(t, "global", func(c *qt.C) {
+		c.Patch(&os.Args, []string{"cmd", "-h"})
+		c.Patch(&flag.Usage, flag.Usage)
+		// Mimic the standard command line flag set, which calls flag.Usage.
+		flag.CommandLine.Usage = func() { flag.Usage() }
+		var buf bytes.Buffer
+		flag.CommandLine.SetOutput(&buf)
+		flagutils.Slice("tags", []string{"a", "b"}, "the tags")
+		flagutils.OptionalBool("debug", "enable debug mode", flagutils.Hidden())
+		flagutils.Parse()
+		c.Assert(buf.String(), qt.Equals, `Usage of cmd:
+  -tags value
+    	the tags (default a,b)
+`)
+	})
+}
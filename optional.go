@@ -0,0 +1,80 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// OptionalOf defines a flag of an arbitrary type with specified name,
+// default value, parsing function and usage string. The returned Optional
+// holds the value of the flag, and also records whether the flag was
+// provided via the command line, so that an explicit zero value can be told
+// apart from the default.
+func OptionalOf[T any](name string, value T, parse func(string) (T, error), usage string, opts ...Option) *Optional[T] {
+	return OptionalOfFS(flag.CommandLine, name, value, parse, usage, opts...)
+}
+
+// OptionalOfFS is like OptionalOf, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func OptionalOfFS[T any](fs *flag.FlagSet, name string, value T, parse func(string) (T, error), usage string, opts ...Option) *Optional[T] {
+	o := NewOptional(value, parse)
+	defineVar(fs, o, name, usage, opts)
+	return o
+}
+
+// NewOptional returns an Optional with the given default value and using the
+// given function to parse values.
+func NewOptional[T any](value T, parse func(string) (T, error)) *Optional[T] {
+	return &Optional[T]{
+		value: value,
+		parse: parse,
+	}
+}
+
+// Optional holds a value of an arbitrary type that can be provided via the
+// command line, and reports whether it has been set.
+type Optional[T any] struct {
+	value T
+	set   bool
+	parse func(string) (T, error)
+}
+
+// Value returns the value of the flag, or the default value if the flag has
+// not been set.
+func (o *Optional[T]) Value() T {
+	return o.value
+}
+
+// IsSet reports whether the flag has been set.
+func (o *Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// String implements flag.Value by returning the value in its default format.
+func (o *Optional[T]) String() string {
+	if o == nil {
+		return ""
+	}
+	return fmt.Sprint(o.value)
+}
+
+// Set implements flag.Value by parsing the given value and recording that
+// the flag has been set.
+func (o *Optional[T]) Set(value string) error {
+	v, err := o.parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q: %v", value, err)
+	}
+	o.value = v
+	o.set = true
+	return nil
+}
+
+// Type implements pflag.Value by returning the name of the value type,
+// for instance "int".
+func (o *Optional[T]) Type() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
@@ -0,0 +1,85 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"strconv"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.Optional[int])(nil)
+
+var optionalTests = []struct {
+	about               string
+	name                string
+	value               string
+	set                 bool
+	defaultValue        int
+	expectedValue       int
+	expectedIsSet       bool
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "value",
+	name:                "value",
+	value:               "42",
+	set:                 true,
+	expectedValue:       42,
+	expectedIsSet:       true,
+	expectedStringValue: "42",
+}, {
+	about:               "explicit zero value",
+	name:                "zero",
+	value:               "0",
+	set:                 true,
+	defaultValue:        10,
+	expectedValue:       0,
+	expectedIsSet:       true,
+	expectedStringValue: "0",
+}, {
+	about:               "not provided",
+	name:                "unset",
+	defaultValue:        10,
+	expectedValue:       10,
+	expectedStringValue: "10",
+}, {
+	about:               "error: parse failure",
+	name:                "err",
+	value:               "ten",
+	set:                 true,
+	defaultValue:        10,
+	expectedValue:       10,
+	expectedStringValue: "10",
+	expectedError:       `invalid value "ten": strconv.Atoi: parsing "ten": invalid syntax`,
+}}
+
+func TestOptionalOf(t *testing.T) {
+	for _, test := range optionalTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.OptionalOf(test.name, test.defaultValue, strconv.Atoi, "optional usage")
+			if test.set {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v.Value(), qt.Equals, test.expectedValue)
+			c.Assert(v.IsSet(), qt.Equals, test.expectedIsSet)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestOptionalDefaultValue(t *testing.T) {
+	runIsolated(t, "default value", func(c *qt.C) {
+		flagutils.OptionalOf("retries", 3, strconv.Atoi, "optional usage")
+		c.Assert(flag.Lookup("retries").DefValue, qt.Equals, "3")
+	})
+}
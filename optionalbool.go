@@ -0,0 +1,78 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"strconv"
+)
+
+// OptionalBool defines a tri-state bool flag with specified name and usage
+// string. The return value is the address of a *bool variable that is nil
+// when the flag is not provided, and points to true or false otherwise. This
+// allows programs to distinguish an explicit "-flag=false" from the flag
+// being omitted.
+func OptionalBool(name string, usage string, opts ...Option) **bool {
+	var b *bool
+	OptionalBoolVar(&b, name, usage, opts...)
+	return &b
+}
+
+// OptionalBoolVar defines a tri-state bool flag with specified name and
+// usage string. The argument p points to a *bool variable which is set to
+// nil, and then to the address of the flag value when the flag is provided.
+func OptionalBoolVar(p **bool, name string, usage string, opts ...Option) {
+	OptionalBoolVarFS(flag.CommandLine, p, name, usage, opts...)
+}
+
+// OptionalBoolVarFS is like OptionalBoolVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func OptionalBoolVarFS(fs *flag.FlagSet, p **bool, name string, usage string, opts ...Option) {
+	*p = nil
+	defineVar(fs, NewOptionalBoolValue(p), name, usage, opts)
+}
+
+// NewOptionalBoolValue returns an OptionalBoolValue storing its value in p.
+func NewOptionalBoolValue(p **bool) *OptionalBoolValue {
+	return &OptionalBoolValue{
+		p: p,
+	}
+}
+
+// OptionalBoolValue holds a boolean that can be unset, true or false. As for
+// standard bool flags, the value can be omitted on the command line, in
+// which case it is true. The value can also be expressed as true/false,
+// yes/no, on/off or 1/0.
+type OptionalBoolValue struct {
+	p **bool
+}
+
+// String implements flag.Value by returning "true" or "false", or an empty
+// string if the flag has not been provided.
+func (b *OptionalBoolValue) String() string {
+	if b.p == nil || *b.p == nil {
+		return ""
+	}
+	return strconv.FormatBool(**b.p)
+}
+
+// Set implements flag.Value by parsing the given boolean value.
+func (b *OptionalBoolValue) Set(value string) error {
+	v, err := parseBool(value)
+	if err != nil {
+		return err
+	}
+	*b.p = &v
+	return nil
+}
+
+// Type implements pflag.Value by returning "bool".
+func (b *OptionalBoolValue) Type() string {
+	return "bool"
+}
+
+// IsBoolFlag reports that the flag does not require a value, so that it can
+// be provided as "-flag" on the command line.
+func (b *OptionalBoolValue) IsBoolFlag() bool {
+	return true
+}
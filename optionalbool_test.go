@@ -0,0 +1,91 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.OptionalBoolValue)(nil)
+
+var optionalBoolTests = []struct {
+	about               string
+	args                []string
+	expectedValue       *bool
+	expectedStringValue string
+	expectedError       string
+}{{
+	about: "not provided",
+}, {
+	about:               "without value",
+	args:                []string{"-b"},
+	expectedValue:       newBool(true),
+	expectedStringValue: "true",
+}, {
+	about:               "explicit true",
+	args:                []string{"-b=yes"},
+	expectedValue:       newBool(true),
+	expectedStringValue: "true",
+}, {
+	about:               "explicit false",
+	args:                []string{"-b=false"},
+	expectedValue:       newBool(false),
+	expectedStringValue: "false",
+}, {
+	about:               "last occurrence wins",
+	args:                []string{"-b", "-b=off"},
+	expectedValue:       newBool(false),
+	expectedStringValue: "false",
+}, {
+	about:         "error: invalid value",
+	args:          []string{"-b=maybe"},
+	expectedError: `invalid boolean value "maybe" for -b: invalid boolean value "maybe"`,
+}}
+
+func TestOptionalBool(t *testing.T) {
+	for _, test := range optionalBoolTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			var v *bool
+			b := flagutils.NewOptionalBoolValue(&v)
+			fs.Var(b, "b", "optional bool usage")
+			err := fs.Parse(test.args)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				c.Assert(v, qt.IsNil)
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+			c.Assert(b.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestOptionalBoolVar(t *testing.T) {
+	runIsolated(t, "optional bool var", func(c *qt.C) {
+		var v *bool
+		flagutils.OptionalBoolVar(&v, "unset", "optional bool usage")
+		v2 := flagutils.OptionalBool("set", "optional bool usage")
+		c.Assert(flag.Set("set", "false"), qt.Equals, nil)
+		c.Assert(v, qt.IsNil)
+		c.Assert(*v2, qt.DeepEquals, newBool(false))
+	})
+}
+
+func TestOptionalBoolValueIsBoolFlag(t *testing.T) {
+	c := qt.New(t)
+	var v *bool
+	c.Assert(flagutils.NewOptionalBoolValue(&v).IsBoolFlag(), qt.Equals, true)
+}
+
+func newBool(b bool) *bool {
+	return &b
+}
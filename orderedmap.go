@@ -0,0 +1,206 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// OrderedMap defines a flag containing an ordered map of strings with
+// specified name, default value, and usage string. The return value is the
+// address of an OrderedStringMap variable that stores the value of the flag.
+func OrderedMap(name string, value OrderedStringMap, usage string, opts ...Option) *OrderedStringMap {
+	var s OrderedStringMap
+	OrderedMapVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// OrderedMapVar defines a flag containing an ordered map of strings with
+// specified name, default value, and usage string. The argument p points to
+// an OrderedStringMap variable in which to store the value of the flag.
+func OrderedMapVar(p *OrderedStringMap, name string, value OrderedStringMap, usage string, opts ...Option) {
+	OrderedMapVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// OrderedMapVarFS is like OrderedMapVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func OrderedMapVarFS(fs *flag.FlagSet, p *OrderedStringMap, name string, value OrderedStringMap, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, p, name, usage, opts)
+}
+
+// MapEntry holds a key and its associated value in an OrderedStringMap.
+type MapEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// OrderedStringMap holds a map of strings to empty interfaces that can be
+// provided via the command line as a JSON encoded string, preserving the
+// order in which top-level keys are provided. Nested objects are decoded as
+// regular maps.
+type OrderedStringMap []MapEntry
+
+// Get returns the value associated with the given key, and whether the key
+// is present in the map.
+func (s OrderedStringMap) Get(key string) (interface{}, bool) {
+	for _, e := range s {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
+// String implements flag.Value by returning the map as a JSON encoded string,
+// with keys in their original order.
+func (s *OrderedStringMap) String() string {
+	if *s == nil {
+		return "null"
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, e := range *s {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(e.Key)
+		if err != nil {
+			// This should never happen.
+			panic(err)
+		}
+		v, err := json.Marshal(e.Value)
+		if err != nil {
+			// This should never happen.
+			panic(err)
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// Set implements flag.Value by unmarshaling the JSON encoded value into the
+// ordered map. The JSON enclosing braces can be omitted. If a key is repeated,
+// the last value is retained at the position of the first occurrence.
+func (s *OrderedStringMap) Set(value string) error {
+	*s = nil
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "{") {
+		value = "{" + value + "}"
+	}
+	m, err := decodeOrderedMap([]byte(value))
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal JSON: %v", err)
+	}
+	*s = m
+	return nil
+}
+
+// Type implements pflag.Value by returning "orderedStringMap".
+func (s *OrderedStringMap) Type() string {
+	return "orderedStringMap"
+}
+
+// MarshalText implements encoding.TextMarshaler by returning the map as a
+// JSON encoded string, with keys in their original order. A nil map results
+// in an empty value.
+func (s OrderedStringMap) MarshalText() ([]byte, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by populating the map
+// from the given JSON encoded value, as done by OrderedStringMap.Set. An
+// empty value results in a nil map.
+func (s *OrderedStringMap) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*s = nil
+		return nil
+	}
+	return s.Set(string(text))
+}
+
+// MarshalJSON implements json.Marshaler by encoding the map as a JSON object,
+// with keys in their original order.
+func (s OrderedStringMap) MarshalJSON() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler by decoding either a JSON object,
+// preserving the order of its keys, or a JSON string holding a JSON encoded
+// object.
+func (s *OrderedStringMap) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		return s.UnmarshalText([]byte(value))
+	}
+	m, err := decodeOrderedMap(data)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal JSON: %v", err)
+	}
+	*s = m
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler by encoding the map as a YAML
+// mapping. YAML encoders usually sort mapping keys, so that the original
+// order of the keys is not preserved: use MarshalText or MarshalJSON when
+// the order is relevant.
+func (s OrderedStringMap) MarshalYAML() (interface{}, error) {
+	if s == nil {
+		return nil, nil
+	}
+	m := make(map[string]interface{}, len(s))
+	for _, e := range s {
+		m[e.Key] = e.Value
+	}
+	return m, nil
+}
+
+// decodeOrderedMap decodes the given JSON object preserving the order of its
+// keys.
+func decodeOrderedMap(data []byte) (OrderedStringMap, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected JSON object, got %v", tok)
+	}
+	m := OrderedStringMap{}
+	index := make(map[string]int)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		if i, ok := index[key]; ok {
+			m[i].Value = v
+			continue
+		}
+		index[key] = len(m)
+		m = append(m, MapEntry{Key: key, Value: v})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON object")
+	}
+	return m, nil
+}
@@ -0,0 +1,207 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"gopkg.in/yaml.v3"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.OrderedStringMap)(nil)
+
+var orderedMapTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.OrderedStringMap
+	expectedValue       flagutils.OrderedStringMap
+	expectedStringValue string
+	expectedError       string
+}{{
+	about: "single pair",
+	name:  "single",
+	value: `{"gisf": true}`,
+	expectedValue: flagutils.OrderedStringMap{
+		{Key: "gisf", Value: true},
+	},
+	expectedStringValue: `{"gisf":true}`,
+}, {
+	about: "multiple pairs",
+	name:  "multiple",
+	value: `{"url": "https://1.2.3.4", "gisf": true, "answer": 42}`,
+	expectedValue: flagutils.OrderedStringMap{
+		{Key: "url", Value: "https://1.2.3.4"},
+		{Key: "gisf", Value: true},
+		{Key: "answer", Value: float64(42)},
+	},
+	expectedStringValue: `{"url":"https://1.2.3.4","gisf":true,"answer":42}`,
+}, {
+	about: "nested map",
+	name:  "nested",
+	value: `{"gisf": true, "flags": {"status": true, "profile": true}}`,
+	expectedValue: flagutils.OrderedStringMap{
+		{Key: "gisf", Value: true},
+		{Key: "flags", Value: map[string]interface{}{
+			"profile": true,
+			"status":  true,
+		}},
+	},
+	expectedStringValue: `{"gisf":true,"flags":{"profile":true,"status":true}}`,
+}, {
+	about: "repeated keys",
+	name:  "repeated",
+	value: `{"b": 1, "a": 2, "b": 3}`,
+	expectedValue: flagutils.OrderedStringMap{
+		{Key: "b", Value: float64(3)},
+		{Key: "a", Value: float64(2)},
+	},
+	expectedStringValue: `{"b":3,"a":2}`,
+}, {
+	about: "no braces",
+	name:  "nobraces",
+	value: `  "z": 1, "a": 2 `,
+	expectedValue: flagutils.OrderedStringMap{
+		{Key: "z", Value: float64(1)},
+		{Key: "a", Value: float64(2)},
+	},
+	expectedStringValue: `{"z":1,"a":2}`,
+}, {
+	about:               "empty string",
+	name:                "empty",
+	expectedValue:       flagutils.OrderedStringMap{},
+	expectedStringValue: "{}",
+}, {
+	about: "default value: with value",
+	name:  "def1",
+	value: `{"gisf": true}`,
+	defaultValue: flagutils.OrderedStringMap{
+		{Key: "answer", Value: 42},
+	},
+	expectedValue: flagutils.OrderedStringMap{
+		{Key: "gisf", Value: true},
+	},
+}, {
+	about: "default value: without value",
+	name:  "def2",
+	defaultValue: flagutils.OrderedStringMap{
+		{Key: "answer", Value: 42},
+	},
+	expectedValue: flagutils.OrderedStringMap{
+		{Key: "answer", Value: 42},
+	},
+}, {
+	about:               "error: not a map",
+	name:                "err",
+	value:               "42",
+	expectedStringValue: "null",
+	expectedError:       "cannot unmarshal JSON: .*",
+}, {
+	about:               "error: trailing data",
+	name:                "err",
+	value:               `{"a": 1} {"b": 2}`,
+	expectedStringValue: "null",
+	expectedError:       "cannot unmarshal JSON: unexpected data after JSON object",
+}}
+
+func TestOrderedMap(t *testing.T) {
+	for _, test := range orderedMapTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.OrderedMap(test.name, test.defaultValue, "ordered map usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestOrderedMapVar(t *testing.T) {
+	for _, test := range orderedMapTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.OrderedStringMap
+			flagutils.OrderedMapVar(&v, test.name, test.defaultValue, "ordered map usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestOrderedStringMapString(t *testing.T) {
+	for _, test := range orderedMapTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.OrderedStringMap
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestOrderedStringMapGet(t *testing.T) {
+	c := qt.New(t)
+	var v flagutils.OrderedStringMap
+	err := v.Set(`"gisf": true, "answer": 42`)
+	c.Assert(err, qt.Equals, nil)
+	value, ok := v.Get("answer")
+	c.Assert(ok, qt.Equals, true)
+	c.Assert(value, qt.Equals, float64(42))
+	value, ok = v.Get("question")
+	c.Assert(ok, qt.Equals, false)
+	c.Assert(value, qt.IsNil)
+}
+
+func TestOrderedStringMapJSON(t *testing.T) {
+	c := qt.New(t)
+	var conf struct {
+		Conf  flagutils.OrderedStringMap `json:"conf"`
+		Flags flagutils.OrderedStringMap `json:"flags"`
+	}
+	err := json.Unmarshal([]byte(`{"conf": {"z": 1, "a": 2}, "flags": "\"profile\": true"}`), &conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf.Conf, qt.DeepEquals, flagutils.OrderedStringMap{
+		{Key: "z", Value: 1.0},
+		{Key: "a", Value: 2.0},
+	})
+	c.Assert(conf.Flags, qt.DeepEquals, flagutils.OrderedStringMap{
+		{Key: "profile", Value: true},
+	})
+
+	b, err := json.Marshal(conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(b), qt.Equals, `{"conf":{"z":1,"a":2},"flags":{"profile":true}}`)
+}
+
+func TestOrderedStringMapYAML(t *testing.T) {
+	c := qt.New(t)
+	v := flagutils.OrderedStringMap{
+		{Key: "z", Value: 1},
+		{Key: "a", Value: []string{"b"}},
+	}
+	b, err := yaml.Marshal(map[string]flagutils.OrderedStringMap{"conf": v})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(b), qt.Equals, "conf:\n    a:\n        - b\n    z: 1\n")
+
+	text, err := v.MarshalText()
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(text), qt.Equals, `{"z":1,"a":["b"]}`)
+}
@@ -0,0 +1,85 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Percent defines a percentage flag with specified name, default value, and
+// usage string. The value can be provided via the command line as a
+// percentage, as in "85%", or as a plain number. If bareIsPercent is true,
+// plain numbers are percentages too ("85"), otherwise they are fractions
+// ("0.85"). The return value is the address of a float64 variable that stores
+// the value of the flag, normalized to the [0, 1] range.
+func Percent(name string, value float64, bareIsPercent bool, usage string, opts ...Option) *float64 {
+	var f float64
+	PercentVar(&f, name, value, bareIsPercent, usage, opts...)
+	return &f
+}
+
+// PercentVar defines a percentage flag with specified name, default value,
+// and usage string. See Percent for details on the accepted values. The
+// argument p points to a float64 variable in which to store the value of the
+// flag, normalized to the [0, 1] range.
+func PercentVar(p *float64, name string, value float64, bareIsPercent bool, usage string, opts ...Option) {
+	PercentVarFS(flag.CommandLine, p, name, value, bareIsPercent, usage, opts...)
+}
+
+// PercentVarFS is like PercentVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func PercentVarFS(fs *flag.FlagSet, p *float64, name string, value float64, bareIsPercent bool, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewPercentValue(p, bareIsPercent), name, usage, opts)
+}
+
+// NewPercentValue returns a PercentValue storing its value in p. If
+// bareIsPercent is true, numbers without a "%" suffix are interpreted as
+// percentages rather than fractions.
+func NewPercentValue(p *float64, bareIsPercent bool) *PercentValue {
+	return &PercentValue{
+		p:             p,
+		bareIsPercent: bareIsPercent,
+	}
+}
+
+// PercentValue holds a percentage that can be provided via the command line,
+// stored as a fraction between 0 and 1.
+type PercentValue struct {
+	p             *float64
+	bareIsPercent bool
+}
+
+// String implements flag.Value by returning the value as a percentage, as
+// in "85%".
+func (f *PercentValue) String() string {
+	if f.p == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f.p*100, 'g', 10, 64) + "%"
+}
+
+// Set implements flag.Value by parsing the given percentage.
+func (f *PercentValue) Set(value string) error {
+	number, isPercent := strings.CutSuffix(strings.TrimSpace(value), "%")
+	v, err := parseFloat(strings.TrimSpace(number))
+	if err != nil {
+		return fmt.Errorf("invalid percentage %q", value)
+	}
+	if isPercent || f.bareIsPercent {
+		v /= 100
+	}
+	if v < 0 || v > 1 {
+		return fmt.Errorf("invalid percentage %q: must be between 0%% and 100%%", value)
+	}
+	*f.p = v
+	return nil
+}
+
+// Type implements pflag.Value by returning "percent".
+func (f *PercentValue) Type() string {
+	return "percent"
+}
@@ -0,0 +1,149 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.PercentValue)(nil)
+
+var percentTests = []struct {
+	about               string
+	name                string
+	value               string
+	bareIsPercent       bool
+	defaultValue        float64
+	expectedValue       float64
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "percentage",
+	name:                "percent",
+	value:               "85%",
+	expectedValue:       0.85,
+	expectedStringValue: "85%",
+}, {
+	about:               "fraction",
+	name:                "fraction",
+	value:               "0.85",
+	expectedValue:       0.85,
+	expectedStringValue: "85%",
+}, {
+	about:               "bare percentage",
+	name:                "bare",
+	value:               "85",
+	bareIsPercent:       true,
+	expectedValue:       0.85,
+	expectedStringValue: "85%",
+}, {
+	about:               "percentage with bare percentages",
+	name:                "percent-bare",
+	value:               "12.5 %",
+	bareIsPercent:       true,
+	expectedValue:       0.125,
+	expectedStringValue: "12.5%",
+}, {
+	about:               "bounds",
+	name:                "bounds",
+	value:               "100%",
+	expectedValue:       1,
+	expectedStringValue: "100%",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "10%",
+	defaultValue:  0.5,
+	expectedValue: 0.1,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  0.5,
+	expectedValue: 0.5,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid percentage ""`,
+}, {
+	about:         "error: not a number",
+	name:          "err",
+	value:         "half",
+	expectedError: `invalid percentage "half"`,
+}, {
+	about:         "error: fraction out of range",
+	name:          "err",
+	value:         "85",
+	expectedError: `invalid percentage "85": must be between 0% and 100%`,
+}, {
+	about:         "error: percentage out of range",
+	name:          "err",
+	value:         "120%",
+	expectedError: `invalid percentage "120%": must be between 0% and 100%`,
+}, {
+	about:         "error: negative",
+	name:          "err",
+	value:         "-1%",
+	expectedError: `invalid percentage "-1%": must be between 0% and 100%`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "NaN%",
+	defaultValue:  0.5,
+	expectedValue: 0.5,
+	expectedError: `invalid percentage "NaN%"`,
+}}
+
+func TestPercent(t *testing.T) {
+	for _, test := range percentTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Percent(test.name, test.defaultValue, test.bareIsPercent, "percent usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestPercentVar(t *testing.T) {
+	for _, test := range percentTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v float64
+			flagutils.PercentVar(&v, test.name, test.defaultValue, test.bareIsPercent, "percent usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestPercentValueString(t *testing.T) {
+	for _, test := range percentTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != 0 || test.expectedError != "" {
+				return
+			}
+			var v float64
+			f := flagutils.NewPercentValue(&v, test.bareIsPercent)
+			err := f.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(f.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// Port defines a port flag with specified name, default value, and usage
+// string. The port must be in the 1-65535 range, unless allowZero is true,
+// in which case 0 is also accepted, usually meaning that any available port
+// can be used. The return value is the address of an int variable that
+// stores the value of the flag.
+func Port(name string, value int, allowZero bool, usage string, opts ...Option) *int {
+	var n int
+	PortVar(&n, name, value, allowZero, usage, opts...)
+	return &n
+}
+
+// PortVar defines a port flag with specified name, default value, and usage
+// string. See Port for details on the accepted values. The argument p points
+// to an int variable in which to store the value of the flag.
+func PortVar(p *int, name string, value int, allowZero bool, usage string, opts ...Option) {
+	PortVarFS(flag.CommandLine, p, name, value, allowZero, usage, opts...)
+}
+
+// PortVarFS is like PortVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func PortVarFS(fs *flag.FlagSet, p *int, name string, value int, allowZero bool, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewPortValue(p, allowZero), name, usage, opts)
+}
+
+// NewPortValue returns a PortValue storing its value in p. If allowZero is
+// true, port 0 is accepted.
+func NewPortValue(p *int, allowZero bool) *PortValue {
+	return &PortValue{
+		p:         p,
+		allowZero: allowZero,
+	}
+}
+
+// PortValue holds a TCP or UDP port that can be provided via the command
+// line.
+type PortValue struct {
+	p         *int
+	allowZero bool
+}
+
+// String implements flag.Value by returning the port as a string.
+func (n *PortValue) String() string {
+	if n.p == nil {
+		return ""
+	}
+	return strconv.Itoa(*n.p)
+}
+
+// Set implements flag.Value by parsing the given port.
+func (n *PortValue) Set(value string) error {
+	if n.allowZero && value == "0" {
+		*n.p = 0
+		return nil
+	}
+	port, err := parsePort(value)
+	if err != nil {
+		if n.allowZero {
+			return fmt.Errorf("invalid port %q: must be a number between 0 and 65535", value)
+		}
+		return err
+	}
+	*n.p = port
+	return nil
+}
+
+// Type implements pflag.Value by returning "port".
+func (n *PortValue) Type() string {
+	return "port"
+}
@@ -0,0 +1,115 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.PortValue)(nil)
+
+var portTests = []struct {
+	about         string
+	name          string
+	value         string
+	allowZero     bool
+	defaultValue  int
+	expectedValue int
+	expectedError string
+}{{
+	about:         "port",
+	name:          "port",
+	value:         "8080",
+	expectedValue: 8080,
+}, {
+	about:         "bounds",
+	name:          "bounds",
+	value:         "65535",
+	expectedValue: 65535,
+}, {
+	about:         "zero allowed",
+	name:          "zero",
+	value:         "0",
+	allowZero:     true,
+	defaultValue:  8080,
+	expectedValue: 0,
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "443",
+	defaultValue:  8080,
+	expectedValue: 443,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  8080,
+	expectedValue: 8080,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid port "": must be a number between 1 and 65535`,
+}, {
+	about:         "error: zero",
+	name:          "err",
+	value:         "0",
+	defaultValue:  8080,
+	expectedValue: 8080,
+	expectedError: `invalid port "0": must be a number between 1 and 65535`,
+}, {
+	about:         "error: too big",
+	name:          "err",
+	value:         "65536",
+	allowZero:     true,
+	expectedError: `invalid port "65536": must be a number between 0 and 65535`,
+}, {
+	about:         "error: negative",
+	name:          "err",
+	value:         "-1",
+	allowZero:     true,
+	expectedError: `invalid port "-1": must be a number between 0 and 65535`,
+}, {
+	about:         "error: not a number",
+	name:          "err",
+	value:         "http",
+	expectedError: `invalid port "http": must be a number between 1 and 65535`,
+}}
+
+func TestPort(t *testing.T) {
+	for _, test := range portTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Port(test.name, test.defaultValue, test.allowZero, "port usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestPortVar(t *testing.T) {
+	for _, test := range portTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v int
+			flagutils.PortVar(&v, test.name, test.defaultValue, test.allowZero, "port usage")
+			if test.value != "" || test.defaultValue == 0 {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PortRange defines a port range flag with specified name, default value,
+// and usage string. The value is provided via the command line as
+// "min-max", for instance "30000-32767", or as a single port. The return
+// value is the address of an interval variable that stores the value of the
+// flag.
+func PortRange(name string, value Interval[int], usage string, opts ...Option) *Interval[int] {
+	var r Interval[int]
+	PortRangeVar(&r, name, value, usage, opts...)
+	return &r
+}
+
+// PortRangeVar defines a port range flag with specified name, default value,
+// and usage string. The argument p points to an interval variable in which
+// to store the value of the flag.
+func PortRangeVar(p *Interval[int], name string, value Interval[int], usage string, opts ...Option) {
+	PortRangeVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// PortRangeVarFS is like PortRangeVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func PortRangeVarFS(fs *flag.FlagSet, p *Interval[int], name string, value Interval[int], usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*PortRangeValue)(p), name, usage, opts)
+}
+
+// PortRangeValue holds a range of TCP or UDP ports that can be provided via
+// the command line. Both bounds are included in the range, and must be in
+// the 1-65535 range.
+type PortRangeValue Interval[int]
+
+// String implements flag.Value by returning the range as "min-max".
+func (r *PortRangeValue) String() string {
+	if r.Min == 0 && r.Max == 0 {
+		return ""
+	}
+	return strconv.Itoa(r.Min) + "-" + strconv.Itoa(r.Max)
+}
+
+// Set implements flag.Value by parsing the given port range.
+func (r *PortRangeValue) Set(value string) error {
+	lo, hi, found := strings.Cut(value, "-")
+	if !found {
+		hi = lo
+	}
+	loPort, err := parsePort(strings.TrimSpace(lo))
+	if err != nil {
+		return err
+	}
+	hiPort, err := parsePort(strings.TrimSpace(hi))
+	if err != nil {
+		return err
+	}
+	if loPort > hiPort {
+		return fmt.Errorf("invalid port range %q: %d is greater than %d", value, loPort, hiPort)
+	}
+	*r = PortRangeValue{
+		Min: loPort,
+		Max: hiPort,
+	}
+	return nil
+}
+
+// Type implements pflag.Value by returning "portRange".
+func (r *PortRangeValue) Type() string {
+	return "portRange"
+}
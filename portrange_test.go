@@ -0,0 +1,129 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.PortRangeValue)(nil)
+
+var portRangeTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.Interval[int]
+	expectedValue       flagutils.Interval[int]
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "range",
+	name:                "range",
+	value:               "30000-32767",
+	expectedValue:       flagutils.Interval[int]{Min: 30000, Max: 32767},
+	expectedStringValue: "30000-32767",
+}, {
+	about:               "spaces",
+	name:                "spaces",
+	value:               "80 - 443",
+	expectedValue:       flagutils.Interval[int]{Min: 80, Max: 443},
+	expectedStringValue: "80-443",
+}, {
+	about:               "single port",
+	name:                "single",
+	value:               "8080",
+	expectedValue:       flagutils.Interval[int]{Min: 8080, Max: 8080},
+	expectedStringValue: "8080-8080",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "1-1023",
+	defaultValue:  flagutils.Interval[int]{Min: 1024, Max: 65535},
+	expectedValue: flagutils.Interval[int]{Min: 1, Max: 1023},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  flagutils.Interval[int]{Min: 1024, Max: 65535},
+	expectedValue: flagutils.Interval[int]{Min: 1024, Max: 65535},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid port "": must be a number between 1 and 65535`,
+}, {
+	about:         "error: invalid bound",
+	name:          "err",
+	value:         "0-1023",
+	expectedError: `invalid port "0": must be a number between 1 and 65535`,
+}, {
+	about:         "error: missing bound",
+	name:          "err",
+	value:         "1024-",
+	expectedError: `invalid port "": must be a number between 1 and 65535`,
+}, {
+	about:         "error: wrong order",
+	name:          "err",
+	value:         "32767-30000",
+	expectedError: `invalid port range "32767-30000": 32767 is greater than 30000`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "1-70000",
+	defaultValue:  flagutils.Interval[int]{Min: 1024, Max: 65535},
+	expectedValue: flagutils.Interval[int]{Min: 1024, Max: 65535},
+	expectedError: `invalid port "70000": must be a number between 1 and 65535`,
+}}
+
+func TestPortRange(t *testing.T) {
+	for _, test := range portRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.PortRange(test.name, test.defaultValue, "port range usage")
+			if test.value != "" || test.defaultValue == (flagutils.Interval[int]{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestPortRangeVar(t *testing.T) {
+	for _, test := range portRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.Interval[int]
+			flagutils.PortRangeVar(&v, test.name, test.defaultValue, "port range usage")
+			if test.value != "" || test.defaultValue == (flagutils.Interval[int]{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestPortRangeValueString(t *testing.T) {
+	for _, test := range portRangeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != (flagutils.Interval[int]{}) || test.expectedError != "" {
+				return
+			}
+			var v flagutils.PortRangeValue
+			c.Assert(v.String(), qt.Equals, "")
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
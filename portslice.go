@@ -0,0 +1,136 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PortSlice defines a port slice flag with specified name, default value, and
+// usage string. The return value is the address of an int slice variable that
+// stores the value of the flag.
+func PortSlice(name string, value []int, usage string, opts ...PortSliceOption) *[]int {
+	var s []int
+	PortSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// PortSliceVar defines a port slice flag with specified name, default value,
+// and usage string. The argument p points to an int slice variable in which to
+// store the value of the flag.
+func PortSliceVar(p *[]int, name string, value []int, usage string, opts ...PortSliceOption) {
+	PortSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// PortSliceVarFS is like PortSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func PortSliceVarFS(fs *flag.FlagSet, p *[]int, name string, value []int, usage string, opts ...PortSliceOption) {
+	*p = value
+	var o portSliceOptions
+	for _, opt := range opts {
+		opt.applyPortSliceOption(&o)
+	}
+	o.flag.define(fs, NewPortSliceValue(p, o.warn), name, usage)
+}
+
+// PortSliceOption configures the behavior of flags defined with PortSlice
+// and PortSliceVar. In addition to WarnPrivileged, the options returned by
+// Required, Env and Hidden can be used.
+type PortSliceOption interface {
+	applyPortSliceOption(*portSliceOptions)
+}
+
+// portSliceOption implements PortSliceOption with a function.
+type portSliceOption func(*portSliceOptions)
+
+// applyPortSliceOption implements PortSliceOption.
+func (f portSliceOption) applyPortSliceOption(o *portSliceOptions) {
+	f(o)
+}
+
+// portSliceOptions holds the configuration of a port slice flag.
+type portSliceOptions struct {
+	warn func(port int)
+	flag flagOptions
+}
+
+// WarnPrivileged returns an option making port slice flags call the given
+// function for every privileged port (below 1024) provided in the value, for
+// instance to log a warning.
+func WarnPrivileged(warn func(port int)) PortSliceOption {
+	return portSliceOption(func(o *portSliceOptions) {
+		o.warn = warn
+	})
+}
+
+// NewPortSliceValue returns a PortSliceValue storing its value in p. If warn
+// is not nil, it is called for every privileged port (below 1024) provided in
+// the value.
+func NewPortSliceValue(p *[]int, warn func(port int)) *PortSliceValue {
+	return &PortSliceValue{
+		p:    p,
+		warn: warn,
+	}
+}
+
+// PortSliceValue holds a slice of TCP or UDP ports that can be provided via
+// the command line as a comma separated list of values. Each port must be in
+// the 1-65535 range.
+type PortSliceValue struct {
+	p    *[]int
+	warn func(port int)
+}
+
+// String implements flag.Value by returning the slice as a string.
+func (s *PortSliceValue) String() string {
+	if s.p == nil {
+		return ""
+	}
+	values := make([]string, len(*s.p))
+	for i, port := range *s.p {
+		values[i] = strconv.Itoa(port)
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *PortSliceValue) Set(value string) error {
+	*s.p = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	ports := make([]int, len(values))
+	for i, v := range values {
+		if ports[i], err = parsePort(v); err != nil {
+			return err
+		}
+	}
+	if s.warn != nil {
+		for _, port := range ports {
+			if port < 1024 {
+				s.warn(port)
+			}
+		}
+	}
+	*s.p = ports
+	return nil
+}
+
+// Type implements pflag.Value by returning "portSlice".
+func (s *PortSliceValue) Type() string {
+	return "portSlice"
+}
+
+// parsePort parses the given value as a port in the 1-65535 range.
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q: must be a number between 1 and 65535", value)
+	}
+	return port, nil
+}
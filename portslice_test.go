@@ -0,0 +1,147 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.PortSliceValue)(nil)
+
+var portSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []int
+	expectedValue       []int
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single port",
+	name:                "single",
+	value:               "8080",
+	expectedValue:       []int{8080},
+	expectedStringValue: "8080",
+}, {
+	about:               "multiple ports",
+	name:                "multiple",
+	value:               "1, 443,8080 ,65535",
+	expectedValue:       []int{1, 443, 8080, 65535},
+	expectedStringValue: "1,443,8080,65535",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "8080",
+	defaultValue:  []int{80, 443},
+	expectedValue: []int{8080},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []int{80, 443},
+	expectedValue: []int{80, 443},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: not a number",
+	name:          "err",
+	value:         "80,http",
+	expectedError: `invalid port "http": must be a number between 1 and 65535`,
+}, {
+	about:         "error: zero",
+	name:          "err",
+	value:         "0",
+	expectedError: `invalid port "0": must be a number between 1 and 65535`,
+}, {
+	about:         "error: too big",
+	name:          "err",
+	value:         "65536",
+	expectedError: `invalid port "65536": must be a number between 1 and 65535`,
+}}
+
+func TestPortSlice(t *testing.T) {
+	for _, test := range portSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.PortSlice(test.name, test.defaultValue, "port slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestPortSliceVar(t *testing.T) {
+	for _, test := range portSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []int
+			flagutils.PortSliceVar(&v, test.name, test.defaultValue, "port slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestPortSliceValueString(t *testing.T) {
+	for _, test := range portSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v []int
+			s := flagutils.NewPortSliceValue(&v, nil)
+			s.Set(test.value)
+			c.Assert(s.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestPortSliceValueWarn(t *testing.T) {
+	c := qt.New(t)
+	var v, privileged []int
+	s := flagutils.NewPortSliceValue(&v, func(port int) {
+		privileged = append(privileged, port)
+	})
+	err := s.Set("22,80,1023,1024,8080")
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v, qt.DeepEquals, []int{22, 80, 1023, 1024, 8080})
+	c.Assert(privileged, qt.DeepEquals, []int{22, 80, 1023})
+}
+
+func TestPortSliceWarnPrivileged(t *testing.T) {
+	t.Setenv("FLAGUTILS_PORTS", "443,8443")
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	var privileged []int
+	warn := flagutils.WarnPrivileged(func(port int) {
+		privileged = append(privileged, port)
+	})
+	ports := fs.PortSlice("ports", nil, "the ports", warn, flagutils.Env("FLAGUTILS_PORTS"))
+	c.Assert(fs.Lookup("ports").Usage, qt.Equals, "the ports (env FLAGUTILS_PORTS)")
+	admin := fs.PortSlice("admin", nil, "the admin ports", warn)
+	err := fs.Parse([]string{"-admin", "22,2222"})
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(*ports, qt.DeepEquals, []int{443, 8443})
+	c.Assert(*admin, qt.DeepEquals, []int{22, 2222})
+	c.Assert(privileged, qt.DeepEquals, []int{22, 443})
+}
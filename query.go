@@ -0,0 +1,81 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Query defines a URL query parameters flag with specified name, default
+// value, and usage string. The return value is the address of a url.Values
+// variable that stores the value of the flag.
+func Query(name string, value url.Values, usage string, opts ...Option) *url.Values {
+	var v url.Values
+	QueryVar(&v, name, value, usage, opts...)
+	return &v
+}
+
+// QueryVar defines a URL query parameters flag with specified name, default
+// value, and usage string. The argument p points to a url.Values variable in
+// which to store the value of the flag.
+func QueryVar(p *url.Values, name string, value url.Values, usage string, opts ...Option) {
+	QueryVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// QueryVarFS is like QueryVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func QueryVarFS(fs *flag.FlagSet, p *url.Values, name string, value url.Values, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewQueryValue(p), name, usage, opts)
+}
+
+// NewQueryValue returns a QueryValue storing its value in p.
+func NewQueryValue(p *url.Values) *QueryValue {
+	return &QueryValue{
+		p: p,
+	}
+}
+
+// QueryValue holds URL query parameters that can be provided via the command
+// line as a query string, like "a=1&b=2&b=3", optionally prefixed with "?".
+// Keys and values are unescaped. The flag can be repeated, in which case
+// parameters are accumulated. The first occurrence replaces the default
+// value.
+type QueryValue struct {
+	p   *url.Values
+	set bool
+}
+
+// String implements flag.Value by returning the encoded query string, sorted
+// by key.
+func (q *QueryValue) String() string {
+	if q.p == nil {
+		return ""
+	}
+	return q.p.Encode()
+}
+
+// Set implements flag.Value by adding the parameters in the given query
+// string.
+func (q *QueryValue) Set(value string) error {
+	if !q.set {
+		*q.p = make(url.Values)
+		q.set = true
+	}
+	values, err := url.ParseQuery(strings.TrimPrefix(value, "?"))
+	if err != nil {
+		return fmt.Errorf("invalid query %q: %v", value, err)
+	}
+	for k, vs := range values {
+		(*q.p)[k] = append((*q.p)[k], vs...)
+	}
+	return nil
+}
+
+// Type implements pflag.Value by returning "query".
+func (q *QueryValue) Type() string {
+	return "query"
+}
@@ -0,0 +1,134 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"net/url"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.QueryValue)(nil)
+
+var queryTests = []struct {
+	about               string
+	name                string
+	values              []string
+	defaultValue        url.Values
+	expectedValue       url.Values
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single parameter",
+	name:                "single",
+	values:              []string{"a=1"},
+	expectedValue:       url.Values{"a": {"1"}},
+	expectedStringValue: "a=1",
+}, {
+	about:               "multiple parameters",
+	name:                "multiple",
+	values:              []string{"a=1&b=2&b=3"},
+	expectedValue:       url.Values{"a": {"1"}, "b": {"2", "3"}},
+	expectedStringValue: "a=1&b=2&b=3",
+}, {
+	about:               "leading question mark",
+	name:                "question",
+	values:              []string{"?q=bad+wolf&empty="},
+	expectedValue:       url.Values{"q": {"bad wolf"}, "empty": {""}},
+	expectedStringValue: "empty=&q=bad+wolf",
+}, {
+	about:               "escaped values",
+	name:                "escaped",
+	values:              []string{"redirect=http%3A%2F%2Fexample.com%2F%3Fa%3Db"},
+	expectedValue:       url.Values{"redirect": {"http://example.com/?a=b"}},
+	expectedStringValue: "redirect=http%3A%2F%2Fexample.com%2F%3Fa%3Db",
+}, {
+	about:               "repeated flags",
+	name:                "repeated",
+	values:              []string{"b=2&a=1", "b=3", "c"},
+	expectedValue:       url.Values{"a": {"1"}, "b": {"2", "3"}, "c": {""}},
+	expectedStringValue: "a=1&b=2&b=3&c=",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	values:        []string{"page=2"},
+	defaultValue:  url.Values{"page": {"1"}, "size": {"10"}},
+	expectedValue: url.Values{"page": {"2"}},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  url.Values{"page": {"1"}},
+	expectedValue: url.Values{"page": {"1"}},
+}, {
+	about:         "error: invalid escape",
+	name:          "err",
+	values:        []string{"a=%zz"},
+	expectedError: `invalid query "a=%zz": invalid URL escape "%zz"`,
+}, {
+	about:         "error: semicolon",
+	name:          "err",
+	values:        []string{"a=1;b=2"},
+	expectedError: `invalid query "a=1;b=2": invalid semicolon separator in query`,
+}}
+
+func TestQuery(t *testing.T) {
+	for _, test := range queryTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Query(test.name, test.defaultValue, "query usage")
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set(test.name, value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+				c.Assert(*v, qt.DeepEquals, test.expectedValue)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestQueryVar(t *testing.T) {
+	for _, test := range queryTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v url.Values
+			flagutils.QueryVar(&v, test.name, test.defaultValue, "query usage")
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set(test.name, value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+				c.Assert(v, qt.DeepEquals, test.expectedValue)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestQueryValueString(t *testing.T) {
+	for _, test := range queryTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil || test.expectedError != "" {
+				return
+			}
+			var v url.Values
+			q := flagutils.NewQueryValue(&v)
+			c.Assert(q.String(), qt.Equals, "")
+			for _, value := range test.values {
+				q.Set(value)
+			}
+			c.Assert(q.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
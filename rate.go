@@ -0,0 +1,137 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency holds a number of events per time interval, for instance for
+// configuring rate limiters.
+type Frequency struct {
+	Count    int64
+	Interval time.Duration
+}
+
+// PerSecond returns the number of events per second.
+func (f Frequency) PerSecond() float64 {
+	if f.Interval == 0 {
+		return 0
+	}
+	return float64(f.Count) / f.Interval.Seconds()
+}
+
+// String returns the frequency as "count/interval", as in "100/s" or
+// "5/30m".
+func (f Frequency) String() string {
+	if f.Interval == 0 {
+		return ""
+	}
+	var interval string
+	switch f.Interval {
+	case time.Second:
+		interval = "s"
+	case time.Minute:
+		interval = "m"
+	case time.Hour:
+		interval = "h"
+	case 24 * time.Hour:
+		interval = "d"
+	default:
+		interval = formatDuration(f.Interval)
+	}
+	return strconv.FormatInt(f.Count, 10) + "/" + interval
+}
+
+// Rate defines a rate flag with specified name, default value, and usage
+// string. The value is provided via the command line as "count/interval",
+// as in "100/s", "5/m" or "10k/h". The count can include a "k", "M" or "G"
+// multiplier suffix, and the interval can be a unit (s, m, h, d) or a
+// duration, as in "30/10s". The return value is the address of a Frequency
+// variable that stores the value of the flag.
+func Rate(name string, value Frequency, usage string, opts ...Option) *Frequency {
+	var f Frequency
+	RateVar(&f, name, value, usage, opts...)
+	return &f
+}
+
+// RateVar defines a rate flag with specified name, default value, and usage
+// string. See Rate for details on the accepted values. The argument p points
+// to a Frequency variable in which to store the value of the flag.
+func RateVar(p *Frequency, name string, value Frequency, usage string, opts ...Option) {
+	RateVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// RateVarFS is like RateVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func RateVarFS(fs *flag.FlagSet, p *Frequency, name string, value Frequency, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*RateValue)(p), name, usage, opts)
+}
+
+// RateValue holds a number of events per time interval that can be provided
+// via the command line.
+type RateValue Frequency
+
+// String implements flag.Value by returning the rate as "count/interval".
+func (r *RateValue) String() string {
+	return Frequency(*r).String()
+}
+
+// Set implements flag.Value by parsing the given rate.
+func (r *RateValue) Set(value string) error {
+	count, interval, ok := strings.Cut(value, "/")
+	if !ok {
+		return fmt.Errorf("invalid rate %q: expected \"count/interval\"", value)
+	}
+	n, err := parseCount(strings.TrimSpace(count))
+	if err != nil {
+		return fmt.Errorf("invalid rate %q: invalid count %q", value, count)
+	}
+	interval = strings.TrimSpace(interval)
+	duration := interval
+	if duration != "" && strings.IndexAny(duration[:1], "0123456789.") == -1 {
+		// Allow units without a number, as in "100/s".
+		duration = "1" + duration
+	}
+	d, err := parseDuration(duration)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid rate %q: invalid interval %q", value, interval)
+	}
+	*r = RateValue{
+		Count:    n,
+		Interval: d,
+	}
+	return nil
+}
+
+// Type implements pflag.Value by returning "rate".
+func (r *RateValue) Type() string {
+	return "rate"
+}
+
+// parseCount parses the given non-negative count, which can include a "k",
+// "M" or "G" multiplier suffix.
+func parseCount(value string) (int64, error) {
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(value, "k"):
+		multiplier = 1e3
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1e9
+	}
+	if multiplier != 1 {
+		value = value[:len(value)-1]
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 || n > (1<<63-1)/multiplier {
+		return 0, fmt.Errorf("invalid count %q", value)
+	}
+	return n * multiplier, nil
+}
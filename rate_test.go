@@ -0,0 +1,154 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.RateValue)(nil)
+
+var rateTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.Frequency
+	expectedValue       flagutils.Frequency
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "per second",
+	name:                "second",
+	value:               "100/s",
+	expectedValue:       flagutils.Frequency{Count: 100, Interval: time.Second},
+	expectedStringValue: "100/s",
+}, {
+	about:               "per minute",
+	name:                "minute",
+	value:               "5/m",
+	expectedValue:       flagutils.Frequency{Count: 5, Interval: time.Minute},
+	expectedStringValue: "5/m",
+}, {
+	about:               "multiplier per hour",
+	name:                "hour",
+	value:               "10k/h",
+	expectedValue:       flagutils.Frequency{Count: 10000, Interval: time.Hour},
+	expectedStringValue: "10000/h",
+}, {
+	about:               "per day",
+	name:                "day",
+	value:               "2M/d",
+	expectedValue:       flagutils.Frequency{Count: 2000000, Interval: 24 * time.Hour},
+	expectedStringValue: "2000000/d",
+}, {
+	about:               "duration interval",
+	name:                "duration",
+	value:               "30 / 10s",
+	expectedValue:       flagutils.Frequency{Count: 30, Interval: 10 * time.Second},
+	expectedStringValue: "30/10s",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "1/s",
+	defaultValue:  flagutils.Frequency{Count: 10, Interval: time.Second},
+	expectedValue: flagutils.Frequency{Count: 1, Interval: time.Second},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  flagutils.Frequency{Count: 10, Interval: time.Second},
+	expectedValue: flagutils.Frequency{Count: 10, Interval: time.Second},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid rate "": expected "count/interval"`,
+}, {
+	about:         "error: invalid count",
+	name:          "err",
+	value:         "many/s",
+	expectedError: `invalid rate "many/s": invalid count "many"`,
+}, {
+	about:         "error: negative count",
+	name:          "err",
+	value:         "-1/s",
+	expectedError: `invalid rate "-1/s": invalid count "-1"`,
+}, {
+	about:         "error: invalid interval",
+	name:          "err",
+	value:         "100/y",
+	expectedError: `invalid rate "100/y": invalid interval "y"`,
+}, {
+	about:         "error: zero interval",
+	name:          "err",
+	value:         "100/0s",
+	expectedError: `invalid rate "100/0s": invalid interval "0s"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "100",
+	defaultValue:  flagutils.Frequency{Count: 10, Interval: time.Second},
+	expectedValue: flagutils.Frequency{Count: 10, Interval: time.Second},
+	expectedError: `invalid rate "100": expected "count/interval"`,
+}}
+
+func TestRate(t *testing.T) {
+	for _, test := range rateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Rate(test.name, test.defaultValue, "rate usage")
+			if test.value != "" || test.defaultValue == (flagutils.Frequency{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestRateVar(t *testing.T) {
+	for _, test := range rateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.Frequency
+			flagutils.RateVar(&v, test.name, test.defaultValue, "rate usage")
+			if test.value != "" || test.defaultValue == (flagutils.Frequency{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestRateValueString(t *testing.T) {
+	for _, test := range rateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != (flagutils.Frequency{}) || test.expectedError != "" {
+				return
+			}
+			var v flagutils.RateValue
+			c.Assert(v.String(), qt.Equals, "")
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestFrequencyPerSecond(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(flagutils.Frequency{}.PerSecond(), qt.Equals, 0.0)
+	c.Assert(flagutils.Frequency{Count: 100, Interval: time.Second}.PerSecond(), qt.Equals, 100.0)
+	c.Assert(flagutils.Frequency{Count: 30, Interval: time.Minute}.PerSecond(), qt.Equals, 0.5)
+}
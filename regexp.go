@@ -0,0 +1,70 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+)
+
+// Regexp defines a regular expression flag with specified name, default
+// value, and usage string. The return value is the address of a
+// *regexp.Regexp variable that stores the compiled value of the flag.
+func Regexp(name string, value *regexp.Regexp, usage string, opts ...Option) **regexp.Regexp {
+	var r *regexp.Regexp
+	RegexpVar(&r, name, value, usage, opts...)
+	return &r
+}
+
+// RegexpVar defines a regular expression flag with specified name, default
+// value, and usage string. The argument p points to a *regexp.Regexp variable
+// in which to store the compiled value of the flag.
+func RegexpVar(p **regexp.Regexp, name string, value *regexp.Regexp, usage string, opts ...Option) {
+	RegexpVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// RegexpVarFS is like RegexpVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func RegexpVarFS(fs *flag.FlagSet, p **regexp.Regexp, name string, value *regexp.Regexp, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewRegexpValue(p), name, usage, opts)
+}
+
+// NewRegexpValue returns a RegexpValue storing its value in p.
+func NewRegexpValue(p **regexp.Regexp) *RegexpValue {
+	return &RegexpValue{
+		p: p,
+	}
+}
+
+// RegexpValue holds a regular expression that can be provided via the
+// command line using the syntax accepted by regexp.Compile. The expression is
+// compiled when the flag is set.
+type RegexpValue struct {
+	p **regexp.Regexp
+}
+
+// String implements flag.Value by returning the source text of the regular
+// expression.
+func (r *RegexpValue) String() string {
+	if r.p == nil || *r.p == nil {
+		return ""
+	}
+	return (*r.p).String()
+}
+
+// Set implements flag.Value by compiling the given regular expression.
+func (r *RegexpValue) Set(value string) error {
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression %q: %v", value, err)
+	}
+	*r.p = re
+	return nil
+}
+
+// Type implements pflag.Value by returning "regexp".
+func (r *RegexpValue) Type() string {
+	return "regexp"
+}
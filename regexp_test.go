@@ -0,0 +1,108 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"regexp"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.RegexpValue)(nil)
+
+var regexpTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        *regexp.Regexp
+	expectedStringValue string
+	expectedMatch       string
+	expectedError       string
+}{{
+	about:               "valid expression",
+	name:                "valid",
+	value:               `^bad-(wolf|rose)$`,
+	expectedStringValue: `^bad-(wolf|rose)$`,
+	expectedMatch:       "bad-wolf",
+}, {
+	about:               "empty expression",
+	name:                "empty",
+	expectedStringValue: "",
+	expectedMatch:       "anything",
+}, {
+	about:               "default value: with value",
+	name:                "def1",
+	value:               `^rose$`,
+	defaultValue:        regexp.MustCompile(`^wolf$`),
+	expectedStringValue: `^rose$`,
+	expectedMatch:       "rose",
+}, {
+	about:               "default value: without value",
+	name:                "def2",
+	defaultValue:        regexp.MustCompile(`^wolf$`),
+	expectedStringValue: `^wolf$`,
+	expectedMatch:       "wolf",
+}, {
+	about:         "error: invalid expression",
+	name:          "err",
+	value:         `bad-(wolf`,
+	expectedError: `invalid regular expression "bad-\(wolf": error parsing regexp: missing closing \): .*`,
+}}
+
+func TestRegexp(t *testing.T) {
+	for _, test := range regexpTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Regexp(test.name, test.defaultValue, "regexp usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					c.Assert(*v, qt.IsNil)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert((*v).String(), qt.Equals, test.expectedStringValue)
+			c.Assert((*v).MatchString(test.expectedMatch), qt.Equals, true)
+		})
+	}
+}
+
+func TestRegexpVar(t *testing.T) {
+	for _, test := range regexpTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v *regexp.Regexp
+			flagutils.RegexpVar(&v, test.name, test.defaultValue, "regexp usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					c.Assert(v, qt.IsNil)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+			c.Assert(v.MatchString(test.expectedMatch), qt.Equals, true)
+		})
+	}
+}
+
+func TestRegexpValueString(t *testing.T) {
+	for _, test := range regexpTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v *regexp.Regexp
+			r := flagutils.NewRegexpValue(&v)
+			c.Assert(r.String(), qt.Equals, "")
+			r.Set(test.value)
+			c.Assert(r.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
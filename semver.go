@@ -0,0 +1,200 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version holds a semantic version, as defined by https://semver.org.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+// ParseVersion parses the given semantic version string, for instance
+// "1.2.3" or "2.0.0-rc.1+build.5". A leading "v" is allowed.
+func ParseVersion(s string) (Version, error) {
+	invalid := fmt.Errorf("invalid semantic version %q", s)
+	rest := strings.TrimPrefix(s, "v")
+	var v Version
+	if i := strings.IndexByte(rest, '+'); i != -1 {
+		rest, v.Build = rest[:i], rest[i+1:]
+		if !validIdentifiers(v.Build, false) {
+			return Version{}, invalid
+		}
+	}
+	if i := strings.IndexByte(rest, '-'); i != -1 {
+		rest, v.Prerelease = rest[:i], rest[i+1:]
+		if !validIdentifiers(v.Prerelease, true) {
+			return Version{}, invalid
+		}
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, invalid
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		if !isNumeric(part) {
+			return Version{}, invalid
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, invalid
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// String returns the version as a string, without a leading "v".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 depending on whether v precedes, is equal to or
+// follows w. Build metadata is ignored, as mandated by the specification.
+func (v Version) Compare(w Version) int {
+	for _, c := range [][2]int{{v.Major, w.Major}, {v.Minor, w.Minor}, {v.Patch, w.Patch}} {
+		if c[0] != c[1] {
+			return cmp.Compare(c[0], c[1])
+		}
+	}
+	switch {
+	case v.Prerelease == w.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case w.Prerelease == "":
+		return -1
+	}
+	vids, wids := strings.Split(v.Prerelease, "."), strings.Split(w.Prerelease, ".")
+	for i := 0; i < len(vids) && i < len(wids); i++ {
+		a, b := vids[i], wids[i]
+		if a == b {
+			continue
+		}
+		an, bn := isNumeric(a), isNumeric(b)
+		switch {
+		case an && bn:
+			if len(a) != len(b) {
+				return cmp.Compare(len(a), len(b))
+			}
+			return strings.Compare(a, b)
+		case an:
+			return -1
+		case bn:
+			return 1
+		}
+		return strings.Compare(a, b)
+	}
+	return cmp.Compare(len(vids), len(wids))
+}
+
+// Less reports whether v precedes w.
+func (v Version) Less(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+// Semver defines a semantic version flag with specified name, default value,
+// and usage string. The return value is the address of a Version variable
+// that stores the value of the flag.
+func Semver(name string, value Version, usage string, opts ...Option) *Version {
+	var v Version
+	SemverVar(&v, name, value, usage, opts...)
+	return &v
+}
+
+// SemverVar defines a semantic version flag with specified name, default
+// value, and usage string. The argument p points to a Version variable in
+// which to store the value of the flag.
+func SemverVar(p *Version, name string, value Version, usage string, opts ...Option) {
+	SemverVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// SemverVarFS is like SemverVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func SemverVarFS(fs *flag.FlagSet, p *Version, name string, value Version, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*SemverValue)(p), name, usage, opts)
+}
+
+// SemverValue holds a semantic version that can be provided via the command
+// line, for instance "1.2.3" or "v2.0.0-rc.1".
+type SemverValue Version
+
+// String implements flag.Value by returning the version as a string.
+func (v *SemverValue) String() string {
+	return Version(*v).String()
+}
+
+// Set implements flag.Value by parsing the given semantic version.
+func (v *SemverValue) Set(value string) error {
+	version, err := ParseVersion(value)
+	if err != nil {
+		return err
+	}
+	*v = SemverValue(version)
+	return nil
+}
+
+// Type implements pflag.Value by returning "semver".
+func (v *SemverValue) Type() string {
+	return "semver"
+}
+
+// validIdentifiers reports whether s is a non-empty list of dot separated
+// identifiers composed of alphanumerics and hyphens. If prerelease is true,
+// numeric identifiers must not include leading zeros.
+func validIdentifiers(s string, prerelease bool) bool {
+	if s == "" {
+		return false
+	}
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, r := range id {
+			if !(r == '-' || '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+				return false
+			}
+		}
+		if prerelease && isDigits(id) && !isNumeric(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumeric reports whether s is a number without leading zeros.
+func isNumeric(s string) bool {
+	return isDigits(s) && (len(s) == 1 || s[0] != '0')
+}
+
+// isDigits reports whether s is a non-empty string of digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.SemverValue)(nil)
+
+var semverTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        flagutils.Version
+	expectedValue       flagutils.Version
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "release",
+	name:                "release",
+	value:               "1.2.3",
+	expectedValue:       flagutils.Version{Major: 1, Minor: 2, Patch: 3},
+	expectedStringValue: "1.2.3",
+}, {
+	about:               "leading v",
+	name:                "v",
+	value:               "v10.0.1",
+	expectedValue:       flagutils.Version{Major: 10, Patch: 1},
+	expectedStringValue: "10.0.1",
+}, {
+	about: "pre-release and build metadata",
+	name:  "prerelease",
+	value: "2.0.0-rc.1+build-5.sha",
+	expectedValue: flagutils.Version{
+		Major:      2,
+		Prerelease: "rc.1",
+		Build:      "build-5.sha",
+	},
+	expectedStringValue: "2.0.0-rc.1+build-5.sha",
+}, {
+	about:               "hyphen in pre-release",
+	name:                "hyphen",
+	value:               "1.0.0-alpha-beta",
+	expectedValue:       flagutils.Version{Major: 1, Prerelease: "alpha-beta"},
+	expectedStringValue: "1.0.0-alpha-beta",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "1.2.3",
+	defaultValue:  flagutils.Version{Major: 1},
+	expectedValue: flagutils.Version{Major: 1, Minor: 2, Patch: 3},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  flagutils.Version{Major: 1},
+	expectedValue: flagutils.Version{Major: 1},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid semantic version ""`,
+}, {
+	about:         "error: missing patch",
+	name:          "err",
+	value:         "1.2",
+	expectedError: `invalid semantic version "1.2"`,
+}, {
+	about:         "error: leading zeros",
+	name:          "err",
+	value:         "1.02.3",
+	expectedError: `invalid semantic version "1.02.3"`,
+}, {
+	about:         "error: numeric pre-release with leading zeros",
+	name:          "err",
+	value:         "1.2.3-01",
+	expectedError: `invalid semantic version "1.2.3-01"`,
+}, {
+	about:         "error: empty pre-release identifier",
+	name:          "err",
+	value:         "1.2.3-rc..1",
+	expectedError: `invalid semantic version "1.2.3-rc..1"`,
+}, {
+	about:         "error: empty build metadata",
+	name:          "err",
+	value:         "1.2.3+",
+	expectedError: `invalid semantic version "1.2.3\+"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "latest",
+	defaultValue:  flagutils.Version{Major: 1},
+	expectedValue: flagutils.Version{Major: 1},
+	expectedError: `invalid semantic version "latest"`,
+}}
+
+func TestSemver(t *testing.T) {
+	for _, test := range semverTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Semver(test.name, test.defaultValue, "semver usage")
+			if test.value != "" || test.defaultValue == (flagutils.Version{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestSemverVar(t *testing.T) {
+	for _, test := range semverTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.Version
+			flagutils.SemverVar(&v, test.name, test.defaultValue, "semver usage")
+			if test.value != "" || test.defaultValue == (flagutils.Version{}) {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestSemverValueString(t *testing.T) {
+	for _, test := range semverTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != (flagutils.Version{}) || test.expectedError != "" {
+				return
+			}
+			var v flagutils.SemverValue
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+var versionCompareTests = []struct {
+	v, w     string
+	expected int
+}{
+	{"1.0.0", "1.0.0", 0},
+	{"1.0.0", "2.0.0", -1},
+	{"2.1.0", "2.0.9", 1},
+	{"1.0.10", "1.0.9", 1},
+	{"1.0.0-alpha", "1.0.0", -1},
+	{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+	{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+	{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+	{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+	{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+	{"1.0.0+build.1", "1.0.0+build.2", 0},
+}
+
+func TestVersionCompare(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range versionCompareTests {
+		v, err := flagutils.ParseVersion(test.v)
+		c.Assert(err, qt.Equals, nil)
+		w, err := flagutils.ParseVersion(test.w)
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(v.Compare(w), qt.Equals, test.expected, qt.Commentf("%s vs %s", test.v, test.w))
+		c.Assert(w.Compare(v), qt.Equals, -test.expected, qt.Commentf("%s vs %s", test.w, test.v))
+		c.Assert(v.Less(w), qt.Equals, test.expected < 0)
+	}
+}
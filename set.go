@@ -0,0 +1,125 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"encoding/json"
+	"flag"
+	"strings"
+)
+
+// StringSet defines a string set flag with specified name, default value, and
+// usage string. The return value is the address of a StringSetValue variable
+// that stores the value of the flag.
+func StringSet(name string, value []string, usage string, opts ...Option) *StringSetValue {
+	var s StringSetValue
+	StringSetVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// StringSetVar defines a string set flag with specified name, default value,
+// and usage string. The argument p points to a StringSetValue variable in
+// which to store the value of the flag. Repeated values are dropped from a
+// copy of the default value, so that the flag value is always a set.
+func StringSetVar(p *StringSetValue, name string, value []string, usage string, opts ...Option) {
+	StringSetVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// StringSetVarFS is like StringSetVar, but defines the flag in the given flag
+// set rather than in the default command line flag set.
+func StringSetVarFS(fs *flag.FlagSet, p *StringSetValue, name string, value []string, usage string, opts ...Option) {
+	*p = nil
+	if value != nil {
+		*p = make(StringSetValue, 0, len(value))
+		p.setValues(value)
+	}
+	defineVar(fs, p, name, usage, opts)
+}
+
+// StringSetValue holds a set of strings that can be provided via the command
+// line as a comma separated list of values. Repeated values are silently dropped,
+// and the order in which values are first provided is preserved.
+type StringSetValue []string
+
+// Contains reports whether the set includes the given value.
+func (s StringSetValue) Contains(value string) bool {
+	for _, v := range s {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String implements flag.Value by returning the set as a string.
+func (s *StringSetValue) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set implements flag.Value by populating the set from the given comma
+// separated value.
+func (s *StringSetValue) Set(value string) error {
+	*s = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	s.setValues(values)
+	return nil
+}
+
+// Type implements pflag.Value by returning "stringSet".
+func (s *StringSetValue) Type() string {
+	return "stringSet"
+}
+
+// MarshalText implements encoding.TextMarshaler by returning the set as a
+// comma separated list of values.
+func (s StringSetValue) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by populating the set
+// from the given comma separated value. An empty value results in a nil set.
+func (s *StringSetValue) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = nil
+		return nil
+	}
+	return s.Set(string(text))
+}
+
+// MarshalJSON implements json.Marshaler by encoding the set as a JSON array.
+func (s StringSetValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler by decoding either a JSON array
+// of strings or a JSON string holding a comma separated list of values.
+// Repeated values are silently dropped.
+func (s *StringSetValue) UnmarshalJSON(data []byte) error {
+	values, err := unmarshalJSONList(data)
+	if err != nil {
+		return err
+	}
+	*s = nil
+	s.setValues(values)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler by encoding the set as a YAML
+// sequence.
+func (s StringSetValue) MarshalYAML() (interface{}, error) {
+	return []string(s), nil
+}
+
+// setValues adds the given values to the set, dropping repeated ones.
+func (s *StringSetValue) setValues(values []string) {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			*s = append(*s, v)
+		}
+	}
+}
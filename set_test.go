@@ -0,0 +1,147 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.StringSetValue)(nil)
+
+var setTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []string
+	expectedValue       flagutils.StringSetValue
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single string",
+	name:                "single",
+	value:               "exterminate",
+	expectedValue:       flagutils.StringSetValue{"exterminate"},
+	expectedStringValue: "exterminate",
+}, {
+	about:               "multiple strings",
+	name:                "multiple",
+	value:               "these,are,the,voyages",
+	expectedValue:       flagutils.StringSetValue{"these", "are", "the", "voyages"},
+	expectedStringValue: "these,are,the,voyages",
+}, {
+	about:               "duplicates",
+	name:                "duplicates",
+	value:               "bad,wolf, bad ,wolf,rose",
+	expectedValue:       flagutils.StringSetValue{"bad", "wolf", "rose"},
+	expectedStringValue: "bad,wolf,rose",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "exterminate",
+	defaultValue:  []string{"default", "not", "used"},
+	expectedValue: flagutils.StringSetValue{"exterminate"},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []string{"default", "used"},
+	expectedValue: flagutils.StringSetValue{"default", "used"},
+}, {
+	about:         "default value: with repeated values",
+	name:          "def3",
+	defaultValue:  []string{"default", "used", "default"},
+	expectedValue: flagutils.StringSetValue{"default", "used"},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: multiple values with empty string",
+	name:          "err",
+	value:         "bad,,wolf",
+	expectedError: "cannot include empty strings in the list",
+}}
+
+func TestStringSet(t *testing.T) {
+	for _, test := range setTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.StringSet(test.name, test.defaultValue, "set usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestStringSetVar(t *testing.T) {
+	for _, test := range setTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.StringSetValue
+			flagutils.StringSetVar(&v, test.name, test.defaultValue, "set usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestStringSetValueString(t *testing.T) {
+	for _, test := range setTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.StringSetValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestStringSetValueContains(t *testing.T) {
+	c := qt.New(t)
+	var v flagutils.StringSetValue
+	err := v.Set("bad,wolf,bad")
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(v.Contains("bad"), qt.Equals, true)
+	c.Assert(v.Contains("wolf"), qt.Equals, true)
+	c.Assert(v.Contains("rose"), qt.Equals, false)
+	c.Assert(v.Contains(""), qt.Equals, false)
+}
+
+func TestStringSetValueJSON(t *testing.T) {
+	c := qt.New(t)
+	var conf struct {
+		Set   flagutils.StringSetValue `json:"set"`
+		Names flagutils.StringSetValue `json:"names"`
+	}
+	err := json.Unmarshal([]byte(`{"set": ["b", "a", "b"], "names": "c,d,c"}`), &conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf.Set, qt.DeepEquals, flagutils.StringSetValue{"b", "a"})
+	c.Assert(conf.Names, qt.DeepEquals, flagutils.StringSetValue{"c", "d"})
+
+	b, err := json.Marshal(conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(b), qt.Equals, `{"set":["b","a"],"names":["c","d"]}`)
+
+	text, err := conf.Set.MarshalText()
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(text), qt.Equals, "b,a")
+}
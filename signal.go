@@ -0,0 +1,89 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Signal defines an OS signal flag with specified name, default value, and
+// usage string. The value is provided via the command line as a signal name,
+// with or without the "SIG" prefix and case insensitively, as in "TERM" or
+// "sighup", or as a signal number. The return value is the address of an
+// os.Signal variable that stores the value of the flag.
+func Signal(name string, value os.Signal, usage string, opts ...Option) *os.Signal {
+	var s os.Signal
+	SignalVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// SignalVar defines an OS signal flag with specified name, default value,
+// and usage string. See Signal for details on the accepted values. The
+// argument p points to an os.Signal variable in which to store the value of
+// the flag.
+func SignalVar(p *os.Signal, name string, value os.Signal, usage string, opts ...Option) {
+	SignalVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// SignalVarFS is like SignalVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func SignalVarFS(fs *flag.FlagSet, p *os.Signal, name string, value os.Signal, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewSignalValue(p), name, usage, opts)
+}
+
+// NewSignalValue returns a SignalValue storing its value in p.
+func NewSignalValue(p *os.Signal) *SignalValue {
+	return &SignalValue{
+		p: p,
+	}
+}
+
+// SignalValue holds an OS signal that can be provided via the command line
+// by name or number. The set of supported signal names depends on the
+// platform.
+type SignalValue struct {
+	p *os.Signal
+}
+
+// String implements flag.Value by returning the signal name, as in
+// "SIGTERM".
+func (s *SignalValue) String() string {
+	if s.p == nil || *s.p == nil {
+		return ""
+	}
+	for name, sig := range signals {
+		if sig == *s.p {
+			return "SIG" + name
+		}
+	}
+	return (*s.p).String()
+}
+
+// Set implements flag.Value by parsing the given signal name or number.
+func (s *SignalValue) Set(value string) error {
+	if n, err := strconv.Atoi(value); err == nil {
+		sig := signalFromNumber(n)
+		if sig == nil {
+			return fmt.Errorf("invalid signal %q", value)
+		}
+		*s.p = sig
+		return nil
+	}
+	name := strings.TrimPrefix(strings.ToUpper(value), "SIG")
+	sig, ok := signals[name]
+	if !ok {
+		return fmt.Errorf("invalid signal %q", value)
+	}
+	*s.p = sig
+	return nil
+}
+
+// Type implements pflag.Value by returning "signal".
+func (s *SignalValue) Type() string {
+	return "signal"
+}
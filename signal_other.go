@@ -0,0 +1,19 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+//go:build !unix && !windows
+
+package flagutils
+
+import "os"
+
+// signals maps signal names, without the "SIG" prefix, to signals.
+var signals = map[string]os.Signal{
+	"INT":  os.Interrupt,
+	"KILL": os.Kill,
+}
+
+// signalFromNumber returns nil, as signal numbers are not supported on this
+// platform.
+func signalFromNumber(n int) os.Signal {
+	return nil
+}
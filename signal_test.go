@@ -0,0 +1,134 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+//go:build unix
+
+package flagutils_test
+
+import (
+	"flag"
+	"os"
+	"syscall"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.SignalValue)(nil)
+
+var signalTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        os.Signal
+	expectedValue       os.Signal
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "name",
+	name:                "name",
+	value:               "TERM",
+	expectedValue:       syscall.SIGTERM,
+	expectedStringValue: "SIGTERM",
+}, {
+	about:               "name with prefix",
+	name:                "prefix",
+	value:               "SIGHUP",
+	expectedValue:       syscall.SIGHUP,
+	expectedStringValue: "SIGHUP",
+}, {
+	about:               "lowercase name",
+	name:                "lower",
+	value:               "sigusr1",
+	expectedValue:       syscall.SIGUSR1,
+	expectedStringValue: "SIGUSR1",
+}, {
+	about:               "number",
+	name:                "number",
+	value:               "9",
+	expectedValue:       syscall.SIGKILL,
+	expectedStringValue: "SIGKILL",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "INT",
+	defaultValue:  syscall.SIGTERM,
+	expectedValue: syscall.SIGINT,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  syscall.SIGTERM,
+	expectedValue: syscall.SIGTERM,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid signal ""`,
+}, {
+	about:         "error: unknown name",
+	name:          "err",
+	value:         "SIGWOLF",
+	expectedError: `invalid signal "SIGWOLF"`,
+}, {
+	about:         "error: invalid number",
+	name:          "err",
+	value:         "0",
+	expectedError: `invalid signal "0"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "-1",
+	defaultValue:  syscall.SIGTERM,
+	expectedValue: syscall.SIGTERM,
+	expectedError: `invalid signal "-1"`,
+}}
+
+func TestSignal(t *testing.T) {
+	for _, test := range signalTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Signal(test.name, test.defaultValue, "signal usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestSignalVar(t *testing.T) {
+	for _, test := range signalTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v os.Signal
+			flagutils.SignalVar(&v, test.name, test.defaultValue, "signal usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestSignalValueString(t *testing.T) {
+	for _, test := range signalTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil || test.expectedError != "" {
+				return
+			}
+			var v os.Signal
+			s := flagutils.NewSignalValue(&v)
+			err := s.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(s.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
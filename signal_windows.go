@@ -0,0 +1,34 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"os"
+	"syscall"
+)
+
+// signals maps signal names, without the "SIG" prefix, to signals.
+var signals = map[string]os.Signal{
+	"ABRT": syscall.SIGABRT,
+	"ALRM": syscall.SIGALRM,
+	"BUS":  syscall.SIGBUS,
+	"FPE":  syscall.SIGFPE,
+	"HUP":  syscall.SIGHUP,
+	"ILL":  syscall.SIGILL,
+	"INT":  syscall.SIGINT,
+	"KILL": syscall.SIGKILL,
+	"PIPE": syscall.SIGPIPE,
+	"QUIT": syscall.SIGQUIT,
+	"SEGV": syscall.SIGSEGV,
+	"TERM": syscall.SIGTERM,
+	"TRAP": syscall.SIGTRAP,
+}
+
+// signalFromNumber returns the signal with the given number, or nil if the
+// number is not valid.
+func signalFromNumber(n int) os.Signal {
+	if n < 1 || n > int(syscall.SIGTERM) {
+		return nil
+	}
+	return syscall.Signal(n)
+}
@@ -0,0 +1,87 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SliceOf defines a slice flag with specified name, element parsing function,
+// default value and usage string. Each comma separated element provided via
+// the command line is converted using the given parse function. The return
+// value is the address of a slice variable that stores the value of the flag.
+func SliceOf[T any](name string, parse func(string) (T, error), value []T, usage string, opts ...Option) *[]T {
+	var s []T
+	SliceOfVar(&s, name, parse, value, usage, opts...)
+	return &s
+}
+
+// SliceOfVar defines a slice flag with specified name, element parsing
+// function, default value and usage string. The argument p points to a slice
+// variable in which to store the value of the flag.
+func SliceOfVar[T any](p *[]T, name string, parse func(string) (T, error), value []T, usage string, opts ...Option) {
+	SliceOfVarFS(flag.CommandLine, p, name, parse, value, usage, opts...)
+}
+
+// SliceOfVarFS is like SliceOfVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func SliceOfVarFS[T any](fs *flag.FlagSet, p *[]T, name string, parse func(string) (T, error), value []T, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewSliceOfValue(p, parse), name, usage, opts)
+}
+
+// NewSliceOfValue returns a SliceOfValue storing its value in p and using the
+// given function to parse each element.
+func NewSliceOfValue[T any](p *[]T, parse func(string) (T, error)) *SliceOfValue[T] {
+	return &SliceOfValue[T]{
+		p:     p,
+		parse: parse,
+	}
+}
+
+// SliceOfValue holds a slice of values of an arbitrary type that can be
+// provided via the command line as a comma separated list.
+type SliceOfValue[T any] struct {
+	p     *[]T
+	parse func(string) (T, error)
+}
+
+// String implements flag.Value by returning the slice as a string. Elements
+// are formatted using their default format.
+func (s *SliceOfValue[T]) String() string {
+	if s.p == nil {
+		return ""
+	}
+	values := make([]string, len(*s.p))
+	for i, v := range *s.p {
+		values[i] = fmt.Sprint(v)
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *SliceOfValue[T]) Set(value string) error {
+	*s.p = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	ts := make([]T, len(values))
+	for i, v := range values {
+		if ts[i], err = s.parse(v); err != nil {
+			return fmt.Errorf("invalid value %q: %v", v, err)
+		}
+	}
+	*s.p = ts
+	return nil
+}
+
+// Type implements pflag.Value by returning the slice type, for instance
+// "[]int".
+func (s *SliceOfValue[T]) Type() string {
+	return reflect.TypeOf((*[]T)(nil)).Elem().String()
+}
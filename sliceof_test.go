@@ -0,0 +1,106 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"strconv"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.SliceOfValue[int])(nil)
+
+var sliceOfTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []int
+	expectedValue       []int
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single value",
+	name:                "single",
+	value:               "42",
+	expectedValue:       []int{42},
+	expectedStringValue: "42",
+}, {
+	about:               "multiple values",
+	name:                "multiple",
+	value:               "1, 2,-3 ,42",
+	expectedValue:       []int{1, 2, -3, 42},
+	expectedStringValue: "1,2,-3,42",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "42",
+	defaultValue:  []int{1, 2},
+	expectedValue: []int{42},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []int{1, 2},
+	expectedValue: []int{1, 2},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: parse failure",
+	name:          "err",
+	value:         "1,two,3",
+	expectedError: `invalid value "two": strconv.Atoi: parsing "two": invalid syntax`,
+}}
+
+func TestSliceOf(t *testing.T) {
+	for _, test := range sliceOfTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.SliceOf(test.name, strconv.Atoi, test.defaultValue, "slice of usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestSliceOfVar(t *testing.T) {
+	for _, test := range sliceOfTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []int
+			flagutils.SliceOfVar(&v, test.name, strconv.Atoi, test.defaultValue, "slice of usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestSliceOfValueString(t *testing.T) {
+	for _, test := range sliceOfTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v []int
+			s := flagutils.NewSliceOfValue(&v, strconv.Atoi)
+			s.Set(test.value)
+			c.Assert(s.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
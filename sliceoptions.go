@@ -0,0 +1,482 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SliceOption configures the behavior of flags defined with Slice and
+// SliceVar. In addition to the options defined in this file, the options
+// returned by Required, Env and Hidden can be used.
+type SliceOption interface {
+	applySliceOption(*sliceOptions)
+}
+
+// sliceOption implements SliceOption with a function.
+type sliceOption func(*sliceOptions)
+
+// applySliceOption implements SliceOption.
+func (f sliceOption) applySliceOption(o *sliceOptions) {
+	f(o)
+}
+
+// sliceOptions holds the configuration of a string slice flag.
+type sliceOptions struct {
+	sep        string
+	accumulate bool
+	noTrim     bool
+	csv        bool
+	escape     bool
+	minLen     int
+	maxLen     int
+	extend     bool
+	validators []func(string) error
+	transforms []func(string) string
+	lines      bool
+	removal    bool
+	flag       flagOptions
+}
+
+// Separator returns an option making string slice flags split their value
+// using the given separator rather than commas, for instance ";" or ":".
+// This is useful when elements can include commas, as in SQL fragments.
+func Separator(sep string) SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.sep = sep
+	})
+}
+
+// Accumulate returns an option making repeated occurrences of a string slice
+// flag append to the slice rather than replacing it, so that for instance
+// "-tag a -tag b,c" results in ["a", "b", "c"]. The first occurrence
+// replaces the default value.
+func Accumulate() SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.accumulate = true
+	})
+}
+
+// NoTrim returns an option preventing string slice flags from removing
+// leading and trailing spaces from their elements, for when spaces are
+// significant. Elements including only spaces are allowed in this case.
+func NoTrim() SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.noTrim = true
+	})
+}
+
+// CSV returns an option making string slice flags parse their value using
+// encoding/csv semantics, so that separators are only recognized outside
+// double quoted elements. For instance, `"Smith, John","Doe, Jane"` results
+// in ["Smith, John", "Doe, Jane"]. Elements are quoted as required when the
+// value is printed. When combined with Separator, the separator must be a
+// single character.
+func CSV() SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.csv = true
+	})
+}
+
+// Escape returns an option allowing separators to be included in string
+// slice elements by escaping them with a backslash. For instance, `a\,b,c`
+// results in ["a,b", "c"]. A literal backslash preceding a separator can be
+// escaped as `\\`. Separators and backslashes are escaped when the value is
+// printed. The option is ignored if CSV is also provided.
+func Escape() SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.escape = true
+	})
+}
+
+// ExtendDefault returns an option making the value provided for string slice
+// flags extend the default value rather than replacing it. For instance,
+// with default ["stdout"], "-outputs file" results in ["stdout", "file"].
+// The default can still be replaced by providing "!reset" as the first
+// element, as in "-outputs !reset,file", or cleared by providing "!reset"
+// alone.
+func ExtendDefault() SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.extend = true
+	})
+}
+
+// AllowRemoval returns an option making string slice flags support removing
+// elements from the default value, by prefixing them with "-". The option
+// implies ExtendDefault, so that for instance, with default ["a", "b", "c"],
+// "-b,d" results in ["a", "c", "d"]. When Accumulate is also provided,
+// elements are removed from the accumulated value. Removing an element which
+// is not included in the list is an error.
+func AllowRemoval() SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.extend = true
+		o.removal = true
+	})
+}
+
+// MinLen returns an option making string slice flags require at least n
+// elements. When Accumulate is also provided, the minimum applies to the
+// accumulated value, and it is checked by Parse and FlagSet.Parse once all
+// the occurrences of the flag have been parsed.
+func MinLen(n int) SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.minLen = n
+	})
+}
+
+// MaxLen returns an option making string slice flags accept at most n
+// elements, with n greater than zero. When Accumulate is also provided, the
+// maximum applies to the accumulated value.
+func MaxLen(n int) SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.maxLen = n
+	})
+}
+
+// SplitLines returns an option making string slice flags also split their
+// value on newlines, which is convenient when values are read from files or
+// environment variables. Trailing blank lines are ignored. When CSV is also
+// provided, newlines always separate records, and blank lines are ignored.
+func SplitLines() SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.lines = true
+	})
+}
+
+// Transform returns an option making string slice flags normalize each
+// provided element using the given function, for instance strings.ToLower,
+// os.ExpandEnv or filepath.Clean. The function is called after spaces are
+// trimmed and before elements are validated. When the option is provided
+// multiple times, functions are applied in order.
+func Transform(transform func(string) string) SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.transforms = append(o.transforms, transform)
+	})
+}
+
+// ValidateElements returns an option making string slice flags validate each
+// provided element using the given function, so that invalid elements are
+// reported as flag parsing errors. Errors are wrapped in an *ElementError
+// identifying the offending element. When the option is provided multiple
+// times, or with MatchElements, all validations are applied in order.
+func ValidateElements(validate func(string) error) SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.validators = append(o.validators, validate)
+	})
+}
+
+// MatchElements returns an option making string slice flags require each
+// provided element to match the given regular expression, for instance
+// `^[a-z0-9-]+$` for DNS labels. Non-matching elements are reported as
+// *ElementError values.
+func MatchElements(re *regexp.Regexp) SliceOption {
+	return ValidateElements(func(s string) error {
+		if !re.MatchString(s) {
+			return fmt.Errorf("does not match %q", re)
+		}
+		return nil
+	})
+}
+
+// ElementError records an error about an element of a string slice flag
+// value, where Index is the zero based position of the element in the
+// provided list.
+type ElementError struct {
+	Index int
+	Value string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("invalid element %d (%q): %v", e.Index, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// Len returns an option making string slice flags require exactly n
+// elements, for instance for RGB triples. It is equivalent to providing both
+// MinLen(n) and MaxLen(n): when Accumulate is also provided, exceeding n
+// elements is reported as soon as the offending occurrence is parsed, while
+// providing fewer elements is reported by Parse and FlagSet.Parse.
+func Len(n int) SliceOption {
+	return sliceOption(func(o *sliceOptions) {
+		o.minLen, o.maxLen = n, n
+	})
+}
+
+// newSliceValue returns a sliceValue storing its value in p and configured
+// with the given options.
+func newSliceValue(p *StringSlice, opts []SliceOption) *sliceValue {
+	v := &sliceValue{
+		p:   p,
+		def: *p,
+	}
+	for _, opt := range opts {
+		opt.applySliceOption(&v.opts)
+	}
+	if v.opts.sep == "" {
+		v.opts.sep = ","
+	}
+	if v.opts.csv && utf8.RuneCountInString(v.opts.sep) != 1 {
+		panic(fmt.Sprintf("flagutils: invalid separator %q: CSV requires a single character", v.opts.sep))
+	}
+	return v
+}
+
+// sliceValue implements flag.Value for string slices configured with
+// options.
+type sliceValue struct {
+	p    *StringSlice
+	def  StringSlice
+	opts sliceOptions
+	set  bool
+}
+
+// String implements flag.Value by returning the slice as a string.
+func (v *sliceValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+	if v.opts.csv {
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		w.Comma, _ = utf8.DecodeRuneInString(v.opts.sep)
+		w.Write(*v.p)
+		w.Flush()
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+	if v.opts.escape {
+		r := strings.NewReplacer(`\`, `\\`, v.opts.sep, `\`+v.opts.sep)
+		values := make([]string, len(*v.p))
+		for i, e := range *v.p {
+			values[i] = r.Replace(e)
+		}
+		return strings.Join(values, v.opts.sep)
+	}
+	return strings.Join(*v.p, v.opts.sep)
+}
+
+// Set implements flag.Value by populating the slice from the given value
+// according to the configured options. The current value is left untouched
+// if an error occurs.
+func (v *sliceValue) Set(value string) error {
+	var values StringSlice
+	var reset bool
+	if v.opts.extend && value == resetElement {
+		reset = true
+	} else {
+		if rest, ok := strings.CutPrefix(value, resetElement+v.opts.sep); ok && v.opts.extend {
+			value, reset = rest, true
+		}
+		var err error
+		if values, err = v.split(value); err != nil {
+			return err
+		}
+	}
+	var base StringSlice
+	switch {
+	case reset:
+	case v.opts.accumulate && v.set:
+		base = *v.p
+	case v.opts.extend:
+		base = v.def
+	}
+	result := append(StringSlice(nil), base...)
+	for _, e := range values {
+		if name, ok := strings.CutPrefix(e, removalPrefix); ok && v.opts.removal {
+			var err error
+			if result, err = removeElement(result, name); err != nil {
+				return err
+			}
+			continue
+		}
+		result = append(result, e)
+	}
+	if err := v.checkLen(len(result)); err != nil {
+		return err
+	}
+	*v.p = result
+	v.set = true
+	return nil
+}
+
+// Type implements pflag.Value by returning "stringSlice".
+func (v *sliceValue) Type() string {
+	return "stringSlice"
+}
+
+// split splits the given value into its elements according to the configured
+// options. An error is returned if any of the elements is empty.
+func (v *sliceValue) split(value string) (StringSlice, error) {
+	var elements []string
+	if v.opts.csv {
+		r := csv.NewReader(strings.NewReader(value))
+		r.Comma, _ = utf8.DecodeRuneInString(v.opts.sep)
+		r.TrimLeadingSpace = !v.opts.noTrim
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse CSV: %v", err)
+		}
+		if len(records) == 0 {
+			return nil, errors.New("cannot include empty strings in the list")
+		}
+		for _, record := range records {
+			elements = append(elements, record...)
+		}
+	} else {
+		lines := []string{value}
+		if v.opts.lines {
+			lines = splitLines(value)
+		}
+		for _, line := range lines {
+			if v.opts.escape {
+				elements = append(elements, splitEscaped(line, v.opts.sep)...)
+			} else {
+				elements = append(elements, strings.Split(line, v.opts.sep)...)
+			}
+		}
+	}
+	var values StringSlice
+	for _, e := range elements {
+		if !v.opts.noTrim {
+			e = strings.TrimSpace(e)
+		}
+		for _, transform := range v.opts.transforms {
+			e = transform(e)
+		}
+		name := e
+		if v.opts.removal {
+			name = strings.TrimPrefix(e, removalPrefix)
+		}
+		if name == "" {
+			return nil, errors.New("cannot include empty strings in the list")
+		}
+		for _, validate := range v.opts.validators {
+			if err := validate(name); err != nil {
+				return nil, &ElementError{
+					Index: len(values),
+					Value: e,
+					Err:   err,
+				}
+			}
+		}
+		values = append(values, e)
+	}
+	return values, nil
+}
+
+// resetElement is used as the first element of string slice flags configured
+// with ExtendDefault in order to replace the default value.
+const resetElement = "!reset"
+
+// removalPrefix is used to mark elements to be removed from string slice
+// flags configured with AllowRemoval.
+const removalPrefix = "-"
+
+// removeElement returns the given slice without any occurrence of the
+// element. An error is returned if the element is not found.
+func removeElement(s StringSlice, element string) (StringSlice, error) {
+	result := s[:0]
+	for _, e := range s {
+		if e != element {
+			result = append(result, e)
+		}
+	}
+	if len(result) == len(s) {
+		return nil, fmt.Errorf("cannot remove %q: element not found", element)
+	}
+	return result, nil
+}
+
+// checkLen returns an error if the given number of elements is out of the
+// configured bounds. When Accumulate is provided, the minimum is checked by
+// checkParsed instead, as later occurrences of the flag can still add
+// elements.
+func (v *sliceValue) checkLen(n int) error {
+	lo := v.opts.minLen
+	if v.opts.accumulate {
+		lo = 0
+	}
+	return checkBounds(n, lo, v.opts.maxLen)
+}
+
+// checkParsed implements parsedChecker by checking that the value
+// accumulated from all the occurrences of the flag includes at least the
+// configured minimum number of elements.
+func (v *sliceValue) checkParsed() error {
+	if !v.opts.accumulate || !v.set {
+		return nil
+	}
+	return checkBounds(len(*v.p), v.opts.minLen, v.opts.maxLen)
+}
+
+// checkBounds returns an error if the given number of elements is out of
+// the given bounds, where zero means no bound.
+func checkBounds(n, lo, hi int) error {
+	switch {
+	case lo > 0 && lo == hi && n != lo:
+		return fmt.Errorf("invalid list: expected exactly %s, got %d", pluralize(lo, "element"), n)
+	case lo > 0 && hi > 0 && (n < lo || n > hi):
+		return fmt.Errorf("invalid list: expected between %d and %d elements, got %d", lo, hi, n)
+	case lo > 0 && n < lo:
+		return fmt.Errorf("invalid list: expected at least %s, got %d", pluralize(lo, "element"), n)
+	case hi > 0 && n > hi:
+		return fmt.Errorf("invalid list: expected at most %s, got %d", pluralize(hi, "element"), n)
+	}
+	return nil
+}
+
+// pluralize returns the given count followed by the noun, in plural form if
+// required.
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// splitLines splits the given value into lines, ignoring trailing blank
+// lines. Both "\n" and "\r\n" line endings are supported.
+func splitLines(value string) []string {
+	lines := strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")
+	for len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitEscaped splits the given value on separators not preceded by a
+// backslash, unescaping separators and backslashes in the resulting elements.
+// Backslashes not followed by a separator or another backslash are preserved.
+func splitEscaped(value, sep string) []string {
+	var elements []string
+	var b strings.Builder
+	for i := 0; i < len(value); {
+		switch {
+		case strings.HasPrefix(value[i:], `\`+sep):
+			b.WriteString(sep)
+			i += 1 + len(sep)
+		case strings.HasPrefix(value[i:], `\\`):
+			b.WriteByte('\\')
+			i += 2
+		case strings.HasPrefix(value[i:], sep):
+			elements = append(elements, b.String())
+			b.Reset()
+			i += len(sep)
+		default:
+			b.WriteByte(value[i])
+			i++
+		}
+	}
+	return append(elements, b.String())
+}
@@ -0,0 +1,543 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var sliceOptionsTests = []struct {
+	about               string
+	values              []string
+	opts                []flagutils.SliceOption
+	defaultValue        []string
+	expectedValue       flagutils.StringSlice
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "no options: comma separated values",
+	values:              []string{"a, b,c"},
+	expectedValue:       flagutils.StringSlice{"a", "b", "c"},
+	expectedStringValue: "a,b,c",
+}, {
+	about:               "separator: semicolon",
+	values:              []string{"name = 'Smith, John'; age > 42"},
+	opts:                []flagutils.SliceOption{flagutils.Separator(";")},
+	expectedValue:       flagutils.StringSlice{"name = 'Smith, John'", "age > 42"},
+	expectedStringValue: "name = 'Smith, John';age > 42",
+}, {
+	about:               "separator: multiple characters",
+	values:              []string{"a, b || c"},
+	opts:                []flagutils.SliceOption{flagutils.Separator("||")},
+	expectedValue:       flagutils.StringSlice{"a, b", "c"},
+	expectedStringValue: "a, b||c",
+}, {
+	about:               "separator: empty defaults to comma",
+	values:              []string{"a,b"},
+	opts:                []flagutils.SliceOption{flagutils.Separator("")},
+	expectedValue:       flagutils.StringSlice{"a", "b"},
+	expectedStringValue: "a,b",
+}, {
+	about:         "separator: empty element",
+	values:        []string{"/bin::/usr/bin"},
+	opts:          []flagutils.SliceOption{flagutils.Separator(":")},
+	defaultValue:  []string{"/bin"},
+	expectedValue: flagutils.StringSlice{"/bin"},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "no options: repeated flags replace the value",
+	values:        []string{"a,b", "c"},
+	expectedValue: flagutils.StringSlice{"c"},
+}, {
+	about:               "accumulate: repeated flags",
+	values:              []string{"a", "b,c", "a"},
+	opts:                []flagutils.SliceOption{flagutils.Accumulate()},
+	expectedValue:       flagutils.StringSlice{"a", "b", "c", "a"},
+	expectedStringValue: "a,b,c,a",
+}, {
+	about:         "accumulate: default value replaced",
+	values:        []string{"a", "b"},
+	opts:          []flagutils.SliceOption{flagutils.Accumulate()},
+	defaultValue:  []string{"x", "y"},
+	expectedValue: flagutils.StringSlice{"a", "b"},
+}, {
+	about:               "accumulate: with separator",
+	values:              []string{"a,b;c", "d"},
+	opts:                []flagutils.SliceOption{flagutils.Accumulate(), flagutils.Separator(";")},
+	expectedValue:       flagutils.StringSlice{"a,b", "c", "d"},
+	expectedStringValue: "a,b;c;d",
+}, {
+	about:         "accumulate: error",
+	values:        []string{"a", "b,", "c"},
+	opts:          []flagutils.SliceOption{flagutils.Accumulate()},
+	expectedValue: flagutils.StringSlice{"a"},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:               "no trim: spaces preserved",
+	values:              []string{" ERROR:, WARN ,  "},
+	opts:                []flagutils.SliceOption{flagutils.NoTrim()},
+	expectedValue:       flagutils.StringSlice{" ERROR:", " WARN ", "  "},
+	expectedStringValue: " ERROR:, WARN ,  ",
+}, {
+	about:         "no trim: empty element",
+	values:        []string{"a,,b"},
+	opts:          []flagutils.SliceOption{flagutils.NoTrim()},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:               "csv: quoted elements",
+	values:              []string{`"Smith, John", "Doe, Jane",Roe`},
+	opts:                []flagutils.SliceOption{flagutils.CSV()},
+	expectedValue:       flagutils.StringSlice{"Smith, John", "Doe, Jane", "Roe"},
+	expectedStringValue: `"Smith, John","Doe, Jane",Roe`,
+}, {
+	about:               "csv: escaped quotes",
+	values:              []string{`"say ""hello""",bye`},
+	opts:                []flagutils.SliceOption{flagutils.CSV()},
+	expectedValue:       flagutils.StringSlice{`say "hello"`, "bye"},
+	expectedStringValue: `"say ""hello""",bye`,
+}, {
+	about:               "csv: with separator and accumulate",
+	values:              []string{`a;"b;c"`, "d"},
+	opts:                []flagutils.SliceOption{flagutils.CSV(), flagutils.Separator(";"), flagutils.Accumulate()},
+	expectedValue:       flagutils.StringSlice{"a", "b;c", "d"},
+	expectedStringValue: `a;"b;c";d`,
+}, {
+	about:         "csv: empty element",
+	values:        []string{`a,"",b`},
+	opts:          []flagutils.SliceOption{flagutils.CSV()},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "csv: empty string",
+	values:        []string{""},
+	opts:          []flagutils.SliceOption{flagutils.CSV()},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "csv: invalid quotes",
+	values:        []string{`a,b"c`},
+	opts:          []flagutils.SliceOption{flagutils.CSV()},
+	expectedError: `cannot parse CSV: parse error on line 1, .*bare " in non-quoted-field`,
+}, {
+	about:               "escape: escaped separators",
+	values:              []string{`a\,b,c`},
+	opts:                []flagutils.SliceOption{flagutils.Escape()},
+	expectedValue:       flagutils.StringSlice{"a,b", "c"},
+	expectedStringValue: `a\,b,c`,
+}, {
+	about:               "escape: backslashes",
+	values:              []string{`C:\dir,a\\,b\\\,c`},
+	opts:                []flagutils.SliceOption{flagutils.Escape()},
+	expectedValue:       flagutils.StringSlice{`C:\dir`, `a\`, `b\,c`},
+	expectedStringValue: `C:\\dir,a\\,b\\\,c`,
+}, {
+	about:               "escape: with separator",
+	values:              []string{`a\;b;c,d`},
+	opts:                []flagutils.SliceOption{flagutils.Escape(), flagutils.Separator(";")},
+	expectedValue:       flagutils.StringSlice{"a;b", "c,d"},
+	expectedStringValue: `a\;b;c,d`,
+}, {
+	about:         "escape: empty element",
+	values:        []string{`a\,,`},
+	opts:          []flagutils.SliceOption{flagutils.Escape()},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "min and max: valid",
+	values:        []string{"a,b"},
+	opts:          []flagutils.SliceOption{flagutils.MinLen(2), flagutils.MaxLen(3)},
+	expectedValue: flagutils.StringSlice{"a", "b"},
+}, {
+	about:         "min and max: too few elements",
+	values:        []string{"a"},
+	opts:          []flagutils.SliceOption{flagutils.MinLen(2), flagutils.MaxLen(3)},
+	defaultValue:  []string{"x", "y"},
+	expectedValue: flagutils.StringSlice{"x", "y"},
+	expectedError: "invalid list: expected between 2 and 3 elements, got 1",
+}, {
+	about:         "min and max: too many elements",
+	values:        []string{"a,b,c,d"},
+	opts:          []flagutils.SliceOption{flagutils.MinLen(2), flagutils.MaxLen(3)},
+	expectedError: "invalid list: expected between 2 and 3 elements, got 4",
+}, {
+	about:         "min: too few elements",
+	values:        []string{"a,b"},
+	opts:          []flagutils.SliceOption{flagutils.MinLen(3)},
+	expectedError: "invalid list: expected at least 3 elements, got 2",
+}, {
+	about:         "max: too many elements",
+	values:        []string{"a,b"},
+	opts:          []flagutils.SliceOption{flagutils.MaxLen(1)},
+	expectedError: "invalid list: expected at most 1 element, got 2",
+}, {
+	about:         "max: with accumulate",
+	values:        []string{"a,b", "c", "d"},
+	opts:          []flagutils.SliceOption{flagutils.MaxLen(3), flagutils.Accumulate()},
+	expectedValue: flagutils.StringSlice{"a", "b", "c"},
+	expectedError: "invalid list: expected at most 3 elements, got 4",
+}, {
+	about:         "min: not checked before parsing with accumulate",
+	values:        []string{"a", "b"},
+	opts:          []flagutils.SliceOption{flagutils.MinLen(2), flagutils.Accumulate()},
+	expectedValue: flagutils.StringSlice{"a", "b"},
+}, {
+	about:         "extend default: value appended to default",
+	values:        []string{"file,syslog"},
+	opts:          []flagutils.SliceOption{flagutils.ExtendDefault()},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice{"stdout", "file", "syslog"},
+}, {
+	about:         "extend default: repeated flags replace the extension",
+	values:        []string{"file", "syslog"},
+	opts:          []flagutils.SliceOption{flagutils.ExtendDefault()},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice{"stdout", "syslog"},
+}, {
+	about:         "extend default: with accumulate",
+	values:        []string{"file", "syslog"},
+	opts:          []flagutils.SliceOption{flagutils.ExtendDefault(), flagutils.Accumulate()},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice{"stdout", "file", "syslog"},
+}, {
+	about:         "extend default: reset",
+	values:        []string{"!reset,file"},
+	opts:          []flagutils.SliceOption{flagutils.ExtendDefault()},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice{"file"},
+}, {
+	about:         "extend default: reset only",
+	values:        []string{"!reset"},
+	opts:          []flagutils.SliceOption{flagutils.ExtendDefault()},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice(nil),
+}, {
+	about:         "extend default: reset with accumulate",
+	values:        []string{"file", "!reset;syslog", "tcp"},
+	opts:          []flagutils.SliceOption{flagutils.ExtendDefault(), flagutils.Accumulate(), flagutils.Separator(";")},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice{"syslog", "tcp"},
+}, {
+	about:         "extend default: reset not at the beginning",
+	values:        []string{"file,!reset"},
+	opts:          []flagutils.SliceOption{flagutils.ExtendDefault()},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice{"stdout", "file", "!reset"},
+}, {
+	about:         "extend default: reset ignored without option",
+	values:        []string{"!reset,file"},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice{"!reset", "file"},
+}, {
+	about:         "extend default: error",
+	values:        []string{"!reset,"},
+	opts:          []flagutils.SliceOption{flagutils.ExtendDefault()},
+	defaultValue:  []string{"stdout"},
+	expectedValue: flagutils.StringSlice{"stdout"},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "validate elements: valid",
+	values:        []string{"web-1,db-2"},
+	opts:          []flagutils.SliceOption{flagutils.ValidateElements(validateHost)},
+	expectedValue: flagutils.StringSlice{"web-1", "db-2"},
+}, {
+	about:         "validate elements: invalid element",
+	values:        []string{"web-1,DB,db-2"},
+	opts:          []flagutils.SliceOption{flagutils.ValidateElements(validateHost)},
+	defaultValue:  []string{"localhost"},
+	expectedValue: flagutils.StringSlice{"localhost"},
+	expectedError: `invalid element 1 \("DB"\): must be lower case`,
+}, {
+	about:         "validate elements: default not validated",
+	values:        []string{"db-2"},
+	opts:          []flagutils.SliceOption{flagutils.ValidateElements(validateHost), flagutils.ExtendDefault()},
+	defaultValue:  []string{"LOCALHOST"},
+	expectedValue: flagutils.StringSlice{"LOCALHOST", "db-2"},
+}, {
+	about:         "transform: lower case",
+	values:        []string{"Web-1, DB-2"},
+	opts:          []flagutils.SliceOption{flagutils.Transform(strings.ToLower)},
+	expectedValue: flagutils.StringSlice{"web-1", "db-2"},
+}, {
+	about:         "transform: multiple functions applied in order",
+	values:        []string{"a/../B/,./c//d"},
+	opts:          []flagutils.SliceOption{flagutils.Transform(path.Clean), flagutils.Transform(strings.ToUpper)},
+	expectedValue: flagutils.StringSlice{"B", "C/D"},
+}, {
+	about:         "transform: before validation",
+	values:        []string{"Web-1,DB-2"},
+	opts:          []flagutils.SliceOption{flagutils.ValidateElements(validateHost), flagutils.Transform(strings.ToLower)},
+	expectedValue: flagutils.StringSlice{"web-1", "db-2"},
+}, {
+	about:         "transform: empty result",
+	values:        []string{"a,$UNDEFINED_VARIABLE"},
+	opts:          []flagutils.SliceOption{flagutils.Transform(os.ExpandEnv)},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "split lines: newline separated values",
+	values:        []string{"a\nb,c\r\nd\n\n  \n"},
+	opts:          []flagutils.SliceOption{flagutils.SplitLines()},
+	expectedValue: flagutils.StringSlice{"a", "b", "c", "d"},
+}, {
+	about:         "split lines: blank line in the middle",
+	values:        []string{"a\n\nb"},
+	opts:          []flagutils.SliceOption{flagutils.SplitLines()},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "split lines: with escape",
+	values:        []string{"a\\,b\nc"},
+	opts:          []flagutils.SliceOption{flagutils.SplitLines(), flagutils.Escape()},
+	expectedValue: flagutils.StringSlice{"a,b", "c"},
+}, {
+	about:         "split lines: newlines not split without option",
+	values:        []string{"a\nb,c"},
+	expectedValue: flagutils.StringSlice{"a\nb", "c"},
+}, {
+	about:         "split lines: only blank lines",
+	values:        []string{"\n\n"},
+	opts:          []flagutils.SliceOption{flagutils.SplitLines()},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "match elements: valid",
+	values:        []string{"web-1,db-2"},
+	opts:          []flagutils.SliceOption{flagutils.MatchElements(regexp.MustCompile(`^[a-z0-9-]+$`))},
+	expectedValue: flagutils.StringSlice{"web-1", "db-2"},
+}, {
+	about:         "match elements: non-matching element",
+	values:        []string{"web-1,db_2"},
+	opts:          []flagutils.SliceOption{flagutils.MatchElements(regexp.MustCompile(`^[a-z0-9-]+$`))},
+	expectedError: `invalid element 1 \("db_2"\): does not match "\^\[a-z0-9-\]\+\$"`,
+}, {
+	about:  "match elements: with validation",
+	values: []string{"web-1,DB-2"},
+	opts: []flagutils.SliceOption{
+		flagutils.MatchElements(regexp.MustCompile(`^[a-zA-Z0-9-]+$`)),
+		flagutils.ValidateElements(validateHost),
+	},
+	expectedError: `invalid element 1 \("DB-2"\): must be lower case`,
+}, {
+	about:         "len: exact count",
+	values:        []string{"255,128,0"},
+	opts:          []flagutils.SliceOption{flagutils.Len(3)},
+	expectedValue: flagutils.StringSlice{"255", "128", "0"},
+}, {
+	about:         "len: too few elements",
+	values:        []string{"255,128"},
+	opts:          []flagutils.SliceOption{flagutils.Len(3)},
+	expectedError: "invalid list: expected exactly 3 elements, got 2",
+}, {
+	about:         "len: too many elements",
+	values:        []string{"a,b"},
+	opts:          []flagutils.SliceOption{flagutils.Len(1)},
+	expectedError: "invalid list: expected exactly 1 element, got 2",
+}, {
+	about:         "len: with accumulate",
+	values:        []string{"a", "b", "c"},
+	opts:          []flagutils.SliceOption{flagutils.Len(2), flagutils.Accumulate()},
+	expectedValue: flagutils.StringSlice{"a", "b"},
+	expectedError: "invalid list: expected at most 2 elements, got 3",
+}, {
+	about:         "allow removal: remove and add",
+	values:        []string{"-b,d"},
+	opts:          []flagutils.SliceOption{flagutils.AllowRemoval()},
+	defaultValue:  []string{"a", "b", "c"},
+	expectedValue: flagutils.StringSlice{"a", "c", "d"},
+}, {
+	about:         "allow removal: remove all occurrences",
+	values:        []string{"-a"},
+	opts:          []flagutils.SliceOption{flagutils.AllowRemoval()},
+	defaultValue:  []string{"a", "b", "a"},
+	expectedValue: flagutils.StringSlice{"b"},
+}, {
+	about:         "allow removal: remove added element",
+	values:        []string{"d,e,-d"},
+	opts:          []flagutils.SliceOption{flagutils.AllowRemoval()},
+	defaultValue:  []string{"a"},
+	expectedValue: flagutils.StringSlice{"a", "e"},
+}, {
+	about:         "allow removal: with accumulate",
+	values:        []string{"-a", "d", "-b"},
+	opts:          []flagutils.SliceOption{flagutils.AllowRemoval(), flagutils.Accumulate()},
+	defaultValue:  []string{"a", "b", "c"},
+	expectedValue: flagutils.StringSlice{"c", "d"},
+}, {
+	about:         "allow removal: with validation",
+	values:        []string{"-b,D"},
+	opts:          []flagutils.SliceOption{flagutils.AllowRemoval(), flagutils.ValidateElements(validateHost)},
+	defaultValue:  []string{"a", "b"},
+	expectedValue: flagutils.StringSlice{"a", "b"},
+	expectedError: `invalid element 1 \("D"\): must be lower case`,
+}, {
+	about:         "allow removal: element not found",
+	values:        []string{"-x"},
+	opts:          []flagutils.SliceOption{flagutils.AllowRemoval()},
+	defaultValue:  []string{"a", "b"},
+	expectedValue: flagutils.StringSlice{"a", "b"},
+	expectedError: `cannot remove "x": element not found`,
+}, {
+	about:         "allow removal: empty element",
+	values:        []string{"a,-"},
+	opts:          []flagutils.SliceOption{flagutils.AllowRemoval()},
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "allow removal: prefix preserved without option",
+	values:        []string{"-b,d"},
+	defaultValue:  []string{"a", "b", "c"},
+	expectedValue: flagutils.StringSlice{"-b", "d"},
+}}
+
+func validateHost(s string) error {
+	if strings.ToLower(s) != s {
+		return errors.New("must be lower case")
+	}
+	return nil
+}
+
+func TestSliceOptions(t *testing.T) {
+	for _, test := range sliceOptionsTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Slice("list", test.defaultValue, "slice usage", test.opts...)
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set("list", value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+			if test.expectedStringValue != "" {
+				c.Assert(flag.Lookup("list").Value.String(), qt.Equals, test.expectedStringValue)
+			}
+		})
+	}
+}
+
+var sliceAccumulateLenTests = []struct {
+	about         string
+	args          []string
+	opts          []flagutils.SliceOption
+	expectedValue flagutils.StringSlice
+	expectedError string
+}{{
+	about:         "len: exact",
+	args:          []string{"-rgb", "1", "-rgb", "2,3"},
+	opts:          []flagutils.SliceOption{flagutils.Len(3)},
+	expectedValue: flagutils.StringSlice{"1", "2", "3"},
+}, {
+	about:         "len: too few elements",
+	args:          []string{"-rgb", "1"},
+	opts:          []flagutils.SliceOption{flagutils.Len(3)},
+	expectedValue: flagutils.StringSlice{"1"},
+	expectedError: "invalid value for flag -rgb: invalid list: expected exactly 3 elements, got 1",
+}, {
+	about:         "len: too many elements",
+	args:          []string{"-rgb", "1,2", "-rgb", "3,4"},
+	opts:          []flagutils.SliceOption{flagutils.Len(3)},
+	expectedValue: flagutils.StringSlice{"1", "2"},
+	expectedError: `invalid value "3,4" for flag -rgb: invalid list: expected at most 3 elements, got 4`,
+}, {
+	about:         "min: enough elements",
+	args:          []string{"-rgb", "1", "-rgb", "2"},
+	opts:          []flagutils.SliceOption{flagutils.MinLen(2)},
+	expectedValue: flagutils.StringSlice{"1", "2"},
+}, {
+	about:         "min: too few elements",
+	args:          []string{"-rgb", "1"},
+	opts:          []flagutils.SliceOption{flagutils.MinLen(2), flagutils.MaxLen(4)},
+	expectedValue: flagutils.StringSlice{"1"},
+	expectedError: "invalid value for flag -rgb: invalid list: expected between 2 and 4 elements, got 1",
+}, {
+	about:         "min: not provided",
+	opts:          []flagutils.SliceOption{flagutils.MinLen(2)},
+	expectedValue: flagutils.StringSlice{"0"},
+}, {
+	about:         "min: provided via the environment",
+	opts:          []flagutils.SliceOption{flagutils.MinLen(2), flagutils.Env("FLAGUTILS_RGB")},
+	expectedValue: flagutils.StringSlice{"4", "5"},
+}}
+
+func TestSliceOptionsAccumulateLen(t *testing.T) {
+	t.Setenv("FLAGUTILS_RGB", "4,5")
+	c := qt.New(t)
+	for _, test := range sliceAccumulateLenTests {
+		c.Run(test.about, func(c *qt.C) {
+			fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+			fs.SetOutput(new(bytes.Buffer))
+			opts := append([]flagutils.SliceOption{flagutils.Accumulate()}, test.opts...)
+			v := fs.Slice("rgb", []string{"0"}, "the color", opts...)
+			err := fs.Parse(test.args)
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestSliceOptionsCSVInvalidSeparator(t *testing.T) {
+	runIsolated(t, "invalid separator", func(c *qt.C) {
+		c.Assert(func() {
+			flagutils.Slice("list", nil, "slice usage", flagutils.CSV(), flagutils.Separator("||"))
+		}, qt.PanicMatches, `flagutils: invalid separator "\|\|": CSV requires a single character`)
+	})
+}
+
+func TestSliceOptionsEscapeRoundTrip(t *testing.T) {
+	runIsolated(t, "round trip", func(c *qt.C) {
+		v := flagutils.Slice("list", nil, "slice usage", flagutils.Escape())
+		values := flagutils.StringSlice{`a,b`, `c\`, `\,`, `d\e`}
+		*v = values
+		err := flag.Set("list", flag.Lookup("list").Value.String())
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(*v, qt.DeepEquals, values)
+	})
+}
+
+func TestSliceExtendDefaultDoesNotModifyDefault(t *testing.T) {
+	runIsolated(t, "default not modified", func(c *qt.C) {
+		defaultValue := make([]string, 1, 10)
+		defaultValue[0] = "stdout"
+		flagutils.Slice("list", defaultValue, "slice usage", flagutils.ExtendDefault(), flagutils.Accumulate())
+		err := flag.Set("list", "file")
+		c.Assert(err, qt.Equals, nil)
+		err = flag.Set("list", "syslog")
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(defaultValue[:2], qt.DeepEquals, []string{"stdout", ""})
+	})
+}
+
+func TestElementErrorUnwrap(t *testing.T) {
+	c := qt.New(t)
+	err := errors.New("bad wolf")
+	var eerr error = &flagutils.ElementError{
+		Index: 2,
+		Value: "rose",
+		Err:   err,
+	}
+	c.Assert(eerr, qt.ErrorMatches, `invalid element 2 \("rose"\): bad wolf`)
+	c.Assert(errors.Is(eerr, err), qt.Equals, true)
+}
+
+func TestSliceAllowRemovalDoesNotModifyDefault(t *testing.T) {
+	runIsolated(t, "default not modified", func(c *qt.C) {
+		defaultValue := []string{"a", "b", "c"}
+		v := flagutils.Slice("list", defaultValue, "slice usage", flagutils.AllowRemoval())
+		err := flag.Set("list", "-a")
+		c.Assert(err, qt.Equals, nil)
+		c.Assert(*v, qt.DeepEquals, flagutils.StringSlice{"b", "c"})
+		c.Assert(defaultValue, qt.DeepEquals, []string{"a", "b", "c"})
+	})
+}
@@ -0,0 +1,108 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"encoding/json"
+	"flag"
+	"sort"
+	"strings"
+)
+
+// SortedSlice defines a sorted string slice flag with specified name, default
+// value, and usage string. The return value is the address of a
+// SortedStringSlice variable that stores the value of the flag.
+func SortedSlice(name string, value []string, usage string, opts ...Option) *SortedStringSlice {
+	var s SortedStringSlice
+	SortedSliceVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// SortedSliceVar defines a sorted string slice flag with specified name,
+// default value, and usage string. The argument p points to a
+// SortedStringSlice variable in which to store the value of the flag. The
+// default value is copied and sorted, so that the flag value is always
+// sorted.
+func SortedSliceVar(p *SortedStringSlice, name string, value []string, usage string, opts ...Option) {
+	SortedSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// SortedSliceVarFS is like SortedSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func SortedSliceVarFS(fs *flag.FlagSet, p *SortedStringSlice, name string, value []string, usage string, opts ...Option) {
+	*p = nil
+	if value != nil {
+		*p = append(SortedStringSlice{}, value...)
+		sort.Strings(*p)
+	}
+	defineVar(fs, p, name, usage, opts)
+}
+
+// SortedStringSlice holds a sorted slice of strings that can be provided via
+// the command line as a comma separated list of values.
+type SortedStringSlice []string
+
+// String implements flag.Value by returning the slice as a string.
+func (s *SortedStringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value, and then sorting it.
+func (s *SortedStringSlice) Set(value string) error {
+	*s = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	sort.Strings(values)
+	*s = values
+	return nil
+}
+
+// Type implements pflag.Value by returning "sortedStringSlice".
+func (s *SortedStringSlice) Type() string {
+	return "sortedStringSlice"
+}
+
+// MarshalText implements encoding.TextMarshaler by returning the slice as a
+// comma separated list of values.
+func (s SortedStringSlice) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by populating the slice
+// from the given comma separated value, and then sorting it. An empty value
+// results in a nil slice.
+func (s *SortedStringSlice) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = nil
+		return nil
+	}
+	return s.Set(string(text))
+}
+
+// MarshalJSON implements json.Marshaler by encoding the slice as a JSON
+// array.
+func (s SortedStringSlice) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler by decoding either a JSON array
+// of strings or a JSON string holding a comma separated list of values, and
+// then sorting the slice.
+func (s *SortedStringSlice) UnmarshalJSON(data []byte) error {
+	values, err := unmarshalJSONList(data)
+	if err != nil {
+		return err
+	}
+	sort.Strings(values)
+	*s = values
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler by encoding the slice as a YAML
+// sequence.
+func (s SortedStringSlice) MarshalYAML() (interface{}, error) {
+	return []string(s), nil
+}
@@ -0,0 +1,141 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.SortedStringSlice)(nil)
+
+var sortedSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []string
+	expectedValue       flagutils.SortedStringSlice
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single string",
+	name:                "single",
+	value:               "exterminate",
+	expectedValue:       flagutils.SortedStringSlice{"exterminate"},
+	expectedStringValue: "exterminate",
+}, {
+	about:               "multiple strings",
+	name:                "multiple",
+	value:               "these,are,the,voyages",
+	expectedValue:       flagutils.SortedStringSlice{"are", "the", "these", "voyages"},
+	expectedStringValue: "are,the,these,voyages",
+}, {
+	about:               "weird formatting",
+	name:                "weird",
+	value:               "  these , are,the,  voyages ",
+	expectedValue:       flagutils.SortedStringSlice{"are", "the", "these", "voyages"},
+	expectedStringValue: "are,the,these,voyages",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "exterminate",
+	defaultValue:  []string{"default", "not", "used"},
+	expectedValue: flagutils.SortedStringSlice{"exterminate"},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []string{"default", "used", "and", "sorted"},
+	expectedValue: flagutils.SortedStringSlice{"and", "default", "sorted", "used"},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: multiple values with empty string",
+	name:          "err",
+	value:         ",bad,wolf",
+	expectedError: "cannot include empty strings in the list",
+}}
+
+func TestSortedSlice(t *testing.T) {
+	for _, test := range sortedSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.SortedSlice(test.name, test.defaultValue, "sorted slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestSortedSliceVar(t *testing.T) {
+	for _, test := range sortedSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.SortedStringSlice
+			flagutils.SortedSliceVar(&v, test.name, test.defaultValue, "sorted slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestSortedSliceVarDoesNotModifyDefault(t *testing.T) {
+	runIsolated(t, "default not modified", func(c *qt.C) {
+		defaultValue := []string{"these", "are", "the", "voyages"}
+		var v flagutils.SortedStringSlice
+		flagutils.SortedSliceVar(&v, "voyages", defaultValue, "sorted slice usage")
+		c.Assert(v, qt.DeepEquals, flagutils.SortedStringSlice{"are", "the", "these", "voyages"})
+		c.Assert(defaultValue, qt.DeepEquals, []string{"these", "are", "the", "voyages"})
+	})
+}
+
+func TestSortedStringSliceString(t *testing.T) {
+	for _, test := range sortedSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.SortedStringSlice
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestSortedStringSliceJSON(t *testing.T) {
+	c := qt.New(t)
+	var conf struct {
+		Tags  flagutils.SortedStringSlice `json:"tags"`
+		Names flagutils.SortedStringSlice `json:"names"`
+	}
+	err := json.Unmarshal([]byte(`{"tags": ["b", "a"], "names": "d,c"}`), &conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf.Tags, qt.DeepEquals, flagutils.SortedStringSlice{"a", "b"})
+	c.Assert(conf.Names, qt.DeepEquals, flagutils.SortedStringSlice{"c", "d"})
+
+	b, err := json.Marshal(conf)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(string(b), qt.Equals, `{"tags":["a","b"],"names":["c","d"]}`)
+
+	err = conf.Tags.UnmarshalText([]byte("z,y"))
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf.Tags, qt.DeepEquals, flagutils.SortedStringSlice{"y", "z"})
+}
@@ -0,0 +1,134 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Flatten returns a new map in which nested maps are replaced by their
+// entries, keyed by their dot separated path. For instance,
+// `{"flags": {"profile": true}, "url": "https://1.2.3.4"}` is flattened to
+// `{"flags.profile": true, "url": "https://1.2.3.4"}`. Empty nested maps are
+// preserved as values, and other values, including slices, are stored as
+// they are.
+func (s *StringMap) Flatten() map[string]interface{} {
+	flat := make(map[string]interface{})
+	flatten(flat, *s, "")
+	return flat
+}
+
+// flatten stores the entries of m in flat, prefixing their keys with the
+// given dot separated path.
+func flatten(flat, m map[string]interface{}, prefix string) {
+	for key, v := range m {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch nested := v.(type) {
+		case map[string]interface{}:
+			if len(nested) != 0 {
+				flatten(flat, nested, key)
+				continue
+			}
+		case StringMap:
+			if len(nested) != 0 {
+				flatten(flat, nested, key)
+				continue
+			}
+		}
+		flat[key] = v
+	}
+}
+
+// SetPath stores v in the string map at the given dot separated path,
+// creating nested maps as required. For instance, setting "flags.profile" to
+// true results in `{"flags": {"profile": true}}`. An error is returned if
+// the path includes empty keys or if it traverses a value which is not a map.
+func (s *StringMap) SetPath(path string, v interface{}) error {
+	if *s == nil {
+		*s = make(StringMap)
+	}
+	return setPath(*s, path, v)
+}
+
+// GetString returns the string stored at the given dot separated path, or
+// the given default value if the path is not found or the value is not a
+// string.
+func (s *StringMap) GetString(path string, def string) string {
+	if v, ok := s.get(path).(string); ok {
+		return v
+	}
+	return def
+}
+
+// GetInt returns the integer stored at the given dot separated path, or the
+// given default value if the path is not found or the value is not an
+// integer. JSON numbers are accepted if they have no fractional part and fit
+// in an int.
+func (s *StringMap) GetInt(path string, def int) int {
+	switch v := s.get(path).(type) {
+	case int:
+		return v
+	case int64:
+		if v >= math.MinInt && v <= math.MaxInt {
+			return int(v)
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt && v < -math.MinInt {
+			return int(v)
+		}
+	case json.Number:
+		if n, err := strconv.Atoi(v.String()); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// GetBool returns the boolean stored at the given dot separated path, or the
+// given default value if the path is not found or the value is not a
+// boolean.
+func (s *StringMap) GetBool(path string, def bool) bool {
+	if v, ok := s.get(path).(bool); ok {
+		return v
+	}
+	return def
+}
+
+// GetDuration returns the duration stored at the given dot separated path,
+// or the given default value if the path is not found or the value is not a
+// duration. Strings are parsed like Duration flag values, so that the "d" and
+// "w" units are also accepted.
+func (s *StringMap) GetDuration(path string, def time.Duration) time.Duration {
+	switch v := s.get(path).(type) {
+	case time.Duration:
+		return v
+	case string:
+		if d, err := parseDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// get returns the value stored at the given dot separated path, or nil if
+// the path is not found.
+func (s *StringMap) get(path string) interface{} {
+	var v interface{} = map[string]interface{}(*s)
+	for _, key := range strings.Split(path, ".") {
+		switch m := v.(type) {
+		case map[string]interface{}:
+			v = m[key]
+		case StringMap:
+			v = m[key]
+		default:
+			return nil
+		}
+	}
+	return v
+}
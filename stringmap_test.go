@@ -0,0 +1,243 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var flattenTests = []struct {
+	about         string
+	value         flagutils.StringMap
+	expectedValue map[string]interface{}
+}{{
+	about:         "nil map",
+	expectedValue: map[string]interface{}{},
+}, {
+	about: "flat map",
+	value: flagutils.StringMap{
+		"gisf": true,
+		"url":  "https://1.2.3.4",
+	},
+	expectedValue: map[string]interface{}{
+		"gisf": true,
+		"url":  "https://1.2.3.4",
+	},
+}, {
+	about: "nested maps",
+	value: flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"profile": true,
+			"trace": map[string]interface{}{
+				"level": 2.0,
+			},
+		},
+		"tags": []interface{}{"a", "b"},
+		"db": flagutils.StringMap{
+			"host": "localhost",
+		},
+	},
+	expectedValue: map[string]interface{}{
+		"flags.profile":     true,
+		"flags.trace.level": 2.0,
+		"tags":              []interface{}{"a", "b"},
+		"db.host":           "localhost",
+	},
+}, {
+	about: "empty nested map",
+	value: flagutils.StringMap{
+		"flags": map[string]interface{}{},
+	},
+	expectedValue: map[string]interface{}{
+		"flags": map[string]interface{}{},
+	},
+}}
+
+func TestStringMapFlatten(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range flattenTests {
+		c.Run(test.about, func(c *qt.C) {
+			c.Assert(test.value.Flatten(), qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+var setPathTests = []struct {
+	about         string
+	value         flagutils.StringMap
+	path          string
+	v             interface{}
+	expectedValue flagutils.StringMap
+	expectedError string
+}{{
+	about: "nil map",
+	path:  "gisf",
+	v:     true,
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+	},
+}, {
+	about: "new nested maps",
+	value: flagutils.StringMap{
+		"url": "https://1.2.3.4",
+	},
+	path: "flags.trace.level",
+	v:    2,
+	expectedValue: flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"trace": map[string]interface{}{
+				"level": 2,
+			},
+		},
+		"url": "https://1.2.3.4",
+	},
+}, {
+	about: "existing nested map",
+	value: flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+		"db": flagutils.StringMap{
+			"host": "localhost",
+		},
+	},
+	path: "db.port",
+	v:    5432,
+	expectedValue: flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+		"db": flagutils.StringMap{
+			"host": "localhost",
+			"port": 5432,
+		},
+	},
+}, {
+	about: "replace value",
+	value: flagutils.StringMap{
+		"flags": map[string]interface{}{
+			"profile": true,
+		},
+	},
+	path: "flags",
+	v:    "none",
+	expectedValue: flagutils.StringMap{
+		"flags": "none",
+	},
+}, {
+	about: "error: empty key",
+	value: flagutils.StringMap{
+		"gisf": true,
+	},
+	path: "flags..profile",
+	v:    true,
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+	},
+	expectedError: `invalid path "flags..profile": empty key`,
+}, {
+	about: "error: not a map",
+	value: flagutils.StringMap{
+		"flags": "none",
+	},
+	path: "flags.profile",
+	v:    true,
+	expectedValue: flagutils.StringMap{
+		"flags": "none",
+	},
+	expectedError: `cannot set "flags.profile": "flags" is not a map`,
+}}
+
+func TestStringMapSetPath(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range setPathTests {
+		c.Run(test.about, func(c *qt.C) {
+			v := test.value
+			err := v.SetPath(test.path, test.v)
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+var getterMap = flagutils.StringMap{
+	"name":    "gisf",
+	"retries": 3.0,
+	"ratio":   0.5,
+	"big":     1e100,
+	"count":   json.Number("42"),
+	"debug":   true,
+	"timeout": "1m30s",
+	"flags": map[string]interface{}{
+		"profile": true,
+		"trace": flagutils.StringMap{
+			"level": 2,
+		},
+	},
+	"delay": 5 * time.Second,
+}
+
+func TestStringMapGetString(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(getterMap.GetString("name", "def"), qt.Equals, "gisf")
+	c.Assert(getterMap.GetString("timeout", "def"), qt.Equals, "1m30s")
+	c.Assert(getterMap.GetString("retries", "def"), qt.Equals, "def")
+	c.Assert(getterMap.GetString("no-such", "def"), qt.Equals, "def")
+	c.Assert(getterMap.GetString("name.nested", "def"), qt.Equals, "def")
+}
+
+func TestStringMapGetInt(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(getterMap.GetInt("retries", -1), qt.Equals, 3)
+	c.Assert(getterMap.GetInt("count", -1), qt.Equals, 42)
+	c.Assert(getterMap.GetInt("flags.trace.level", -1), qt.Equals, 2)
+	c.Assert(getterMap.GetInt("ratio", -1), qt.Equals, -1)
+	c.Assert(getterMap.GetInt("big", -1), qt.Equals, -1)
+	c.Assert(getterMap.GetInt("name", -1), qt.Equals, -1)
+	c.Assert(getterMap.GetInt("flags.no-such", -1), qt.Equals, -1)
+}
+
+func TestStringMapGetBool(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(getterMap.GetBool("debug", false), qt.Equals, true)
+	c.Assert(getterMap.GetBool("flags.profile", false), qt.Equals, true)
+	c.Assert(getterMap.GetBool("name", false), qt.Equals, false)
+	c.Assert(getterMap.GetBool("flags.no-such", true), qt.Equals, true)
+}
+
+func TestStringMapGetDuration(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(getterMap.GetDuration("timeout", time.Second), qt.Equals, 90*time.Second)
+	c.Assert(getterMap.GetDuration("delay", time.Second), qt.Equals, 5*time.Second)
+	c.Assert(getterMap.GetDuration("name", time.Second), qt.Equals, time.Second)
+	c.Assert(getterMap.GetDuration("retries", time.Second), qt.Equals, time.Second)
+	c.Assert(getterMap.GetDuration("no-such", time.Second), qt.Equals, time.Second)
+
+	m := flagutils.StringMap{
+		"ttl":       "1d12h",
+		"retention": "2w",
+		"bad":       "1x",
+	}
+	c.Assert(m.GetDuration("ttl", time.Second), qt.Equals, 36*time.Hour)
+	c.Assert(m.GetDuration("retention", time.Second), qt.Equals, 14*24*time.Hour)
+	c.Assert(m.GetDuration("bad", time.Second), qt.Equals, time.Second)
+}
+
+func TestStringMapGettersNilMap(t *testing.T) {
+	c := qt.New(t)
+	var m flagutils.StringMap
+	c.Assert(m.GetString("name", "def"), qt.Equals, "def")
+	c.Assert(m.GetInt("retries", 1), qt.Equals, 1)
+	c.Assert(m.GetBool("debug", true), qt.Equals, true)
+	c.Assert(m.GetDuration("timeout", time.Second), qt.Equals, time.Second)
+}
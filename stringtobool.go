@@ -0,0 +1,87 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StringToBool defines a string to bool map flag with specified name, default
+// value, and usage string. The return value is the address of a map variable
+// that stores the value of the flag.
+func StringToBool(name string, value map[string]bool, usage string, opts ...Option) *map[string]bool {
+	var m map[string]bool
+	StringToBoolVar(&m, name, value, usage, opts...)
+	return &m
+}
+
+// StringToBoolVar defines a string to bool map flag with specified name,
+// default value, and usage string. The argument p points to a map variable in
+// which to store the value of the flag.
+func StringToBoolVar(p *map[string]bool, name string, value map[string]bool, usage string, opts ...Option) {
+	StringToBoolVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// StringToBoolVarFS is like StringToBoolVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func StringToBoolVarFS(fs *flag.FlagSet, p *map[string]bool, name string, value map[string]bool, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*StringToBoolValue)(p), name, usage, opts)
+}
+
+// StringToBoolValue holds a map of strings to booleans that can be provided
+// via the command line as a comma separated list of "key=value" pairs, for
+// instance "gisf=true,profile=false". Values can be expressed as true/false,
+// yes/no, on/off or 1/0.
+type StringToBoolValue map[string]bool
+
+// String implements flag.Value by returning the map as a string, with keys
+// sorted.
+func (m *StringToBoolValue) String() string {
+	return formatPairs(*m, strconv.FormatBool)
+}
+
+// Set implements flag.Value by populating the map from the given comma
+// separated value.
+func (m *StringToBoolValue) Set(value string) error {
+	*m = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	bs := make(map[string]bool, len(values))
+	for _, v := range values {
+		kv, err := parseKeyValue(v)
+		if err != nil {
+			return err
+		}
+		if bs[kv.Key], err = parseBool(kv.Value); err != nil {
+			return err
+		}
+	}
+	*m = bs
+	return nil
+}
+
+// Type implements pflag.Value by returning "stringToBool".
+func (m *StringToBoolValue) Type() string {
+	return "stringToBool"
+}
+
+// formatPairs returns the given map as a comma separated list of "key=value"
+// pairs sorted by key, using the given function to format values.
+func formatPairs[V any](m map[string]V, format func(V) string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + format(m[k])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,114 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.StringToBoolValue)(nil)
+
+var stringToBoolTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        map[string]bool
+	expectedValue       map[string]bool
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single pair",
+	name:                "single",
+	value:               "gisf=true",
+	expectedValue:       map[string]bool{"gisf": true},
+	expectedStringValue: "gisf=true",
+}, {
+	about: "multiple pairs",
+	name:  "multiple",
+	value: "gisf=true,profile=false, status = yes ,debug=off",
+	expectedValue: map[string]bool{
+		"gisf":    true,
+		"profile": false,
+		"status":  true,
+		"debug":   false,
+	},
+	expectedStringValue: "debug=false,gisf=true,profile=false,status=true",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "gisf=true",
+	defaultValue:  map[string]bool{"profile": true},
+	expectedValue: map[string]bool{"gisf": true},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  map[string]bool{"profile": true},
+	expectedValue: map[string]bool{"profile": true},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: missing value",
+	name:          "err",
+	value:         "gisf=true,profile",
+	expectedError: `invalid key=value pair "profile": missing "="`,
+}, {
+	about:         "error: invalid boolean",
+	name:          "err",
+	value:         "gisf=maybe",
+	expectedError: `invalid boolean value "maybe"`,
+}}
+
+func TestStringToBool(t *testing.T) {
+	for _, test := range stringToBoolTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.StringToBool(test.name, test.defaultValue, "string to bool usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestStringToBoolVar(t *testing.T) {
+	for _, test := range stringToBoolTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v map[string]bool
+			flagutils.StringToBoolVar(&v, test.name, test.defaultValue, "string to bool usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestStringToBoolValueString(t *testing.T) {
+	for _, test := range stringToBoolTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.StringToBoolValue
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// StringToFloat64 defines a string to float64 map flag with specified name,
+// default value, and usage string. The return value is the address of a map
+// variable that stores the value of the flag.
+func StringToFloat64(name string, value map[string]float64, usage string, opts ...Option) *map[string]float64 {
+	var m map[string]float64
+	StringToFloat64Var(&m, name, value, usage, opts...)
+	return &m
+}
+
+// StringToFloat64Var defines a string to float64 map flag with specified
+// name, default value, and usage string. The argument p points to a map
+// variable in which to store the value of the flag.
+func StringToFloat64Var(p *map[string]float64, name string, value map[string]float64, usage string, opts ...Option) {
+	StringToFloat64VarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// StringToFloat64VarFS is like StringToFloat64Var, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func StringToFloat64VarFS(fs *flag.FlagSet, p *map[string]float64, name string, value map[string]float64, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, (*StringToFloat64Value)(p), name, usage, opts)
+}
+
+// StringToFloat64Value holds a map of strings to finite floating point
+// numbers that can be provided via the command line as a comma separated list
+// of "key=value" pairs, for instance "search=0.1,checkout=1.0".
+type StringToFloat64Value map[string]float64
+
+// String implements flag.Value by returning the map as a string, with keys
+// sorted.
+func (m *StringToFloat64Value) String() string {
+	return formatPairs(*m, formatFloat)
+}
+
+// Set implements flag.Value by populating the map from the given comma
+// separated value.
+func (m *StringToFloat64Value) Set(value string) error {
+	*m = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	fs := make(map[string]float64, len(values))
+	for _, v := range values {
+		kv, err := parseKeyValue(v)
+		if err != nil {
+			return err
+		}
+		if fs[kv.Key], err = parseFloat(kv.Value); err != nil {
+			return err
+		}
+	}
+	*m = fs
+	return nil
+}
+
+// Type implements pflag.Value by returning "stringToFloat64".
+func (m *StringToFloat64Value) Type() string {
+	return "stringToFloat64"
+}
+
+// parseFloat parses the given value as a finite float64.
+func parseFloat(value string) (float64, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, fmt.Errorf("invalid float value %q", value)
+	}
+	return f, nil
+}
+
+// formatFloat returns the shortest representation of the given float64.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,118 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.StringToFloat64Value)(nil)
+
+var stringToFloat64Tests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        map[string]float64
+	expectedValue       map[string]float64
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single pair",
+	name:                "single",
+	value:               "search=0.1",
+	expectedValue:       map[string]float64{"search": 0.1},
+	expectedStringValue: "search=0.1",
+}, {
+	about: "multiple pairs",
+	name:  "multiple",
+	value: "search=0.1, checkout = 1.0,home=-2.5e-3",
+	expectedValue: map[string]float64{
+		"search":   0.1,
+		"checkout": 1,
+		"home":     -0.0025,
+	},
+	expectedStringValue: "checkout=1,home=-0.0025,search=0.1",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "search=0.1",
+	defaultValue:  map[string]float64{"checkout": 1},
+	expectedValue: map[string]float64{"search": 0.1},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  map[string]float64{"checkout": 1},
+	expectedValue: map[string]float64{"checkout": 1},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: missing value",
+	name:          "err",
+	value:         "search",
+	expectedError: `invalid key=value pair "search": missing "="`,
+}, {
+	about:         "error: invalid float",
+	name:          "err",
+	value:         "search=0.1,checkout=all",
+	expectedError: `invalid float value "all"`,
+}, {
+	about:         "error: not finite",
+	name:          "err",
+	value:         "search=NaN",
+	expectedError: `invalid float value "NaN"`,
+}}
+
+func TestStringToFloat64(t *testing.T) {
+	for _, test := range stringToFloat64Tests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.StringToFloat64(test.name, test.defaultValue, "string to float64 usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestStringToFloat64Var(t *testing.T) {
+	for _, test := range stringToFloat64Tests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v map[string]float64
+			flagutils.StringToFloat64Var(&v, test.name, test.defaultValue, "string to float64 usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestStringToFloat64ValueString(t *testing.T) {
+	for _, test := range stringToFloat64Tests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v flagutils.StringToFloat64Value
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
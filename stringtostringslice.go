@@ -0,0 +1,93 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"sort"
+	"strings"
+)
+
+// StringToStringSlice defines a multimap flag with specified name, default
+// value, and usage string. The return value is the address of a map variable
+// that stores the value of the flag.
+func StringToStringSlice(name string, value map[string][]string, usage string, opts ...Option) *map[string][]string {
+	var m map[string][]string
+	StringToStringSliceVar(&m, name, value, usage, opts...)
+	return &m
+}
+
+// StringToStringSliceVar defines a multimap flag with specified name, default
+// value, and usage string. The argument p points to a map variable in which to
+// store the value of the flag.
+func StringToStringSliceVar(p *map[string][]string, name string, value map[string][]string, usage string, opts ...Option) {
+	StringToStringSliceVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// StringToStringSliceVarFS is like StringToStringSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func StringToStringSliceVarFS(fs *flag.FlagSet, p *map[string][]string, name string, value map[string][]string, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewStringToStringSliceValue(p), name, usage, opts)
+}
+
+// NewStringToStringSliceValue returns a StringToStringSliceValue storing its
+// value in p.
+func NewStringToStringSliceValue(p *map[string][]string) *StringToStringSliceValue {
+	return &StringToStringSliceValue{
+		p: p,
+	}
+}
+
+// StringToStringSliceValue holds a map of strings to string slices that can
+// be provided via the command line by repeating the flag with "key=value"
+// pairs, for instance "-header Accept=json -header Accept=xml". Values for
+// repeated keys are accumulated in the order they are provided. Since values
+// are taken verbatim, they can include commas. The first occurrence of the
+// flag replaces the default value.
+type StringToStringSliceValue struct {
+	p   *map[string][]string
+	set bool
+}
+
+// String implements flag.Value by returning the map as a string, with keys
+// sorted.
+func (m *StringToStringSliceValue) String() string {
+	if m.p == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(*m.p))
+	for k := range *m.p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		for _, v := range (*m.p)[k] {
+			pairs = append(pairs, k+"="+v)
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value by adding the given "key=value" pair to the map.
+func (m *StringToStringSliceValue) Set(value string) error {
+	if !m.set {
+		*m.p = nil
+		m.set = true
+	}
+	kv, err := parseKeyValue(value)
+	if err != nil {
+		return err
+	}
+	if *m.p == nil {
+		*m.p = make(map[string][]string)
+	}
+	(*m.p)[kv.Key] = append((*m.p)[kv.Key], kv.Value)
+	return nil
+}
+
+// Type implements pflag.Value by returning "stringToStringSlice".
+func (m *StringToStringSliceValue) Type() string {
+	return "stringToStringSlice"
+}
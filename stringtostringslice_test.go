@@ -0,0 +1,127 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.StringToStringSliceValue)(nil)
+
+var stringToStringSliceTests = []struct {
+	about               string
+	name                string
+	values              []string
+	defaultValue        map[string][]string
+	expectedValue       map[string][]string
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single pair",
+	name:                "single",
+	values:              []string{"Accept=json"},
+	expectedValue:       map[string][]string{"Accept": {"json"}},
+	expectedStringValue: "Accept=json",
+}, {
+	about:  "repeated keys",
+	name:   "repeated",
+	values: []string{"Accept=json", "User-Agent=flagutils", "Accept=xml"},
+	expectedValue: map[string][]string{
+		"Accept":     {"json", "xml"},
+		"User-Agent": {"flagutils"},
+	},
+	expectedStringValue: "Accept=json,Accept=xml,User-Agent=flagutils",
+}, {
+	about:  "values are taken verbatim",
+	name:   "verbatim",
+	values: []string{"Accept= text/html, application/xhtml+xml", "Empty="},
+	expectedValue: map[string][]string{
+		"Accept": {"text/html, application/xhtml+xml"},
+		"Empty":  {""},
+	},
+	expectedStringValue: "Accept=text/html, application/xhtml+xml,Empty=",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	values:        []string{"Accept=xml"},
+	defaultValue:  map[string][]string{"Accept": {"json"}},
+	expectedValue: map[string][]string{"Accept": {"xml"}},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  map[string][]string{"Accept": {"json"}},
+	expectedValue: map[string][]string{"Accept": {"json"}},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	values:        []string{""},
+	expectedError: `invalid key=value pair "": missing "="`,
+}, {
+	about:               "error: empty key",
+	name:                "err",
+	values:              []string{"Accept=json", "=xml"},
+	expectedStringValue: "Accept=json",
+	expectedError:       `invalid key=value pair "=xml": empty key`,
+}}
+
+func TestStringToStringSlice(t *testing.T) {
+	for _, test := range stringToStringSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.StringToStringSlice(test.name, test.defaultValue, "string to string slice usage")
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set(test.name, value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+				c.Assert(*v, qt.DeepEquals, test.expectedValue)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestStringToStringSliceVar(t *testing.T) {
+	for _, test := range stringToStringSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v map[string][]string
+			flagutils.StringToStringSliceVar(&v, test.name, test.defaultValue, "string to string slice usage")
+			var err error
+			for _, value := range test.values {
+				if err = flag.Set(test.name, value); err != nil {
+					break
+				}
+			}
+			if test.expectedError == "" {
+				c.Assert(err, qt.Equals, nil)
+				c.Assert(v, qt.DeepEquals, test.expectedValue)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestStringToStringSliceValueString(t *testing.T) {
+	for _, test := range stringToStringSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v map[string][]string
+			s := flagutils.NewStringToStringSliceValue(&v)
+			for _, value := range test.values {
+				s.Set(value)
+			}
+			c.Assert(s.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
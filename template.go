@@ -0,0 +1,136 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+// Template defines a text template flag with specified name, default value,
+// and usage string. The template is parsed with text/template when the flag
+// is set, so that syntax errors are reported as flag errors. The return
+// value is the address of a *template.Template variable that stores the
+// parsed template, named after the flag.
+func Template(name string, value *template.Template, usage string, opts ...Option) **template.Template {
+	var t *template.Template
+	TemplateVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// TemplateVar defines a text template flag with specified name, default
+// value, and usage string. The argument p points to a *template.Template
+// variable in which to store the parsed template.
+func TemplateVar(p **template.Template, name string, value *template.Template, usage string, opts ...Option) {
+	TemplateVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// TemplateVarFS is like TemplateVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func TemplateVarFS(fs *flag.FlagSet, p **template.Template, name string, value *template.Template, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewTemplateValue(p, name), name, usage, opts)
+}
+
+// NewTemplateValue returns a TemplateValue storing templates with the given
+// name in p.
+func NewTemplateValue(p **template.Template, name string) *TemplateValue {
+	return &TemplateValue{
+		p:    p,
+		name: name,
+	}
+}
+
+// TemplateValue holds a text template that can be provided via the command
+// line, for instance "{{.Name}}: {{.Status}}".
+type TemplateValue struct {
+	p    **template.Template
+	name string
+	text string
+}
+
+// String implements flag.Value by returning the template source.
+func (t *TemplateValue) String() string {
+	return t.text
+}
+
+// Set implements flag.Value by parsing the given template.
+func (t *TemplateValue) Set(value string) error {
+	tmpl, err := template.New(t.name).Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid template %q: %v", value, err)
+	}
+	*t.p = tmpl
+	t.text = value
+	return nil
+}
+
+// Type implements pflag.Value by returning "template".
+func (t *TemplateValue) Type() string {
+	return "template"
+}
+
+// HTMLTemplate defines an HTML template flag with specified name, default
+// value, and usage string. The template is parsed with html/template when
+// the flag is set, so that syntax errors are reported as flag errors. The
+// return value is the address of a *template.Template variable that stores
+// the parsed template, named after the flag.
+func HTMLTemplate(name string, value *htmltemplate.Template, usage string, opts ...Option) **htmltemplate.Template {
+	var t *htmltemplate.Template
+	HTMLTemplateVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// HTMLTemplateVar defines an HTML template flag with specified name, default
+// value, and usage string. The argument p points to a *template.Template
+// variable in which to store the parsed template.
+func HTMLTemplateVar(p **htmltemplate.Template, name string, value *htmltemplate.Template, usage string, opts ...Option) {
+	HTMLTemplateVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// HTMLTemplateVarFS is like HTMLTemplateVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func HTMLTemplateVarFS(fs *flag.FlagSet, p **htmltemplate.Template, name string, value *htmltemplate.Template, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewHTMLTemplateValue(p, name), name, usage, opts)
+}
+
+// NewHTMLTemplateValue returns an HTMLTemplateValue storing templates with
+// the given name in p.
+func NewHTMLTemplateValue(p **htmltemplate.Template, name string) *HTMLTemplateValue {
+	return &HTMLTemplateValue{
+		p:    p,
+		name: name,
+	}
+}
+
+// HTMLTemplateValue holds an HTML template that can be provided via the
+// command line.
+type HTMLTemplateValue struct {
+	p    **htmltemplate.Template
+	name string
+	text string
+}
+
+// String implements flag.Value by returning the template source.
+func (t *HTMLTemplateValue) String() string {
+	return t.text
+}
+
+// Set implements flag.Value by parsing the given template.
+func (t *HTMLTemplateValue) Set(value string) error {
+	tmpl, err := htmltemplate.New(t.name).Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid template %q: %v", value, err)
+	}
+	*t.p = tmpl
+	t.text = value
+	return nil
+}
+
+// Type implements pflag.Value by returning "htmlTemplate".
+func (t *HTMLTemplateValue) Type() string {
+	return "htmlTemplate"
+}
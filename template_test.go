@@ -0,0 +1,121 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var (
+	_ flag.Value = (*flagutils.TemplateValue)(nil)
+	_ flag.Value = (*flagutils.HTMLTemplateValue)(nil)
+)
+
+var templateTests = []struct {
+	about         string
+	name          string
+	value         string
+	expectedText  string
+	expectedHTML  string
+	expectedError string
+}{{
+	about:        "template",
+	name:         "format",
+	value:        "{{.Name}}: {{.Status}}",
+	expectedText: "<wolf>: bad",
+	expectedHTML: "&lt;wolf&gt;: bad",
+}, {
+	about:        "constant",
+	name:         "constant",
+	value:        "hello",
+	expectedText: "hello",
+	expectedHTML: "hello",
+}, {
+	about:         "error: unclosed action",
+	name:          "err",
+	value:         "{{.Name}: {{.Status}}",
+	expectedError: `invalid template "{{.Name}: {{.Status}}": template: err:1: bad character U\+007D '}'`,
+}, {
+	about:         "error: unknown function",
+	name:          "err",
+	value:         "{{upper .Name}}",
+	expectedError: `invalid template "{{upper .Name}}": template: err:1: function "upper" not defined`,
+}}
+
+type templateData struct {
+	Name   string
+	Status string
+}
+
+func TestTemplate(t *testing.T) {
+	for _, test := range templateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Template(test.name, nil, "template usage")
+			err := flag.Set(test.name, test.value)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				c.Assert(*v, qt.IsNil)
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			c.Assert((*v).Name(), qt.Equals, test.name)
+			var b strings.Builder
+			err = (*v).Execute(&b, templateData{Name: "<wolf>", Status: "bad"})
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(b.String(), qt.Equals, test.expectedText)
+			c.Assert(flag.Lookup(test.name).Value.String(), qt.Equals, test.value)
+		})
+	}
+}
+
+func TestTemplateVar(t *testing.T) {
+	runIsolated(t, "default value", func(c *qt.C) {
+		def := template.Must(template.New("default").Parse("{{.Name}}"))
+		var v *template.Template
+		flagutils.TemplateVar(&v, "format", def, "template usage")
+		c.Assert(v, qt.Equals, def)
+		err := flag.Set("format", "{{")
+		c.Assert(err, qt.ErrorMatches, `invalid template "{{": template: format:1: .*`)
+		c.Assert(v, qt.Equals, def)
+	})
+}
+
+func TestHTMLTemplate(t *testing.T) {
+	for _, test := range templateTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.HTMLTemplate(test.name, nil, "HTML template usage")
+			err := flag.Set(test.name, test.value)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				c.Assert(*v, qt.IsNil)
+				return
+			}
+			c.Assert(err, qt.Equals, nil)
+			var b strings.Builder
+			err = (*v).Execute(&b, templateData{Name: "<wolf>", Status: "bad"})
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(b.String(), qt.Equals, test.expectedHTML)
+			c.Assert(flag.Lookup(test.name).Value.String(), qt.Equals, test.value)
+		})
+	}
+}
+
+func TestHTMLTemplateVar(t *testing.T) {
+	runIsolated(t, "default value", func(c *qt.C) {
+		def := htmltemplate.Must(htmltemplate.New("default").Parse("{{.Name}}"))
+		var v *htmltemplate.Template
+		flagutils.HTMLTemplateVar(&v, "format", def, "HTML template usage")
+		c.Assert(v, qt.Equals, def)
+		err := flag.Set("format", "{{end}}")
+		c.Assert(err, qt.ErrorMatches, `invalid template "{{end}}": template: format:1: unexpected {{end}}`)
+		c.Assert(v, qt.Equals, def)
+	})
+}
@@ -0,0 +1,99 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// Text defines a flag with specified name and usage string, whose value is
+// decoded into v using its UnmarshalText method. The value of v is used as
+// the default value, and it is displayed in the usage message if v also
+// implements encoding.TextMarshaler. For instance:
+//
+//	var addr netip.Addr
+//	flagutils.Text("addr", &addr, "the address to listen on")
+func Text(name string, v encoding.TextUnmarshaler, usage string, opts ...Option) {
+	TextFS(flag.CommandLine, name, v, usage, opts...)
+}
+
+// TextFS is like Text, but defines the flag in the given flag set rather
+// than in the default command line flag set.
+func TextFS(fs *flag.FlagSet, name string, v encoding.TextUnmarshaler, usage string, opts ...Option) {
+	defineVar(fs, NewTextValue(v), name, usage, opts)
+}
+
+// TextVar defines a flag with specified name, default value, usage string
+// and options, as done by flag.TextVar. The argument p points to a variable
+// in which to store the value of the flag, which is decoded using its
+// UnmarshalText method. The default value is encoded with its MarshalText
+// method and then decoded into p. TextVar panics if the default value cannot
+// be encoded or decoded.
+func TextVar(p encoding.TextUnmarshaler, name string, value encoding.TextMarshaler, usage string, opts ...Option) {
+	TextVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// TextVarFS is like TextVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func TextVarFS(fs *flag.FlagSet, p encoding.TextUnmarshaler, name string, value encoding.TextMarshaler, usage string, opts ...Option) {
+	v := NewTextValue(p)
+	if value != nil {
+		b, err := value.MarshalText()
+		if err != nil {
+			panic(fmt.Sprintf("flagutils: cannot encode default value of flag -%s: %v", name, err))
+		}
+		if err := p.UnmarshalText(b); err != nil {
+			panic(fmt.Sprintf("flagutils: cannot decode default value of flag -%s: %v", name, err))
+		}
+	}
+	defineVar(fs, v, name, usage, opts)
+}
+
+// NewTextValue returns a TextValue decoding values into p, which must be a
+// non-nil pointer.
+func NewTextValue(p encoding.TextUnmarshaler) *TextValue {
+	if v := reflect.ValueOf(p); v.Kind() != reflect.Ptr || v.IsNil() {
+		panic(fmt.Sprintf("flagutils: text flag destination must be a non-nil pointer, got %T", p))
+	}
+	return &TextValue{
+		p: p,
+	}
+}
+
+// TextValue holds a value implementing encoding.TextUnmarshaler, like
+// netip.Addr, that can be provided via the command line in its text form.
+type TextValue struct {
+	p encoding.TextUnmarshaler
+}
+
+// String implements flag.Value by returning the text encoded value, if the
+// value also implements encoding.TextMarshaler.
+func (v *TextValue) String() string {
+	m, ok := v.p.(encoding.TextMarshaler)
+	if !ok {
+		return ""
+	}
+	b, err := m.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Set implements flag.Value by decoding the value into the destination using
+// its UnmarshalText method.
+func (v *TextValue) Set(value string) error {
+	return v.p.UnmarshalText([]byte(value))
+}
+
+// Type implements pflag.Value by returning the name of the destination type,
+// as in "netip.Addr".
+func (v *TextValue) Type() string {
+	if v.p == nil {
+		return "text"
+	}
+	return reflect.TypeOf(v.p).Elem().String()
+}
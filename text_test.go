@@ -0,0 +1,150 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"bytes"
+	"flag"
+	"net/netip"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.TextValue)(nil)
+
+var textTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        netip.Addr
+	expectedValue       netip.Addr
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "ipv4",
+	name:                "ipv4",
+	value:               "1.2.3.4",
+	expectedValue:       netip.MustParseAddr("1.2.3.4"),
+	expectedStringValue: "1.2.3.4",
+}, {
+	about:               "ipv6",
+	name:                "ipv6",
+	value:               "2001:DB8::1",
+	expectedValue:       netip.MustParseAddr("2001:db8::1"),
+	expectedStringValue: "2001:db8::1",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "::1",
+	defaultValue:  netip.MustParseAddr("127.0.0.1"),
+	expectedValue: netip.MustParseAddr("::1"),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  netip.MustParseAddr("127.0.0.1"),
+	expectedValue: netip.MustParseAddr("127.0.0.1"),
+}, {
+	about:         "error: invalid value",
+	name:          "err",
+	value:         "bad-wolf",
+	expectedError: `invalid value "bad-wolf" for flag -err: ParseAddr\("bad-wolf"\): unable to parse IP`,
+}}
+
+func TestText(t *testing.T) {
+	for _, test := range textTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			flag.CommandLine.SetOutput(new(bytes.Buffer))
+			v := test.defaultValue
+			flagutils.Text(test.name, &v, "text usage")
+			c.Assert(flag.Lookup(test.name).DefValue, qt.Equals, textDefValue(test.defaultValue))
+			if test.value != "" {
+				err := flag.CommandLine.Parse([]string{"-" + test.name, test.value})
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestTextVar(t *testing.T) {
+	for _, test := range textTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			flag.CommandLine.SetOutput(new(bytes.Buffer))
+			var v netip.Addr
+			flagutils.TextVar(&v, test.name, test.defaultValue, "text usage")
+			c.Assert(flag.Lookup(test.name).DefValue, qt.Equals, textDefValue(test.defaultValue))
+			if test.value != "" {
+				err := flag.CommandLine.Parse([]string{"-" + test.name, test.value})
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestTextValueString(t *testing.T) {
+	for _, test := range textTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue.IsValid() || test.expectedError != "" {
+				return
+			}
+			var addr netip.Addr
+			v := flagutils.NewTextValue(&addr)
+			c.Assert(v.String(), qt.Equals, "")
+			c.Assert(v.Type(), qt.Equals, "netip.Addr")
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestTextWithOptions(t *testing.T) {
+	t.Setenv("FLAGUTILS_ADDR", "10.0.0.1")
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	var addr, gateway netip.Addr
+	fs.Text("addr", &addr, "the address", flagutils.Env("FLAGUTILS_ADDR"))
+	fs.TextVar(&gateway, "gateway", netip.MustParseAddr("10.0.0.254"), "the gateway", flagutils.Required())
+	err := fs.Parse(nil)
+	c.Assert(err, qt.ErrorMatches, "required flag -gateway not provided")
+	c.Assert(addr, qt.Equals, netip.MustParseAddr("10.0.0.1"))
+	c.Assert(fs.Lookup("gateway").Usage, qt.Equals, "the gateway (required)")
+}
+
+func TestTextVarInvalidDefault(t *testing.T) {
+	c := qt.New(t)
+	fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+	c.Assert(func() {
+		var v flagutils.StringSlice
+		flagutils.TextVarFS(fs, &v, "slice", flagutils.StringSlice{"a", ""}, "slice usage")
+	}, qt.PanicMatches, "flagutils: cannot decode default value of flag -slice: cannot include empty strings in the list")
+}
+
+func TestNewTextValueInvalidDestination(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(func() {
+		flagutils.NewTextValue((*netip.Addr)(nil))
+	}, qt.PanicMatches, `flagutils: text flag destination must be a non-nil pointer, got \*netip.Addr`)
+}
+
+// textDefValue returns the expected default value of a text flag with the
+// given default address.
+func textDefValue(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+	return addr.String()
+}
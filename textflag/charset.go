@@ -0,0 +1,87 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package textflag
+
+import (
+	"flag"
+	"fmt"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+
+	"github.com/frankban/flagutils"
+)
+
+// Charset defines a character encoding flag with specified name, default
+// value, and usage string. The value is provided via the command line as a
+// charset name, as in "utf-8" or "latin1". The return value is the address
+// of an encoding.Encoding variable that stores the value of the flag.
+func Charset(name string, value encoding.Encoding, usage string, opts ...flagutils.Option) *encoding.Encoding {
+	var e encoding.Encoding
+	CharsetVar(&e, name, value, usage, opts...)
+	return &e
+}
+
+// CharsetVar defines a character encoding flag with specified name, default
+// value, and usage string. The argument p points to an encoding.Encoding
+// variable in which to store the value of the flag.
+func CharsetVar(p *encoding.Encoding, name string, value encoding.Encoding, usage string, opts ...flagutils.Option) {
+	CharsetVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// CharsetVarFS is like CharsetVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func CharsetVarFS(fs *flag.FlagSet, p *encoding.Encoding, name string, value encoding.Encoding, usage string, opts ...flagutils.Option) {
+	*p = value
+	flagutils.VarFS(fs, NewCharsetValue(p), name, usage, opts...)
+}
+
+// NewCharsetValue returns a CharsetValue storing its value in p.
+func NewCharsetValue(p *encoding.Encoding) *CharsetValue {
+	return &CharsetValue{
+		p: p,
+	}
+}
+
+// CharsetValue holds a character encoding that can be provided via the
+// command line as a case insensitive charset name. Names and aliases are
+// resolved using the IANA registry, so that for instance "latin1" refers to
+// ISO-8859-1, falling back to the labels defined by the WHATWG Encoding
+// Standard, like "utf8".
+type CharsetValue struct {
+	p *encoding.Encoding
+}
+
+// String implements flag.Value by returning the MIME name of the encoding.
+func (c *CharsetValue) String() string {
+	if c.p == nil || *c.p == nil {
+		return ""
+	}
+	name, err := ianaindex.MIME.Name(*c.p)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// Set implements flag.Value by resolving the given charset name.
+func (c *CharsetValue) Set(value string) error {
+	e, err := ianaindex.IANA.Encoding(value)
+	if err != nil {
+		if e, err = htmlindex.Get(value); err != nil {
+			return fmt.Errorf("invalid charset %q", value)
+		}
+	}
+	if e == nil {
+		// The name is registered but there is no implementation for it.
+		return fmt.Errorf("unsupported charset %q", value)
+	}
+	*c.p = e
+	return nil
+}
+
+// Type implements pflag.Value by returning "charset".
+func (c *CharsetValue) Type() string {
+	return "charset"
+}
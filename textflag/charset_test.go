@@ -0,0 +1,131 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package textflag_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/frankban/flagutils/textflag"
+)
+
+var _ flag.Value = (*textflag.CharsetValue)(nil)
+
+var charsetTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        encoding.Encoding
+	expectedValue       encoding.Encoding
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "utf-8",
+	name:                "utf8",
+	value:               "utf-8",
+	expectedValue:       unicode.UTF8,
+	expectedStringValue: "UTF-8",
+}, {
+	about:               "latin1 alias",
+	name:                "latin1",
+	value:               "latin1",
+	expectedValue:       charmap.ISO8859_1,
+	expectedStringValue: "ISO-8859-1",
+}, {
+	about:               "case insensitive",
+	name:                "case",
+	value:               "SHIFT_JIS",
+	expectedValue:       japanese.ShiftJIS,
+	expectedStringValue: "Shift_JIS",
+}, {
+	about:               "WHATWG label",
+	name:                "whatwg",
+	value:               "utf8",
+	expectedValue:       unicode.UTF8,
+	expectedStringValue: "UTF-8",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "windows-1252",
+	defaultValue:  unicode.UTF8,
+	expectedValue: charmap.Windows1252,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  unicode.UTF8,
+	expectedValue: unicode.UTF8,
+}, {
+	about:         "error: unknown charset",
+	name:          "err",
+	value:         "bad-wolf",
+	expectedError: `invalid charset "bad-wolf"`,
+}, {
+	about:         "error: unsupported charset",
+	name:          "err",
+	value:         "UTF-7",
+	expectedError: `unsupported charset "UTF-7"`,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid charset ""`,
+}}
+
+func TestCharset(t *testing.T) {
+	for _, test := range charsetTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := textflag.Charset(test.name, test.defaultValue, "charset usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					c.Assert(*v, qt.IsNil)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestCharsetVar(t *testing.T) {
+	for _, test := range charsetTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v encoding.Encoding
+			textflag.CharsetVar(&v, test.name, test.defaultValue, "charset usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+					c.Assert(v, qt.IsNil)
+					return
+				}
+				c.Assert(err, qt.Equals, nil)
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestCharsetValueString(t *testing.T) {
+	for _, test := range charsetTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil || test.expectedError != "" {
+				return
+			}
+			var v encoding.Encoding
+			cs := textflag.NewCharsetValue(&v)
+			c.Assert(cs.String(), qt.Equals, "")
+			c.Assert(cs.Type(), qt.Equals, "charset")
+			err := cs.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(cs.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+// Package textflag provides command line flags holding language tags and
+// character encodings, as defined by golang.org/x/text. It is kept separate
+// from the flagutils package so that programs not using these flags do not
+// depend on golang.org/x/text.
+package textflag
+
+import (
+	"flag"
+	"fmt"
+
+	"golang.org/x/text/language"
+
+	"github.com/frankban/flagutils"
+)
+
+// Language defines a BCP 47 language tag flag with specified name, default
+// value, and usage string. The value is provided via the command line as a
+// language tag, as in "en-GB" or "it", and is validated using
+// language.Parse, so that malformed or unknown tags are rejected. The return
+// value is the address of a language.Tag variable that stores the value of
+// the flag.
+func Language(name string, value language.Tag, usage string, opts ...flagutils.Option) *language.Tag {
+	var t language.Tag
+	LanguageVar(&t, name, value, usage, opts...)
+	return &t
+}
+
+// LanguageVar defines a BCP 47 language tag flag with specified name,
+// default value, and usage string. The argument p points to a language.Tag
+// variable in which to store the value of the flag.
+func LanguageVar(p *language.Tag, name string, value language.Tag, usage string, opts ...flagutils.Option) {
+	LanguageVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// LanguageVarFS is like LanguageVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func LanguageVarFS(fs *flag.FlagSet, p *language.Tag, name string, value language.Tag, usage string, opts ...flagutils.Option) {
+	*p = value
+	flagutils.VarFS(fs, (*LanguageValue)(p), name, usage, opts...)
+}
+
+// LanguageValue holds a BCP 47 language tag that can be provided via the
+// command line.
+type LanguageValue language.Tag
+
+// String implements flag.Value by returning the canonical form of the tag.
+func (t *LanguageValue) String() string {
+	return language.Tag(*t).String()
+}
+
+// Set implements flag.Value by parsing the given language tag.
+func (t *LanguageValue) Set(value string) error {
+	tag, err := language.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid language tag %q: %v", value, err)
+	}
+	*t = LanguageValue(tag)
+	return nil
+}
+
+// Type implements pflag.Value by returning "language".
+func (t *LanguageValue) Type() string {
+	return "language"
+}
@@ -0,0 +1,150 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package textflag_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"golang.org/x/text/language"
+
+	"github.com/frankban/flagutils"
+	"github.com/frankban/flagutils/textflag"
+)
+
+var _ flag.Value = (*textflag.LanguageValue)(nil)
+
+var languageTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        language.Tag
+	expectedValue       language.Tag
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "language and region",
+	name:                "region",
+	value:               "en-GB",
+	expectedValue:       language.BritishEnglish,
+	expectedStringValue: "en-GB",
+}, {
+	about:               "language",
+	name:                "language",
+	value:               "it",
+	expectedValue:       language.Italian,
+	expectedStringValue: "it",
+}, {
+	about:               "canonicalized",
+	name:                "canonical",
+	value:               "EN_us",
+	expectedValue:       language.AmericanEnglish,
+	expectedStringValue: "en-US",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "fr",
+	defaultValue:  language.English,
+	expectedValue: language.French,
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  language.English,
+	expectedValue: language.English,
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid language tag "": .*`,
+}, {
+	about:         "error: malformed tag",
+	name:          "err",
+	value:         "en-",
+	expectedError: `invalid language tag "en-": .*`,
+}, {
+	about:         "error: unknown language",
+	name:          "err",
+	value:         "xx-YY",
+	expectedError: `invalid language tag "xx-YY": .*`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "english",
+	defaultValue:  language.English,
+	expectedValue: language.English,
+	expectedError: `invalid language tag "english": .*`,
+}}
+
+func TestLanguage(t *testing.T) {
+	for _, test := range languageTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := textflag.Language(test.name, test.defaultValue, "language usage")
+			if test.value != "" || test.defaultValue == language.Und {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestLanguageVar(t *testing.T) {
+	for _, test := range languageTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v language.Tag
+			textflag.LanguageVar(&v, test.name, test.defaultValue, "language usage")
+			if test.value != "" || test.defaultValue == language.Und {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestLanguageValueString(t *testing.T) {
+	for _, test := range languageTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != language.Und || test.expectedError != "" {
+				return
+			}
+			var v textflag.LanguageValue
+			c.Assert(v.Type(), qt.Equals, "language")
+			err := v.Set(test.value)
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestLanguageWithOptions(t *testing.T) {
+	t.Setenv("FLAGUTILS_LANG", "it")
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	var tag language.Tag
+	textflag.LanguageVarFS(fs.FlagSet, &tag, "lang", language.English, "the language", flagutils.Env("FLAGUTILS_LANG"))
+	c.Assert(fs.Lookup("lang").Usage, qt.Equals, "the language (env FLAGUTILS_LANG)")
+	err := fs.Parse(nil)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(tag, qt.Equals, language.Italian)
+}
+
+// runIsolated runs the given test function without clobbering global flags.
+func runIsolated(t *testing.T, name string, f func(c *qt.C)) {
+	original := flag.CommandLine
+	flag.CommandLine = flag.NewFlagSet("", flag.ContinueOnError)
+	defer func() {
+		flag.CommandLine = original
+	}()
+	qt.New(t).Run(name, f)
+}
@@ -0,0 +1,96 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Time defines a time flag with specified name, default value, accepted
+// layouts and usage string. The value is parsed using the given layouts in
+// order, the first one succeeding winning. If no layouts are provided,
+// time.RFC3339 is used. The value can also be provided relative to the
+// current time, as in "now", "now-1h" or "now+2d". The return value is the
+// address of a time variable that stores the value of the flag.
+func Time(name string, value time.Time, layouts []string, usage string, opts ...Option) *time.Time {
+	var t time.Time
+	TimeVar(&t, name, value, layouts, usage, opts...)
+	return &t
+}
+
+// TimeVar defines a time flag with specified name, default value, accepted
+// layouts and usage string. The argument p points to a time variable in which
+// to store the value of the flag. See Time for details on the accepted
+// values.
+func TimeVar(p *time.Time, name string, value time.Time, layouts []string, usage string, opts ...Option) {
+	TimeVarFS(flag.CommandLine, p, name, value, layouts, usage, opts...)
+}
+
+// TimeVarFS is like TimeVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func TimeVarFS(fs *flag.FlagSet, p *time.Time, name string, value time.Time, layouts []string, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewTimeValue(p, layouts), name, usage, opts)
+}
+
+// NewTimeValue returns a TimeValue storing its value in p and parsing it
+// using the given layouts. If no layouts are provided, time.RFC3339 is used.
+func NewTimeValue(p *time.Time, layouts []string) *TimeValue {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	return &TimeValue{
+		p:       p,
+		layouts: layouts,
+	}
+}
+
+// TimeValue holds a time that can be provided via the command line either
+// as a timestamp or relative to the current time, as in "now-1h". Relative
+// offsets use the syntax accepted by Duration.
+type TimeValue struct {
+	p       *time.Time
+	layouts []string
+}
+
+// String implements flag.Value by formatting the time using the first
+// layout.
+func (t *TimeValue) String() string {
+	if t.p == nil || t.p.IsZero() {
+		return ""
+	}
+	return t.p.Format(t.layouts[0])
+}
+
+// Set implements flag.Value by parsing the given timestamp or relative time.
+func (t *TimeValue) Set(value string) error {
+	if rest := strings.TrimPrefix(value, "now"); rest != value {
+		now := time.Now()
+		if rest != "" {
+			if rest[0] != '-' && rest[0] != '+' {
+				return fmt.Errorf("invalid time value %q", value)
+			}
+			d, err := parseDuration(rest)
+			if err != nil {
+				return fmt.Errorf("invalid time value %q: %v", value, err)
+			}
+			now = now.Add(d)
+		}
+		*t.p = now
+		return nil
+	}
+	v, err := parseTime(value, t.layouts)
+	if err != nil {
+		return err
+	}
+	*t.p = v
+	return nil
+}
+
+// Type implements pflag.Value by returning "time".
+func (t *TimeValue) Type() string {
+	return "time"
+}
@@ -0,0 +1,162 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.TimeValue)(nil)
+
+var timeTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        time.Time
+	layouts             []string
+	expectedValue       time.Time
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "timestamp",
+	name:                "timestamp",
+	value:               "2018-07-27T13:19:33Z",
+	expectedValue:       time.Date(2018, 7, 27, 13, 19, 33, 0, time.UTC),
+	expectedStringValue: "2018-07-27T13:19:33Z",
+}, {
+	about:               "alternative layouts",
+	name:                "layouts",
+	value:               "2019-01-02 03:04",
+	layouts:             []string{"2006-01-02", "2006-01-02 15:04"},
+	expectedValue:       time.Date(2019, 1, 2, 3, 4, 0, 0, time.UTC),
+	expectedStringValue: "2019-01-02",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "2018-07-27T13:19:33Z",
+	defaultValue:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	expectedValue: time.Date(2018, 7, 27, 13, 19, 33, 0, time.UTC),
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	expectedValue: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid time value "": expected layout "2006-01-02T15:04:05Z07:00"`,
+}, {
+	about:         "error: invalid timestamp",
+	name:          "err",
+	value:         "2018-07-27",
+	expectedError: `invalid time value "2018-07-27": expected layout "2006-01-02T15:04:05Z07:00"`,
+}, {
+	about:         "error: multiple layouts",
+	name:          "err",
+	value:         "27/07/2018",
+	layouts:       []string{"2006-01-02", "2006-01-02 15:04"},
+	expectedError: `invalid time value "27/07/2018": expected one of the layouts \["2006-01-02" "2006-01-02 15:04"\]`,
+}, {
+	about:         "error: invalid relative time",
+	name:          "err",
+	value:         "now-1y",
+	expectedError: `invalid time value "now-1y": invalid duration "-1y"`,
+}, {
+	about:         "error: missing relative sign",
+	name:          "err",
+	value:         "now1h",
+	expectedError: `invalid time value "now1h"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "yesterday",
+	defaultValue:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	expectedValue: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	expectedError: `invalid time value "yesterday": expected layout "2006-01-02T15:04:05Z07:00"`,
+}}
+
+func TestTime(t *testing.T) {
+	for _, test := range timeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Time(test.name, test.defaultValue, test.layouts, "time usage")
+			if test.value != "" || test.defaultValue.IsZero() {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestTimeVar(t *testing.T) {
+	for _, test := range timeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v time.Time
+			flagutils.TimeVar(&v, test.name, test.defaultValue, test.layouts, "time usage")
+			if test.value != "" || test.defaultValue.IsZero() {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestTimeValueString(t *testing.T) {
+	for _, test := range timeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if !test.defaultValue.IsZero() {
+				return
+			}
+			var v time.Time
+			tv := flagutils.NewTimeValue(&v, test.layouts)
+			tv.Set(test.value)
+			c.Assert(tv.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+var relativeTimeTests = []struct {
+	about          string
+	value          string
+	expectedOffset time.Duration
+}{{
+	about: "now",
+	value: "now",
+}, {
+	about:          "in the past",
+	value:          "now-1h30m",
+	expectedOffset: -90 * time.Minute,
+}, {
+	about:          "in the future",
+	value:          "now+2d",
+	expectedOffset: 48 * time.Hour,
+}}
+
+func TestTimeRelative(t *testing.T) {
+	for _, test := range relativeTimeTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.Time("relative", time.Time{}, nil, "time usage")
+			before := time.Now()
+			err := flag.Set("relative", test.value)
+			after := time.Now()
+			c.Assert(err, qt.Equals, nil)
+			c.Assert(v.Before(before.Add(test.expectedOffset)), qt.Equals, false)
+			c.Assert(v.After(after.Add(test.expectedOffset)), qt.Equals, false)
+		})
+	}
+}
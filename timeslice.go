@@ -0,0 +1,105 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeSlice defines a time slice flag with specified name, default value,
+// accepted layouts and usage string. Each element is parsed using the given
+// layouts in order, the first one succeeding winning. If no layouts are
+// provided, time.RFC3339 is used. The return value is the address of a time
+// slice variable that stores the value of the flag.
+func TimeSlice(name string, value []time.Time, layouts []string, usage string, opts ...Option) *[]time.Time {
+	var s []time.Time
+	TimeSliceVar(&s, name, value, layouts, usage, opts...)
+	return &s
+}
+
+// TimeSliceVar defines a time slice flag with specified name, default value,
+// accepted layouts and usage string. The argument p points to a time slice
+// variable in which to store the value of the flag. See TimeSlice for details
+// on how layouts are used.
+func TimeSliceVar(p *[]time.Time, name string, value []time.Time, layouts []string, usage string, opts ...Option) {
+	TimeSliceVarFS(flag.CommandLine, p, name, value, layouts, usage, opts...)
+}
+
+// TimeSliceVarFS is like TimeSliceVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func TimeSliceVarFS(fs *flag.FlagSet, p *[]time.Time, name string, value []time.Time, layouts []string, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewTimeSliceValue(p, layouts), name, usage, opts)
+}
+
+// NewTimeSliceValue returns a TimeSliceValue storing its value in p and
+// parsing elements using the given layouts. If no layouts are provided,
+// time.RFC3339 is used.
+func NewTimeSliceValue(p *[]time.Time, layouts []string) *TimeSliceValue {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	return &TimeSliceValue{
+		p:       p,
+		layouts: layouts,
+	}
+}
+
+// TimeSliceValue holds a slice of times that can be provided via the command
+// line as a comma separated list of values.
+type TimeSliceValue struct {
+	p       *[]time.Time
+	layouts []string
+}
+
+// String implements flag.Value by returning the slice as a string. Times are
+// formatted using the first layout.
+func (s *TimeSliceValue) String() string {
+	if s.p == nil {
+		return ""
+	}
+	values := make([]string, len(*s.p))
+	for i, t := range *s.p {
+		values[i] = t.Format(s.layouts[0])
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value by populating the slice from the given comma
+// separated value.
+func (s *TimeSliceValue) Set(value string) error {
+	*s.p = nil
+	values, err := splitList(value)
+	if err != nil {
+		return err
+	}
+	ts := make([]time.Time, len(values))
+	for i, v := range values {
+		if ts[i], err = parseTime(v, s.layouts); err != nil {
+			return err
+		}
+	}
+	*s.p = ts
+	return nil
+}
+
+// Type implements pflag.Value by returning "timeSlice".
+func (s *TimeSliceValue) Type() string {
+	return "timeSlice"
+}
+
+// parseTime parses the given value trying all the given layouts in order.
+func parseTime(value string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if len(layouts) == 1 {
+		return time.Time{}, fmt.Errorf("invalid time value %q: expected layout %q", value, layouts[0])
+	}
+	return time.Time{}, fmt.Errorf("invalid time value %q: expected one of the layouts %q", value, layouts)
+}
@@ -0,0 +1,126 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.TimeSliceValue)(nil)
+
+var timeSliceTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        []time.Time
+	layouts             []string
+	expectedValue       []time.Time
+	expectedStringValue string
+	expectedError       string
+}{{
+	about:               "single timestamp",
+	name:                "single",
+	value:               "2018-07-27T13:19:33Z",
+	expectedValue:       []time.Time{time.Date(2018, 7, 27, 13, 19, 33, 0, time.UTC)},
+	expectedStringValue: "2018-07-27T13:19:33Z",
+}, {
+	about: "multiple timestamps",
+	name:  "multiple",
+	value: "2018-07-27T13:19:33Z, 2019-01-02T03:04:05Z",
+	expectedValue: []time.Time{
+		time.Date(2018, 7, 27, 13, 19, 33, 0, time.UTC),
+		time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+	},
+	expectedStringValue: "2018-07-27T13:19:33Z,2019-01-02T03:04:05Z",
+}, {
+	about:   "alternative layouts",
+	name:    "layouts",
+	value:   "2018-07-27,2019-01-02 03:04",
+	layouts: []string{"2006-01-02", "2006-01-02 15:04"},
+	expectedValue: []time.Time{
+		time.Date(2018, 7, 27, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, 1, 2, 3, 4, 0, 0, time.UTC),
+	},
+	expectedStringValue: "2018-07-27,2019-01-02",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "2018-07-27T13:19:33Z",
+	defaultValue:  []time.Time{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	expectedValue: []time.Time{time.Date(2018, 7, 27, 13, 19, 33, 0, time.UTC)},
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  []time.Time{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	expectedValue: []time.Time{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: "cannot include empty strings in the list",
+}, {
+	about:         "error: invalid timestamp",
+	name:          "err",
+	value:         "2018-07-27T13:19:33Z,yesterday",
+	expectedError: `invalid time value "yesterday": expected layout "2006-01-02T15:04:05Z07:00"`,
+}, {
+	about:         "error: no matching layouts",
+	name:          "err",
+	value:         "2018-07-27T13:19:33Z",
+	layouts:       []string{"2006-01-02", "15:04"},
+	expectedError: `invalid time value "2018-07-27T13:19:33Z": expected one of the layouts \["2006-01-02" "15:04"\]`,
+}}
+
+func TestTimeSlice(t *testing.T) {
+	for _, test := range timeSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.TimeSlice(test.name, test.defaultValue, test.layouts, "time slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestTimeSliceVar(t *testing.T) {
+	for _, test := range timeSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v []time.Time
+			flagutils.TimeSliceVar(&v, test.name, test.defaultValue, test.layouts, "time slice usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestTimeSliceValueString(t *testing.T) {
+	for _, test := range timeSliceTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v []time.Time
+			s := flagutils.NewTimeSliceValue(&v, test.layouts)
+			s.Set(test.value)
+			c.Assert(s.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
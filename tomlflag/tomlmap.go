@@ -0,0 +1,93 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+// Package tomlflag provides command line flags holding TOML encoded maps. It
+// is kept separate from the flagutils package so that programs not using
+// these flags do not depend on a TOML library.
+package tomlflag
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/frankban/flagutils"
+	"github.com/frankban/flagutils/internal/mapcheck"
+)
+
+// TOMLMap defines a flag containing a map of strings with specified name,
+// default value, and usage string. The value is provided via the command line
+// as a TOML encoded string. The return value is the address of a StringMap
+// variable that stores the value of the flag.
+func TOMLMap(name string, value map[string]interface{}, usage string, opts ...flagutils.Option) *flagutils.StringMap {
+	var s flagutils.StringMap
+	TOMLMapVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// TOMLMapVar defines a flag containing a map of strings with specified name,
+// default value, and usage string. The value is provided via the command line
+// as a TOML encoded string. The argument p points to a StringMap variable in
+// which to store the value of the flag.
+func TOMLMapVar(p *flagutils.StringMap, name string, value map[string]interface{}, usage string, opts ...flagutils.Option) {
+	TOMLMapVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// TOMLMapVarFS is like TOMLMapVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func TOMLMapVarFS(fs *flag.FlagSet, p *flagutils.StringMap, name string, value map[string]interface{}, usage string, opts ...flagutils.Option) {
+	*p = value
+	flagutils.VarFS(fs, (*TOMLMapValue)(p), name, usage, opts...)
+}
+
+// TOMLMapValue holds a map of strings to empty interfaces that can be
+// provided via the command line as a TOML encoded string.
+type TOMLMapValue map[string]interface{}
+
+// String implements flag.Value by returning the map as a JSON encoded
+// string.
+func (s *TOMLMapValue) String() string {
+	return (*flagutils.StringMap)(s).String()
+}
+
+// Set implements flag.Value by unmarshaling the TOML encoded value into the
+// string map. Multi-line values are decoded as TOML documents, possibly
+// including tables. Single line values are decoded as inline tables, whose
+// enclosing braces can be omitted, so that `key = "value", answer = 42` is a
+// valid value. NaN and infinite numbers are rejected, as they cannot be
+// represented in the JSON encoded string value.
+func (s *TOMLMapValue) Set(value string) error {
+	*s = nil
+	value = strings.TrimSpace(value)
+	var m map[string]interface{}
+	if strings.Contains(value, "\n") {
+		if _, err := toml.Decode(value, &m); err != nil {
+			return fmt.Errorf("cannot unmarshal TOML: %v", err)
+		}
+	} else {
+		if !strings.HasPrefix(value, "{") {
+			value = "{" + value + "}"
+		}
+		var doc struct {
+			Table map[string]interface{} `toml:"table"`
+		}
+		if _, err := toml.Decode("table = "+value, &doc); err != nil {
+			return fmt.Errorf("cannot unmarshal TOML: %v", err)
+		}
+		m = doc.Table
+	}
+	if err := mapcheck.Finite(m); err != nil {
+		return err
+	}
+	if m == nil {
+		m = make(map[string]interface{})
+	}
+	*s = m
+	return nil
+}
+
+// Type implements pflag.Value by returning "tomlMap".
+func (s *TOMLMapValue) Type() string {
+	return "tomlMap"
+}
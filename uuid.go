@@ -0,0 +1,104 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// UUID defines a UUID flag with specified name, default value, and usage
+// string. The value must be in the canonical RFC 4122 form, for instance
+// "f81d4fae-7dec-11d0-a765-00a0c91e6bf6". When lenient is true, uppercase
+// digits and the "{...}" and "urn:uuid:" forms are also accepted, and the
+// value is normalized to the canonical form. The return value is the address
+// of a string variable that stores the value of the flag.
+func UUID(name string, value string, lenient bool, usage string, opts ...Option) *string {
+	var s string
+	UUIDVar(&s, name, value, lenient, usage, opts...)
+	return &s
+}
+
+// UUIDVar defines a UUID flag with specified name, default value, and usage
+// string. See UUID for details on the accepted formats. The argument p
+// points to a string variable in which to store the canonical form of the
+// flag value.
+func UUIDVar(p *string, name string, value string, lenient bool, usage string, opts ...Option) {
+	UUIDVarFS(flag.CommandLine, p, name, value, lenient, usage, opts...)
+}
+
+// UUIDVarFS is like UUIDVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func UUIDVarFS(fs *flag.FlagSet, p *string, name string, value string, lenient bool, usage string, opts ...Option) {
+	*p = value
+	defineVar(fs, NewUUIDValue(p, lenient), name, usage, opts)
+}
+
+// NewUUIDValue returns a UUIDValue storing its value in p. When lenient is
+// true, non canonical forms of UUIDs are accepted and normalized.
+func NewUUIDValue(p *string, lenient bool) *UUIDValue {
+	return &UUIDValue{
+		p:       p,
+		lenient: lenient,
+	}
+}
+
+// UUIDValue holds a UUID that can be provided via the command line as a
+// string of hexadecimal digits in the 8-4-4-4-12 form.
+type UUIDValue struct {
+	p       *string
+	lenient bool
+}
+
+// String implements flag.Value by returning the UUID in its canonical form.
+func (u *UUIDValue) String() string {
+	if u.p == nil {
+		return ""
+	}
+	return *u.p
+}
+
+// Set implements flag.Value by validating and storing the given UUID.
+func (u *UUIDValue) Set(value string) error {
+	v := value
+	if u.lenient {
+		v = strings.ToLower(v)
+		if strings.HasPrefix(v, "{") && strings.HasSuffix(v, "}") {
+			v = v[1 : len(v)-1]
+		} else {
+			v = strings.TrimPrefix(v, "urn:uuid:")
+		}
+	}
+	if !isUUID(v) {
+		return fmt.Errorf("invalid UUID %q", value)
+	}
+	*u.p = v
+	return nil
+}
+
+// Type implements pflag.Value by returning "uuid".
+func (u *UUIDValue) Type() string {
+	return "uuid"
+}
+
+// isUUID reports whether the given string is a UUID in canonical form, with
+// lowercase hexadecimal digits.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !('0' <= s[i] && s[i] <= '9' || 'a' <= s[i] && s[i] <= 'f') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package flagutils_test
+
+import (
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+)
+
+var _ flag.Value = (*flagutils.UUIDValue)(nil)
+
+var uuidTests = []struct {
+	about         string
+	name          string
+	value         string
+	lenient       bool
+	defaultValue  string
+	expectedValue string
+	expectedError string
+}{{
+	about:         "canonical form",
+	name:          "canonical",
+	value:         "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+	expectedValue: "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+}, {
+	about:         "lenient: canonical form",
+	name:          "lenient",
+	value:         "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+	lenient:       true,
+	expectedValue: "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+}, {
+	about:         "lenient: uppercase",
+	name:          "upper",
+	value:         "F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6",
+	lenient:       true,
+	expectedValue: "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+}, {
+	about:         "lenient: braces",
+	name:          "braces",
+	value:         "{f81d4fae-7dec-11d0-a765-00a0c91e6bf6}",
+	lenient:       true,
+	expectedValue: "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+}, {
+	about:         "lenient: URN",
+	name:          "urn",
+	value:         "urn:uuid:F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6",
+	lenient:       true,
+	expectedValue: "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+}, {
+	about:         "default value: with value",
+	name:          "def1",
+	value:         "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+	defaultValue:  "00000000-0000-0000-0000-000000000000",
+	expectedValue: "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+}, {
+	about:         "default value: without value",
+	name:          "def2",
+	defaultValue:  "00000000-0000-0000-0000-000000000000",
+	expectedValue: "00000000-0000-0000-0000-000000000000",
+}, {
+	about:         "error: empty string",
+	name:          "err",
+	expectedError: `invalid UUID ""`,
+}, {
+	about:         "error: uppercase",
+	name:          "err",
+	value:         "F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6",
+	expectedError: `invalid UUID "F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6"`,
+}, {
+	about:         "error: braces",
+	name:          "err",
+	value:         "{f81d4fae-7dec-11d0-a765-00a0c91e6bf6}",
+	expectedError: `invalid UUID "{f81d4fae-7dec-11d0-a765-00a0c91e6bf6}"`,
+}, {
+	about:         "error: missing hyphens",
+	name:          "err",
+	value:         "f81d4fae7dec11d0a76500a0c91e6bf6",
+	lenient:       true,
+	expectedError: `invalid UUID "f81d4fae7dec11d0a76500a0c91e6bf6"`,
+}, {
+	about:         "error: invalid digit",
+	name:          "err",
+	value:         "g81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+	lenient:       true,
+	expectedError: `invalid UUID "g81d4fae-7dec-11d0-a765-00a0c91e6bf6"`,
+}, {
+	about:         "error: default value preserved",
+	name:          "err",
+	value:         "bad-wolf",
+	defaultValue:  "00000000-0000-0000-0000-000000000000",
+	expectedValue: "00000000-0000-0000-0000-000000000000",
+	expectedError: `invalid UUID "bad-wolf"`,
+}}
+
+func TestUUID(t *testing.T) {
+	for _, test := range uuidTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := flagutils.UUID(test.name, test.defaultValue, test.lenient, "UUID usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.Equals, test.expectedValue)
+		})
+	}
+}
+
+func TestUUIDVar(t *testing.T) {
+	for _, test := range uuidTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v string
+			flagutils.UUIDVar(&v, test.name, test.defaultValue, test.lenient, "UUID usage")
+			if test.value != "" || test.defaultValue == "" {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.Equals, test.expectedValue)
+		})
+	}
+}
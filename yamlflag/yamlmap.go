@@ -0,0 +1,82 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+// Package yamlflag provides command line flags holding YAML encoded maps. It
+// is kept separate from the flagutils package so that programs not using
+// these flags do not depend on a YAML library.
+package yamlflag
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/frankban/flagutils"
+	"github.com/frankban/flagutils/internal/mapcheck"
+)
+
+// YAMLMap defines a flag containing a map of strings with specified name,
+// default value, and usage string. The value is provided via the command line
+// as a YAML encoded string. The return value is the address of a StringMap
+// variable that stores the value of the flag.
+func YAMLMap(name string, value map[string]interface{}, usage string, opts ...flagutils.Option) *flagutils.StringMap {
+	var s flagutils.StringMap
+	YAMLMapVar(&s, name, value, usage, opts...)
+	return &s
+}
+
+// YAMLMapVar defines a flag containing a map of strings with specified name,
+// default value, and usage string. The value is provided via the command line
+// as a YAML encoded string. The argument p points to a StringMap variable in
+// which to store the value of the flag.
+func YAMLMapVar(p *flagutils.StringMap, name string, value map[string]interface{}, usage string, opts ...flagutils.Option) {
+	YAMLMapVarFS(flag.CommandLine, p, name, value, usage, opts...)
+}
+
+// YAMLMapVarFS is like YAMLMapVar, but defines the flag in the given flag set
+// rather than in the default command line flag set.
+func YAMLMapVarFS(fs *flag.FlagSet, p *flagutils.StringMap, name string, value map[string]interface{}, usage string, opts ...flagutils.Option) {
+	*p = value
+	flagutils.VarFS(fs, (*YAMLMapValue)(p), name, usage, opts...)
+}
+
+// YAMLMapValue holds a map of strings to empty interfaces that can be
+// provided via the command line as a YAML encoded string.
+type YAMLMapValue map[string]interface{}
+
+// String implements flag.Value by returning the map as a string. The map is
+// encoded as JSON, which is also valid YAML in flow style.
+func (s *YAMLMapValue) String() string {
+	return (*flagutils.StringMap)(s).String()
+}
+
+// Set implements flag.Value by unmarshaling the YAML encoded value into the
+// string map. Both block and flow styles are supported. When providing a
+// single line flow mapping, the enclosing braces can be omitted, so that
+// "key: value, answer: 42" is a valid value. NaN and infinite numbers are
+// rejected, as they cannot be represented in the JSON encoded string value.
+func (s *YAMLMapValue) Set(value string) error {
+	*s = nil
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "{") && !strings.Contains(value, "\n") {
+		value = "{" + value + "}"
+	}
+	var m map[string]interface{}
+	if err := yaml.Unmarshal([]byte(value), &m); err != nil {
+		return fmt.Errorf("cannot unmarshal YAML: %v", err)
+	}
+	if err := mapcheck.Finite(m); err != nil {
+		return err
+	}
+	if m == nil {
+		m = make(map[string]interface{})
+	}
+	*s = m
+	return nil
+}
+
+// Type implements pflag.Value by returning "yamlMap".
+func (s *YAMLMapValue) Type() string {
+	return "yamlMap"
+}
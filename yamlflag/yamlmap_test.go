@@ -0,0 +1,191 @@
+// Licensed under the MIT license, see LICENCE file for details.
+
+package yamlflag_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/frankban/flagutils"
+	"github.com/frankban/flagutils/yamlflag"
+)
+
+var _ flag.Value = (*yamlflag.YAMLMapValue)(nil)
+
+var yamlMapTests = []struct {
+	about               string
+	name                string
+	value               string
+	defaultValue        map[string]interface{}
+	expectedValue       flagutils.StringMap
+	expectedStringValue string
+	expectedError       string
+}{{
+	about: "flow mapping",
+	name:  "flow",
+	value: `{gisf: true, url: "https://1.2.3.4"}`,
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+		"url":  "https://1.2.3.4",
+	},
+	expectedStringValue: `{"gisf":true,"url":"https://1.2.3.4"}`,
+}, {
+	about: "block mapping",
+	name:  "block",
+	value: `
+gisf: true
+answer: 42
+flags:
+  profile: true
+  status: [ok, ko]
+`,
+	expectedValue: flagutils.StringMap{
+		"gisf":   true,
+		"answer": 42,
+		"flags": map[string]interface{}{
+			"profile": true,
+			"status":  []interface{}{"ok", "ko"},
+		},
+	},
+	expectedStringValue: `{"answer":42,"flags":{"profile":true,"status":["ok","ko"]},"gisf":true}`,
+}, {
+	about: "no braces",
+	name:  "nobraces",
+	value: ` gisf: true, flags: {profile: yes} `,
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+		"flags": map[string]interface{}{
+			"profile": "yes",
+		},
+	},
+	expectedStringValue: `{"flags":{"profile":"yes"},"gisf":true}`,
+}, {
+	about:               "empty string",
+	name:                "empty",
+	expectedValue:       flagutils.StringMap{},
+	expectedStringValue: "{}",
+}, {
+	about: "default value: with value",
+	name:  "def1",
+	value: "gisf: true",
+	defaultValue: map[string]interface{}{
+		"answer": 42,
+	},
+	expectedValue: flagutils.StringMap{
+		"gisf": true,
+	},
+}, {
+	about: "default value: without value",
+	name:  "def2",
+	defaultValue: map[string]interface{}{
+		"answer": 42,
+	},
+	expectedValue: flagutils.StringMap{
+		"answer": 42,
+	},
+}, {
+	about:               "error: not a map",
+	name:                "err",
+	value:               "- a\n- b\n",
+	expectedStringValue: "null",
+	expectedError:       "cannot unmarshal YAML: yaml: unmarshal errors:\n.*",
+}, {
+	about:               "error: infinity",
+	name:                "err",
+	value:               "a: .inf",
+	expectedStringValue: "null",
+	expectedError:       `invalid value at "a": unsupported number \+Inf: NaN and infinity are not allowed`,
+}, {
+	about:               "error: nested negative infinity",
+	name:                "err",
+	value:               "flags: {limit: -.inf}",
+	expectedStringValue: "null",
+	expectedError:       `invalid value at "flags.limit": unsupported number -Inf: NaN and infinity are not allowed`,
+}, {
+	about:               "error: NaN in a sequence",
+	name:                "err",
+	value:               "ratios: [0.5, .nan]",
+	expectedStringValue: "null",
+	expectedError:       `invalid value at "ratios.1": unsupported number NaN: NaN and infinity are not allowed`,
+}, {
+	about:               "error: invalid YAML",
+	name:                "err",
+	value:               "gisf: [true",
+	expectedStringValue: "null",
+	expectedError:       "cannot unmarshal YAML: yaml: .*",
+}}
+
+func TestYAMLMap(t *testing.T) {
+	for _, test := range yamlMapTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			v := yamlflag.YAMLMap(test.name, test.defaultValue, "YAML map usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(*v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestYAMLMapVar(t *testing.T) {
+	for _, test := range yamlMapTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			var v flagutils.StringMap
+			yamlflag.YAMLMapVar(&v, test.name, test.defaultValue, "YAML map usage")
+			if test.value != "" || test.defaultValue == nil {
+				err := flag.Set(test.name, test.value)
+				if test.expectedError == "" {
+					c.Assert(err, qt.Equals, nil)
+				} else {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				}
+			}
+			c.Assert(v, qt.DeepEquals, test.expectedValue)
+		})
+	}
+}
+
+func TestYAMLMapValueString(t *testing.T) {
+	for _, test := range yamlMapTests {
+		runIsolated(t, test.about, func(c *qt.C) {
+			if test.defaultValue != nil {
+				return
+			}
+			var v yamlflag.YAMLMapValue
+			c.Assert(v.Type(), qt.Equals, "yamlMap")
+			v.Set(test.value)
+			c.Assert(v.String(), qt.Equals, test.expectedStringValue)
+		})
+	}
+}
+
+func TestYAMLMapWithOptions(t *testing.T) {
+	t.Setenv("FLAGUTILS_CONF", "answer: 42")
+	c := qt.New(t)
+	fs := flagutils.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	var conf flagutils.StringMap
+	yamlflag.YAMLMapVarFS(fs.FlagSet, &conf, "conf", nil, "the configuration", flagutils.Env("FLAGUTILS_CONF"))
+	c.Assert(fs.Lookup("conf").Usage, qt.Equals, "the configuration (env FLAGUTILS_CONF)")
+	err := fs.Parse(nil)
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(conf, qt.DeepEquals, flagutils.StringMap{"answer": 42})
+}
+
+// runIsolated runs the given test function without clobbering global flags.
+func runIsolated(t *testing.T, name string, f func(c *qt.C)) {
+	original := flag.CommandLine
+	flag.CommandLine = flag.NewFlagSet("", flag.ContinueOnError)
+	defer func() {
+		flag.CommandLine = original
+	}()
+	qt.New(t).Run(name, f)
+}